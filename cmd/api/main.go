@@ -13,22 +13,31 @@ import (
 	"github.com/hadi-projects/xyz-finance-go/config"
 	"github.com/hadi-projects/xyz-finance-go/internal/entity"
 	"github.com/hadi-projects/xyz-finance-go/internal/handler"
+	"github.com/hadi-projects/xyz-finance-go/internal/middleware"
 	"github.com/hadi-projects/xyz-finance-go/internal/repository"
 	"github.com/hadi-projects/xyz-finance-go/internal/router"
 	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/async"
 	"github.com/hadi-projects/xyz-finance-go/pkg/cache"
 	"github.com/hadi-projects/xyz-finance-go/pkg/database"
+	"github.com/hadi-projects/xyz-finance-go/pkg/encryption"
+	"github.com/hadi-projects/xyz-finance-go/pkg/jwtkeys"
 	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+	"github.com/hadi-projects/xyz-finance-go/pkg/storage"
 	"gorm.io/gorm"
 )
 
 type Application struct {
-	Config    *config.AppConfig
-	DB        *gorm.DB
-	Redis     *cache.RedisClient
-	PermCache *cache.PermissionCache
-	Router    *gin.Engine
-	Server    *http.Server
+	Config         *config.AppConfig
+	Keys           *encryption.KeyRing
+	JWTKeys        *jwtkeys.KeySet
+	DB             *gorm.DB
+	Redis          *cache.RedisClient
+	PermCache      *cache.PermissionCache
+	Router         *gin.Engine
+	Server         *http.Server
+	OverdueService services.OverdueService
+	stopJobs       context.CancelFunc
 }
 
 func main() {
@@ -42,8 +51,10 @@ func main() {
 	})
 	logger.SystemLogger.Info().Msg("Logger initialized")
 
+	app.initializeEncryption()
 	app.initializeDatabase()
 	app.setupRouter()
+	app.startJobs()
 	app.run()
 }
 
@@ -58,6 +69,24 @@ func (app *Application) initializeConfig() {
 	log.Println("Configuration loaded successfully")
 }
 
+// initializeEncryption sets up the key ring consumer PII is encrypted with and the keys access
+// tokens are signed with
+func (app *Application) initializeEncryption() {
+	keys, err := encryption.NewKeyRing(app.Config.Encryption)
+	if err != nil {
+		logger.SystemLogger.Fatal().Err(err).Msg("Failed to initialize encryption keys")
+	}
+	encryption.SetKeyRing(keys)
+	app.Keys = keys
+
+	jwtKeys, err := jwtkeys.NewKeySet(app.Config.JWT)
+	if err != nil {
+		logger.SystemLogger.Fatal().Err(err).Msg("Failed to initialize JWT signing keys")
+	}
+	app.JWTKeys = jwtKeys
+	logger.SystemLogger.Info().Str("kid", jwtKeys.ActiveKeyID()).Msg("JWT signing keys loaded")
+}
+
 // initializeDatabase connects to the database and runs migrations
 func (app *Application) initializeDatabase() {
 	db, err := database.NewMySQLConnection(app.Config)
@@ -77,15 +106,36 @@ func (app *Application) initializeDatabase() {
 		&entity.Consumer{},
 		&entity.Transaction{},
 		&entity.LimitMutation{},
+		&entity.Installment{},
+		&entity.Payment{},
+		&entity.PaymentAllocation{},
+		&entity.IdempotencyKey{},
+		&entity.ContractSequence{},
+		&entity.Penalty{},
 	); err != nil {
 		logger.SystemLogger.Fatal().Err(err).Msg("Failed to migrate database")
 	}
+	// The NIK is encrypted now; uniqueness is enforced on its blind index (nik_hash) instead.
+	// Consumers stored before that get their blind index first, so none is left unprotected.
+	backfill := services.NewKeyRotationService(repository.NewConsumerRepository(db, app.Keys), app.Keys.ActiveKeyID())
+	backfilled, err := backfill.BackfillNIKHashes(100)
+	if err != nil {
+		logger.SystemLogger.Fatal().Err(err).Int("backfilled", backfilled).Msg("Failed to backfill NIK blind index")
+	}
+	if backfilled > 0 {
+		logger.SystemLogger.Info().Int("backfilled", backfilled).Msg("NIK blind index backfilled")
+	}
+	if db.Migrator().HasIndex(&entity.Consumer{}, "idx_consumers_nik") {
+		if err := db.Migrator().DropIndex(&entity.Consumer{}, "idx_consumers_nik"); err != nil {
+			logger.SystemLogger.Fatal().Err(err).Msg("Failed to drop the plaintext NIK index")
+		}
+	}
 	logger.SystemLogger.Info().Msg("Database migration completed successfully")
 
 	database.SeedRBAC(app.DB)
 	database.SeedUser(app.DB, app.Config.Security.BCryptCost)
 	database.SeedConsumerLimit(app.DB)
-	database.SeedConsumer(app.DB)
+	database.SeedConsumer(app.DB, app.Keys)
 
 }
 
@@ -100,31 +150,93 @@ func (app *Application) setupRouter() {
 		app.PermCache = cache.NewPermissionCache(redisClient)
 	}
 
+	// Revoked access tokens live in Redis when available, process memory otherwise
+	var tokenRevocations middleware.TokenRevocationStore
+	if app.Redis != nil {
+		tokenRevocations = middleware.NewRedisTokenRevocationStore(app.Redis)
+	} else {
+		logger.SystemLogger.Warn().Msg("Access token revocations are kept in memory and not shared between instances")
+		tokenRevocations = middleware.NewMemoryTokenRevocationStore()
+	}
+
 	userRepo := repository.NewUserRepository(app.DB)
 	authService := services.NewAuthService(userRepo, app.Config)
 	refreshTokenRepo := repository.NewRefreshTokenRepository(app.DB)
-	jwtService := services.NewJWTService(app.Config.JWT.Secret, app.Config.JWT.ExpiryHours, refreshTokenRepo)
-	authHandler := handler.NewAuthHandler(authService, jwtService)
+	jwtService := services.NewJWTService(app.JWTKeys, app.Config.JWT.ExpiryHours, refreshTokenRepo, tokenRevocations)
+
+	// Failed login counters live in Redis when available, process memory otherwise
+	var loginAttempts services.LoginAttemptStore
+	if app.Redis != nil {
+		loginAttempts = services.NewRedisLoginAttemptStore(app.Redis)
+	} else {
+		loginAttempts = services.NewMemoryLoginAttemptStore()
+	}
+	loginGuard := services.NewLoginGuard(loginAttempts, app.Config.LoginProtection)
+	authHandler := handler.NewAuthHandler(authService, jwtService, loginGuard)
 
 	limitRepo := repository.NewLimitRepository(app.DB)
 	mutationRepo := repository.NewLimitMutationRepository(app.DB)
-	limitService := services.NewLimitService(limitRepo, userRepo, mutationRepo, app.DB)
+	transactionRepo := repository.NewTransactionRepository(app.DB)
+	limitService := services.NewLimitService(limitRepo, userRepo, mutationRepo, transactionRepo, app.DB)
 	limitHandler := handler.NewLimitHandler(limitService)
-	userHandler := handler.NewUserHandler(userRepo)
+	consumerRepo := repository.NewConsumerRepository(app.DB, app.Keys)
+	store, err := storage.New(app.Config.Storage)
+	if err != nil {
+		logger.SystemLogger.Fatal().Err(err).Msg("Failed to initialize file storage")
+	}
+	documentService := services.NewDocumentService(userRepo, consumerRepo, store, app.Config.KYC)
+	collectibility := services.NewCollectibilityClassifier(app.Config.Collectibility)
+	userService := services.NewUserService(userRepo, repository.NewRoleRepository(app.DB), app.PermCache, loginGuard)
+	userHandler := handler.NewUserHandler(userRepo, transactionRepo, collectibility, documentService, userService, jwtService)
 
-	transactionRepo := repository.NewTransactionRepository(app.DB)
-	transactionService := services.NewTransactionService(transactionRepo, limitRepo, mutationRepo, userRepo, app.DB)
+	installmentRepo := repository.NewInstallmentRepository(app.DB)
+	contractNumbers := services.NewContractNumberGenerator(app.Config.ContractNumber, repository.NewContractSequenceRepository(app.DB))
+	transactionService := services.NewTransactionService(transactionRepo, limitRepo, mutationRepo, userRepo, installmentRepo, contractNumbers, services.NewPricingEngine(app.Config.Pricing), collectibility, app.DB)
 	transactionHandler := handler.NewTransactionHandler(transactionService)
 
+	paymentRepo := repository.NewPaymentRepository(app.DB)
+	paymentService := services.NewPaymentService(paymentRepo, transactionRepo, installmentRepo, limitRepo, mutationRepo, userRepo, app.Config.Settlement, app.DB)
+	paymentHandler := handler.NewPaymentHandler(paymentService)
+
+	app.OverdueService = services.NewOverdueService(transactionRepo, installmentRepo, paymentRepo, repository.NewPenaltyRepository(app.DB), app.Config.Overdue, app.DB)
+
+	consumerService := services.NewConsumerService(consumerRepo, store, app.Config.KYC)
+	consumerHandler := handler.NewConsumerHandler(consumerService, documentService)
+
 	logService := services.NewLogService("storage/logs")
 	logHandler := handler.NewLogHandler(logService)
+	jwksHandler := handler.NewJWKSHandler(app.JWTKeys)
+
+	// Idempotency records live in Redis when available, the database otherwise
+	var idempotencyStore middleware.IdempotencyStore
+	if app.Redis != nil {
+		idempotencyStore = middleware.NewRedisIdempotencyStore(app.Redis)
+	} else {
+		idempotencyStore = middleware.NewDBIdempotencyStore(repository.NewIdempotencyKeyRepository(app.DB))
+	}
 
-	appRouter := router.NewRouter(app.Config, authHandler, limitHandler, userHandler, transactionHandler, logHandler, userRepo, app.PermCache)
+	appRouter := router.NewRouter(app.Config, authHandler, limitHandler, userHandler, transactionHandler, paymentHandler, consumerHandler, logHandler, jwksHandler, app.JWTKeys, userRepo, app.PermCache, idempotencyStore, tokenRevocations)
 	app.Router = appRouter.SetupRoutes()
 
 	logger.SystemLogger.Info().Msg("Router configured successfully")
 }
 
+// startJobs launches the background jobs; they are stopped on shutdown
+func (app *Application) startJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	app.stopJobs = cancel
+
+	if app.Config.Overdue.JobEnabled {
+		at, _ := time.Parse("15:04", app.Config.Overdue.JobTime) // validated by config.NewConfig
+		go async.RunDaily(ctx, at.Hour(), at.Minute(), func(ctx context.Context, now time.Time) {
+			if _, err := app.OverdueService.Run(ctx, now); err != nil {
+				logger.SystemLogger.Error().Err(err).Msg("Overdue job failed")
+			}
+		})
+		logger.SystemLogger.Info().Str("time", app.Config.Overdue.JobTime).Msg("Overdue job scheduled")
+	}
+}
+
 // run starts the HTTP server and handles graceful shutdown
 func (app *Application) run() {
 	app.Server = &http.Server{
@@ -149,6 +261,7 @@ func (app *Application) run() {
 	<-quit
 
 	logger.SystemLogger.Info().Msg("Shutting down server...")
+	app.stopJobs()
 
 	// Graceful shutdown with 5 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
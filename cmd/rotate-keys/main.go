@@ -0,0 +1,55 @@
+// Command rotate-keys re-encrypts consumer PII with the active encryption key.
+//
+// Rotating a key: add the new key to ENCRYPTION_KEYS and make it ENCRYPTION_ACTIVE_KEY, restart
+// the API so new writes use it, run this command, and only then remove the old key. The command
+// also encrypts rows written before encryption was enabled. Run it with -all after changing
+// BLIND_INDEX_KEY so every NIK index is recomputed.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/database"
+	"github.com/hadi-projects/xyz-finance-go/pkg/encryption"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+)
+
+func main() {
+	all := flag.Bool("all", false, "rewrite every consumer, not only those written with an older key")
+	batchSize := flag.Int("batch", 100, "consumers loaded per query")
+	flag.Parse()
+	if *batchSize <= 0 {
+		log.Fatal("-batch must be positive")
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	logger.Init(logger.Config{
+		LogDir:      "storage/logs",
+		Environment: cfg.AppEnv,
+	})
+
+	keys, err := encryption.NewKeyRing(cfg.Encryption)
+	if err != nil {
+		logger.SystemLogger.Fatal().Err(err).Msg("Failed to initialize encryption keys")
+	}
+	encryption.SetKeyRing(keys)
+
+	db, err := database.NewMySQLConnection(cfg)
+	if err != nil {
+		logger.SystemLogger.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+
+	rotation := services.NewKeyRotationService(repository.NewConsumerRepository(db, keys), keys.ActiveKeyID())
+	rotated, err := rotation.RotateConsumers(*all, *batchSize)
+	if err != nil {
+		logger.SystemLogger.Fatal().Err(err).Int("rotated", rotated).Msg("Consumer key rotation failed")
+	}
+	logger.SystemLogger.Info().Int("rotated", rotated).Str("key_id", keys.ActiveKeyID()).Msg("Consumer key rotation completed")
+}
@@ -1,11 +1,17 @@
 package config
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
 	"github.com/joho/godotenv"
 )
 
@@ -20,6 +26,24 @@ type AppConfig struct {
 	Security   SecurityConfig
 	JWT        JWTConfig
 	Redis      RedisConfig
+	// ContractNumber configures server-side contract number generation
+	ContractNumber ContractNumberConfig
+	// Pricing holds the interest and admin fee configuration per product
+	Pricing PricingConfig
+	// Settlement configures early settlement (pelunasan dipercepat)
+	Settlement SettlementConfig
+	// Overdue configures the daily overdue job and late payment penalties
+	Overdue OverdueConfig
+	// Collectibility configures which collectibility grades may take new financing
+	Collectibility CollectibilityConfig
+	// KYC configures consumer onboarding uploads
+	KYC KYCConfig
+	// Storage selects where uploaded files are kept
+	Storage StorageConfig
+	// Encryption holds the keys consumer PII is encrypted with at rest
+	Encryption EncryptionConfig
+	// LoginProtection throttles and locks out repeated failed logins
+	LoginProtection LoginProtectionConfig
 }
 
 type SecurityConfig struct {
@@ -30,13 +54,142 @@ type SecurityConfig struct {
 	RequestTimeout       int
 	APIKey               string
 	BCryptCost           int
+	IdempotencyTTLHours  int
 }
 
+// JWTConfig holds the keys access tokens are signed with. Each key is identified by its ID (the
+// token's kid header) and is a PEM encoded RSA or Ed25519 key; a public key only verifies tokens
+// signed before it was rotated out. New tokens are signed with ActiveKeyID.
 type JWTConfig struct {
-	Secret      string
+	SigningKeys []JWTSigningKey
+	ActiveKeyID string
 	ExpiryHours int
 }
 
+// LoginProtectionConfig limits password guessing. Failed logins are counted per account and per
+// client IP over FailureWindowMinutes; reaching MaxAccountFailures or MaxIPFailures locks the
+// account or IP for LockoutMinutes. Before each attempt on an account with earlier failures the
+// response is delayed by DelayBaseMillis, doubling with every failure up to DelayMaxMillis.
+type LoginProtectionConfig struct {
+	MaxAccountFailures   int
+	MaxIPFailures        int
+	FailureWindowMinutes int
+	LockoutMinutes       int
+	DelayBaseMillis      int
+	DelayMaxMillis       int
+}
+
+type JWTSigningKey struct {
+	ID  string
+	PEM []byte
+}
+
+// ContractNumberConfig describes the contract number layout. Format may use the placeholders
+// {PREFIX}, {BRANCH}, {DATE} (DateFormat, a Go time layout), {SEQ} (zero-padded to SequenceDigits)
+// and {CHECK} (Luhn check digit over the other digits). The sequence restarts for every
+// prefix/branch/date combination, so all of these but {CHECK} are required.
+type ContractNumberConfig struct {
+	Prefix         string
+	Branch         string
+	DateFormat     string
+	SequenceDigits int
+	Format         string
+}
+
+// Validate checks that Format renders every part the sequence is scoped by: a format without
+// {DATE}, {PREFIX} or {BRANCH} would repeat numbers when the sequence restarts for a new date or
+// runs separately per prefix or branch.
+func (c ContractNumberConfig) Validate() error {
+	for _, placeholder := range []string{"{PREFIX}", "{BRANCH}", "{DATE}", "{SEQ}"} {
+		if !strings.Contains(c.Format, placeholder) {
+			return fmt.Errorf("CONTRACT_NUMBER_FORMAT must contain %s", placeholder)
+		}
+	}
+	return nil
+}
+
+// PricingConfig lists the financing products. Requests without a product use DefaultProduct.
+type PricingConfig struct {
+	DefaultProduct string
+	Products       map[string]ProductPricing
+}
+
+// ProductPricing is the rate configuration of one product.
+// Method is "flat" (interest on the original OTR) or "effective" (annuity on the declining balance).
+// The admin fee is AdminFeeFlat plus AdminFeeRate percent of the OTR.
+type ProductPricing struct {
+	Method       string      `json:"method"`
+	AnnualRate   float64     `json:"annual_rate"` // percent per year
+	AdminFeeFlat money.Money `json:"admin_fee_flat"`
+	AdminFeeRate float64     `json:"admin_fee_rate"` // percent of OTR
+}
+
+// defaultPricingProducts is used when PRICING_PRODUCTS is not set
+const defaultPricingProducts = `{
+	"standard": {"method": "flat", "annual_rate": 12, "admin_fee_flat": 10000},
+	"effective": {"method": "effective", "annual_rate": 18, "admin_fee_rate": 1}
+}`
+
+// SettlementConfig holds the early settlement charges.
+// EarlyTerminationFeeRate is a percentage of the remaining principal; 0 disables the fee.
+type SettlementConfig struct {
+	EarlyTerminationFeeRate float64
+}
+
+// OverdueConfig configures the daily overdue job. Installments past their due date are marked
+// overdue; once an installment is more than GracePeriodDays past due, a late fee of
+// LateFeeDailyRate percent of its unpaid amount accrues per day, up to LateFeeCapRate percent of
+// the installment amount (0 = no cap). The job runs every day at JobTime (HH:MM, server time).
+type OverdueConfig struct {
+	JobEnabled       bool
+	JobTime          string
+	JobWorkers       int
+	GracePeriodDays  int
+	LateFeeDailyRate float64
+	LateFeeCapRate   float64
+}
+
+// CollectibilityConfig holds the collectibility (kolektibilitas) policy. Consumers whose worst
+// contract grade is above MaxGradeForNewTransaction (1-5) cannot create new transactions.
+type CollectibilityConfig struct {
+	MaxGradeForNewTransaction int
+}
+
+// KYCConfig holds the KTP and selfie upload settings. Images are stored under the ktp and selfie
+// prefixes of the configured storage and may be at most MaxImageSizeMB megabytes. They are only
+// served through URLs signed with URLSecret (HMAC-SHA256) that expire after URLTTLMinutes.
+// Links are built on PublicBaseURL (scheme and host the API is reached at), or are relative
+// paths when it is empty; the request's Host header is never used.
+type KYCConfig struct {
+	MaxImageSizeMB int
+	URLSecret      string
+	URLTTLMinutes  int
+	PublicBaseURL  string
+}
+
+// StorageConfig selects the file storage backend. Driver "local" keeps files in LocalDir; "s3"
+// uses a bucket of any S3-compatible store (AWS S3, MinIO, ...) so replicas need no shared disk.
+// S3PathStyle addresses objects as endpoint/bucket/key, as MinIO expects, instead of bucket.endpoint/key.
+type StorageConfig struct {
+	Driver      string
+	LocalDir    string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool
+}
+
+// EncryptionConfig holds the 32-byte key-encryption keys by ID. New values are encrypted with
+// ActiveKeyID; the other keys only decrypt rows that have not been rotated yet. BlindIndexKey
+// keys the hash NIK lookups and uniqueness are checked on.
+type EncryptionConfig struct {
+	Keys          map[string][]byte
+	ActiveKeyID   string
+	BlindIndexKey []byte
+}
+
 type RedisConfig struct {
 	Host     string
 	Port     string
@@ -64,9 +217,9 @@ func NewConfig() (*AppConfig, error) {
 			RequestTimeout:       getEnvAsInt("REQUEST_TIMEOUT", 10),
 			APIKey:               getEnv("API_KEY", ""),
 			BCryptCost:           getEnvAsInt("BCRYPT_COST", 10),
+			IdempotencyTTLHours:  getEnvAsInt("IDEMPOTENCY_TTL_HOURS", 24),
 		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", ""),
 			ExpiryHours: getEnvAsInt("JWT_EXPIRY_HOURS", 1),
 		},
 		Redis: RedisConfig{
@@ -75,15 +228,196 @@ func NewConfig() (*AppConfig, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
+		ContractNumber: ContractNumberConfig{
+			Prefix:         getEnv("CONTRACT_NUMBER_PREFIX", "XYZ"),
+			Branch:         getEnv("CONTRACT_NUMBER_BRANCH", "001"),
+			DateFormat:     getEnv("CONTRACT_NUMBER_DATE_FORMAT", "200601"),
+			SequenceDigits: getEnvAsInt("CONTRACT_NUMBER_SEQUENCE_DIGITS", 6),
+			Format:         getEnv("CONTRACT_NUMBER_FORMAT", "{PREFIX}-{BRANCH}-{DATE}-{SEQ}{CHECK}"),
+		},
+		Pricing: PricingConfig{
+			DefaultProduct: getEnv("PRICING_DEFAULT_PRODUCT", "standard"),
+		},
+		Settlement: SettlementConfig{
+			EarlyTerminationFeeRate: getEnvAsFloat("EARLY_TERMINATION_FEE_RATE", 0),
+		},
+		Overdue: OverdueConfig{
+			JobEnabled:       getEnvAsBool("OVERDUE_JOB_ENABLED", true),
+			JobTime:          getEnv("OVERDUE_JOB_TIME", "00:30"),
+			JobWorkers:       getEnvAsInt("OVERDUE_JOB_WORKERS", 4),
+			GracePeriodDays:  getEnvAsInt("LATE_FEE_GRACE_PERIOD_DAYS", 3),
+			LateFeeDailyRate: getEnvAsFloat("LATE_FEE_DAILY_RATE", 0.1),
+			LateFeeCapRate:   getEnvAsFloat("LATE_FEE_CAP_RATE", 100),
+		},
+		Collectibility: CollectibilityConfig{
+			MaxGradeForNewTransaction: getEnvAsInt("COLLECTIBILITY_MAX_GRADE", 2),
+		},
+		KYC: KYCConfig{
+			MaxImageSizeMB: getEnvAsInt("KYC_MAX_IMAGE_SIZE_MB", 2),
+			URLSecret:      getEnv("KYC_URL_SECRET", ""),
+			URLTTLMinutes:  getEnvAsInt("KYC_URL_TTL_MINUTES", 5),
+			PublicBaseURL:  strings.TrimRight(getEnv("KYC_PUBLIC_BASE_URL", ""), "/"),
+		},
+		Storage: StorageConfig{
+			Driver:      getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:    getEnv("STORAGE_LOCAL_DIR", "storage/uploads"),
+			S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+			S3Region:    getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("S3_BUCKET", ""),
+			S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+			S3PathStyle: getEnvAsBool("S3_PATH_STYLE", true),
+		},
+		LoginProtection: LoginProtectionConfig{
+			MaxAccountFailures:   getEnvAsInt("LOGIN_MAX_ACCOUNT_FAILURES", 5),
+			MaxIPFailures:        getEnvAsInt("LOGIN_MAX_IP_FAILURES", 20),
+			FailureWindowMinutes: getEnvAsInt("LOGIN_FAILURE_WINDOW_MINUTES", 15),
+			LockoutMinutes:       getEnvAsInt("LOGIN_LOCKOUT_MINUTES", 15),
+			DelayBaseMillis:      getEnvAsInt("LOGIN_DELAY_BASE_MS", 250),
+			DelayMaxMillis:       getEnvAsInt("LOGIN_DELAY_MAX_MS", 4000),
+		},
+	}
+
+	pricingProducts := getEnv("PRICING_PRODUCTS", "")
+	if pricingProducts == "" {
+		pricingProducts = defaultPricingProducts
+	}
+	if err := json.Unmarshal([]byte(pricingProducts), &cfg.Pricing.Products); err != nil {
+		return nil, fmt.Errorf("invalid PRICING_PRODUCTS: %w", err)
+	}
+	if _, ok := cfg.Pricing.Products[cfg.Pricing.DefaultProduct]; !ok {
+		return nil, fmt.Errorf("pricing default product %q is not configured", cfg.Pricing.DefaultProduct)
+	}
+
+	if cfg.Settlement.EarlyTerminationFeeRate < 0 {
+		return nil, errors.New("early termination fee rate must not be negative")
+	}
+
+	if _, err := time.Parse("15:04", cfg.Overdue.JobTime); err != nil {
+		return nil, fmt.Errorf("invalid OVERDUE_JOB_TIME %q, expected HH:MM", cfg.Overdue.JobTime)
+	}
+	if cfg.Overdue.JobWorkers <= 0 || cfg.Overdue.GracePeriodDays < 0 || cfg.Overdue.LateFeeDailyRate < 0 || cfg.Overdue.LateFeeCapRate < 0 {
+		return nil, errors.New("overdue job workers must be positive and grace period and late fee rates must not be negative")
+	}
+
+	if cfg.Collectibility.MaxGradeForNewTransaction < 1 || cfg.Collectibility.MaxGradeForNewTransaction > 5 {
+		return nil, errors.New("collectibility max grade must be between 1 and 5")
+	}
+
+	if cfg.KYC.MaxImageSizeMB <= 0 || cfg.KYC.URLTTLMinutes <= 0 {
+		return nil, errors.New("kyc max image size and url ttl must be positive")
+	}
+	if cfg.KYC.URLSecret == "" {
+		return nil, errors.New("KYC_URL_SECRET is required")
+	}
+	if cfg.KYC.PublicBaseURL != "" {
+		base, err := url.Parse(cfg.KYC.PublicBaseURL)
+		if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" || base.RawQuery != "" || base.Fragment != "" {
+			return nil, errors.New("KYC_PUBLIC_BASE_URL must be an http(s) URL such as https://api.example.com")
+		}
+	}
+
+	switch cfg.Storage.Driver {
+	case "local":
+	case "s3":
+		if cfg.Storage.S3Endpoint == "" || cfg.Storage.S3Bucket == "" || cfg.Storage.S3AccessKey == "" || cfg.Storage.S3SecretKey == "" {
+			return nil, errors.New("S3 storage requires S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY")
+		}
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q, expected local or s3", cfg.Storage.Driver)
+	}
+
+	login := cfg.LoginProtection
+	if login.MaxAccountFailures <= 0 || login.MaxIPFailures <= 0 || login.FailureWindowMinutes <= 0 || login.LockoutMinutes <= 0 {
+		return nil, errors.New("login failure limits, window and lockout must be positive")
+	}
+	if login.DelayBaseMillis < 0 || login.DelayMaxMillis < login.DelayBaseMillis {
+		return nil, errors.New("login delay must not be negative and the maximum must not be below the base")
+	}
+
+	if err := loadEncryptionConfig(&cfg.Encryption); err != nil {
+		return nil, err
+	}
+
+	if err := loadJWTConfig(&cfg.JWT); err != nil {
+		return nil, err
 	}
 
 	if cfg.DBHost == "" || cfg.DBPort == "" {
 		return nil, errors.New("database configuration (HOST/PORT) is missing")
 	}
 
+	if err := cfg.ContractNumber.Validate(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// loadEncryptionConfig reads ENCRYPTION_KEYS ("id:base64key,..."), ENCRYPTION_ACTIVE_KEY (defaults
+// to the first key) and BLIND_INDEX_KEY (base64)
+func loadEncryptionConfig(cfg *EncryptionConfig) error {
+	keys := getEnvAsSlice("ENCRYPTION_KEYS", nil)
+	if len(keys) == 0 {
+		return errors.New("ENCRYPTION_KEYS is required")
+	}
+	cfg.Keys = make(map[string][]byte, len(keys))
+	for _, entry := range keys {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" {
+			return fmt.Errorf("invalid ENCRYPTION_KEYS entry %q, expected id:base64key", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return fmt.Errorf("encryption key %q must be 32 bytes, base64 encoded", id)
+		}
+		cfg.Keys[id] = key
+		if cfg.ActiveKeyID == "" {
+			cfg.ActiveKeyID = id
+		}
+	}
+	if active := getEnv("ENCRYPTION_ACTIVE_KEY", ""); active != "" {
+		if _, ok := cfg.Keys[active]; !ok {
+			return fmt.Errorf("ENCRYPTION_ACTIVE_KEY %q is not in ENCRYPTION_KEYS", active)
+		}
+		cfg.ActiveKeyID = active
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(getEnv("BLIND_INDEX_KEY", ""))
+	if err != nil || len(indexKey) < 32 {
+		return errors.New("BLIND_INDEX_KEY must be at least 32 bytes, base64 encoded")
+	}
+	cfg.BlindIndexKey = indexKey
+	return nil
+}
+
+// loadJWTConfig reads JWT_SIGNING_KEYS ("kid:path/to/key.pem,...") and JWT_ACTIVE_KEY (defaults to
+// the first key)
+func loadJWTConfig(cfg *JWTConfig) error {
+	if cfg.ExpiryHours <= 0 {
+		return errors.New("JWT_EXPIRY_HOURS must be positive")
+	}
+
+	keys := getEnvAsSlice("JWT_SIGNING_KEYS", nil)
+	if len(keys) == 0 {
+		return errors.New("JWT_SIGNING_KEYS is required")
+	}
+	for _, entry := range keys {
+		id, path, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" || path == "" {
+			return fmt.Errorf("invalid JWT_SIGNING_KEYS entry %q, expected kid:path", entry)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read JWT key %q: %w", id, err)
+		}
+		cfg.SigningKeys = append(cfg.SigningKeys, JWTSigningKey{ID: id, PEM: data})
+	}
+
+	cfg.ActiveKeyID = getEnv("JWT_ACTIVE_KEY", cfg.SigningKeys[0].ID)
+	return nil
+}
+
 // Helper function untuk membaca env dengan default value
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -0,0 +1,34 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractNumberConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		wantErr string
+	}{
+		{"Default", "{PREFIX}-{BRANCH}-{DATE}-{SEQ}{CHECK}", ""},
+		{"WithoutCheckDigit", "{BRANCH}/{PREFIX}/{DATE}/{SEQ}", ""},
+		{"WithoutDate", "{PREFIX}-{BRANCH}-{SEQ}", "{DATE}"},
+		{"WithoutPrefix", "{BRANCH}-{DATE}-{SEQ}", "{PREFIX}"},
+		{"WithoutBranch", "{PREFIX}-{DATE}-{SEQ}", "{BRANCH}"},
+		{"WithoutSequence", "{PREFIX}-{BRANCH}-{DATE}", "{SEQ}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := config.ContractNumberConfig{Format: tt.format}.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
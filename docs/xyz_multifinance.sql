@@ -56,7 +56,7 @@ CREATE TABLE `consumers` (
 
 LOCK TABLES `consumers` WRITE;
 /*!40000 ALTER TABLE `consumers` DISABLE KEYS */;
-INSERT INTO `consumers` VALUES (1,2,'1234567890123456','Budi Santoso','Budi Santoso','Jakarta','1990-01-01',10000000.00,'budi.webp','budi.jpeg','2026-02-01 22:08:20.280','2026-02-01 22:08:20.280'),(2,3,'6543210987654321','Annisa Putri','Annisa Putri','Bandung','1992-05-15',15000000.00,'annisa.jpeg','annisa.jpeg','2026-02-01 22:08:20.281','2026-02-01 22:08:20.281');
+INSERT INTO `consumers` VALUES (1,2,'3171010101900001','Budi Santoso','Budi Santoso','Jakarta','1990-01-01',10000000.00,'budi.webp','budi.jpeg','2026-02-01 22:08:20.280','2026-02-01 22:08:20.280'),(2,3,'3273015505920002','Annisa Putri','Annisa Putri','Bandung','1992-05-15',15000000.00,'annisa.jpeg','annisa.jpeg','2026-02-01 22:08:20.281','2026-02-01 22:08:20.281');
 /*!40000 ALTER TABLE `consumers` ENABLE KEYS */;
 UNLOCK TABLES;
 
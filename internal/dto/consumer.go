@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
+// SubmitConsumerRequest is the multipart KYC form. Both images are required on the first
+// submission; on a resubmission an omitted image keeps the one already on file.
+type SubmitConsumerRequest struct {
+	NIK          string                `form:"nik" binding:"required,numeric,len=16"`
+	FullName     string                `form:"full_name" binding:"required,max=100"`
+	LegalName    string                `form:"legal_name" binding:"required,max=100"`
+	PlaceOfBirth string                `form:"place_of_birth" binding:"omitempty,max=50"`
+	DateOfBirth  string                `form:"date_of_birth" binding:"required,datetime=2006-01-02"`
+	Salary       money.Money           `form:"salary" binding:"required,gt=0"`
+	KTPImage     *multipart.FileHeader `form:"ktp_image"`
+	SelfieImage  *multipart.FileHeader `form:"selfie_image"`
+}
+
+type UpdateKYCStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=submitted verified rejected"`
+	Reason string `json:"reason" binding:"required_if=Status rejected,max=255"`
+}
+
+// DocumentURLs are signed, short-lived links to a consumer's KYC images
+type DocumentURLs struct {
+	KTPImage    string    `json:"ktp_image"`
+	SelfieImage string    `json:"selfie_image"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// DocumentQuery is the signature part of a document URL
+type DocumentQuery struct {
+	Owner     uint   `form:"owner" binding:"required"`
+	Viewer    uint   `form:"viewer" binding:"required"`
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required,hexadecimal"`
+}
@@ -1,18 +1,38 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
 type CreateLimitRequest struct {
-	TargetUserID uint    `json:"target_user_id" binding:"required"`
-	TenorMonth   int     `json:"tenor_month" binding:"required"`
-	LimitAmount  float64 `json:"limit_amount" binding:"required"`
+	TargetUserID uint        `json:"target_user_id" binding:"required"`
+	TenorMonth   int         `json:"tenor_month" binding:"required"`
+	LimitAmount  money.Money `json:"limit_amount" binding:"required"`
 }
 
 type UpdateLimitRequest struct {
-	TenorMonth  int     `json:"tenor_month" binding:"required"`
-	LimitAmount float64 `json:"limit_amount" binding:"required"`
+	TenorMonth  int         `json:"tenor_month" binding:"required"`
+	LimitAmount money.Money `json:"limit_amount" binding:"required"`
 }
 
 type LimitResponse struct {
-	UserID      uint    `json:"user_id"`
-	TenorMonth  int     `json:"tenor_month"`
-	LimitAmount float64 `json:"limit_amount"`
+	LimitID         uint64      `json:"limit_id,omitempty"`
+	UserID          uint        `json:"user_id"`
+	TenorMonth      int         `json:"tenor_month"`
+	LimitAmount     money.Money `json:"limit_amount"`     // ceiling
+	UsedAmount      money.Money `json:"used_amount"`      // approved/active contracts, net of repaid principal
+	ReservedAmount  money.Money `json:"reserved_amount"`  // pending transactions awaiting approval
+	AvailableAmount money.Money `json:"available_amount"` // ceiling - used - reserved, never below zero
+}
+
+// LimitMutationQuery holds the filters and pagination for GET /api/limit/mutations
+type LimitMutationQuery struct {
+	PaginationRequest
+	UserID       uint      `form:"user_id"` // admin only; users always see their own history
+	Action       string    `form:"action" binding:"omitempty,oneof=CREATE UPDATE DELETE USAGE RELEASE REPAYMENT SETTLEMENT"`
+	TenorLimitID uint      `form:"tenor_limit_id"`
+	StartDate    time.Time `form:"start_date" time_format:"2006-01-02"`
+	EndDate      time.Time `form:"end_date" time_format:"2006-01-02"` // inclusive
 }
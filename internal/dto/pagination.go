@@ -1,19 +1,24 @@
 package dto
 
-// PaginationRequest for incoming pagination params
+// PaginationRequest for incoming pagination params. Listings that support it switch from page
+// mode to cursor mode when a cursor is given or pagination=cursor is set.
 type PaginationRequest struct {
-	Page  int `form:"page"`
-	Limit int `form:"limit"`
+	Page       int    `form:"page"`
+	Limit      int    `form:"limit"`
+	Cursor     string `form:"cursor" binding:"max=512"`
+	Pagination string `form:"pagination" binding:"omitempty,oneof=page cursor"`
 }
 
 // PaginationMeta for response pagination metadata
 type PaginationMeta struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int64 `json:"total_pages"`
-	HasNext    bool  `json:"has_next"`
-	HasPrev    bool  `json:"has_prev"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPages int64  `json:"total_pages"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+	NextCursor string `json:"next_cursor,omitempty"` // cursor mode only
+	PrevCursor string `json:"prev_cursor,omitempty"` // cursor mode only
 }
 
 // PaginatedResponse wraps data with pagination metadata
@@ -27,6 +32,11 @@ func (p *PaginationRequest) GetOffset() int {
 	return (p.Page - 1) * p.Limit
 }
 
+// UsesCursor reports whether the request asks for cursor (keyset) pagination
+func (p *PaginationRequest) UsesCursor() bool {
+	return p.Cursor != "" || p.Pagination == "cursor"
+}
+
 // SetDefaults sets default values if not provided
 func (p *PaginationRequest) SetDefaults() {
 	if p.Page <= 0 {
@@ -60,3 +70,15 @@ func NewPaginatedResponse(data interface{}, page, limit int, total int64) Pagina
 		Pagination: NewPaginationMeta(page, limit, total),
 	}
 }
+
+// NewCursorPaginationMeta creates pagination metadata for cursor mode. Page and total are not
+// computed in this mode; an empty cursor means there is nothing further in that direction.
+func NewCursorPaginationMeta(limit int, nextCursor, prevCursor string) PaginationMeta {
+	return PaginationMeta{
+		Limit:      limit,
+		HasNext:    nextCursor != "",
+		HasPrev:    prevCursor != "",
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	}
+}
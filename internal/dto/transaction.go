@@ -1,11 +1,96 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
 type CreateTransactionRequest struct {
-	ContractNumber    string  `json:"contract_number" binding:"required"`
-	OTR               float64 `json:"otr" binding:"required"`
-	AdminFee          float64 `json:"admin_fee" binding:"required"`
-	InstallmentAmount float64 `json:"installment_amount" binding:"required"`
-	InterestAmount    float64 `json:"interest_amount" binding:"required"`
-	AssetName         string  `json:"asset_name" binding:"required"`
-	Tenor             int     `json:"tenor" binding:"required"`
+	ContractNumber string      `json:"contract_number" binding:"omitempty,max=50"` // optional, generated by the server when empty
+	Product        string      `json:"product" binding:"omitempty,max=50"`         // optional, the default product when empty
+	OTR            money.Money `json:"otr" binding:"required,gt=0"`
+	// Optional: calculated by the server. When sent they must match the calculated amounts.
+	AdminFee          money.Money `json:"admin_fee" binding:"gte=0"`
+	InstallmentAmount money.Money `json:"installment_amount" binding:"gte=0"`
+	InterestAmount    money.Money `json:"interest_amount" binding:"gte=0"`
+	AssetName         string      `json:"asset_name" binding:"required"`
+	Tenor             int         `json:"tenor" binding:"required,gt=0"`
+}
+
+type SimulateTransactionRequest struct {
+	Product string      `json:"product" binding:"omitempty,max=50"`
+	OTR     money.Money `json:"otr" binding:"required,gt=0"`
+	Tenor   int         `json:"tenor" binding:"required,gt=0"`
+}
+
+// TransactionQuote is the server-side pricing of a financing, with the schedule it would get if approved today
+type TransactionQuote struct {
+	Product           string             `json:"product"`
+	InterestMethod    string             `json:"interest_method"`
+	InterestRate      float64            `json:"interest_rate"` // annual, percent
+	OTR               money.Money        `json:"otr"`
+	Tenor             int                `json:"tenor"`
+	AdminFee          money.Money        `json:"admin_fee"`
+	InterestAmount    money.Money        `json:"interest_amount"`
+	InstallmentAmount money.Money        `json:"installment_amount"`
+	TotalPayable      money.Money        `json:"total_payable"`
+	Installments      []QuoteInstallment `json:"installments"`
+}
+
+type QuoteInstallment struct {
+	InstallmentNumber int         `json:"installment_number"`
+	DueDate           time.Time   `json:"due_date"`
+	PrincipalAmount   money.Money `json:"principal_amount"`
+	InterestAmount    money.Money `json:"interest_amount"`
+	AdminFeeAmount    money.Money `json:"admin_fee_amount"`
+	TotalAmount       money.Money `json:"total_amount"`
+}
+
+type RejectTransactionRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// TransactionQuery holds the filters, sort and pagination for GET /api/transaction
+type TransactionQuery struct {
+	PaginationRequest
+	UserID         uint        `form:"user_id"` // admin only; users always see their own transactions
+	Status         string      `form:"status" binding:"omitempty,oneof=pending approved rejected active settled cancelled"`
+	Tenor          int         `form:"tenor" binding:"omitempty,gt=0"`
+	AssetName      string      `form:"asset_name" binding:"max=255"`
+	ContractNumber string      `form:"contract_number" binding:"max=50"`
+	MinOTR         money.Money `form:"min_otr" binding:"gte=0"`
+	MaxOTR         money.Money `form:"max_otr" binding:"gte=0"`
+	StartDate      time.Time   `form:"start_date" time_format:"2006-01-02"`
+	EndDate        time.Time   `form:"end_date" time_format:"2006-01-02"` // inclusive
+	Sort           string      `form:"sort" binding:"omitempty,oneof=created_at_desc created_at_asc otr_desc otr_asc tenor_desc tenor_asc"`
+}
+
+type CancelTransactionRequest struct {
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+// PayoffQuote is the amount that settles a contract early if paid today
+type PayoffQuote struct {
+	TransactionID       uint64      `json:"transaction_id"`
+	ContractNumber      string      `json:"contract_number"`
+	AsOf                time.Time   `json:"as_of"`
+	ValidUntil          time.Time   `json:"valid_until"`      // the quote changes as interest accrues the next day
+	PrincipalAmount     money.Money `json:"principal_amount"` // remaining principal
+	InterestAmount      money.Money `json:"interest_amount"`  // overdue interest plus interest accrued to date
+	AdminFeeAmount      money.Money `json:"admin_fee_amount"`
+	PenaltyAmount       money.Money `json:"penalty_amount"` // unpaid late fees
+	EarlyTerminationFee money.Money `json:"early_termination_fee"`
+	TotalAmount         money.Money `json:"total_amount"`
+	InterestWaived      money.Money `json:"interest_waived"` // scheduled interest no longer charged
+}
+
+type SettleTransactionRequest struct {
+	Amount    money.Money `json:"amount" binding:"required,gt=0"` // must equal the current payoff total
+	Reference string      `json:"reference" binding:"max=100"`
+}
+
+type CreatePaymentRequest struct {
+	Amount    money.Money `json:"amount" binding:"required,gt=0"`
+	Reference string      `json:"reference" binding:"max=100"`
 }
@@ -1,18 +1,39 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
 type ConsumerResponse struct {
-	NIK          string  `json:"nik"`
-	FullName     string  `json:"full_name"`
-	LegalName    string  `json:"legal_name"`
-	PlaceOfBirth string  `json:"place_of_birth"`
-	DateOfBirth  string  `json:"date_of_birth"`
-	Salary       float64 `json:"salary"`
-	KTPImage     string  `json:"ktp_image"`
-	SelfieImage  string  `json:"selfie_image"`
+	NIK          string      `json:"nik"`
+	FullName     string      `json:"full_name"`
+	LegalName    string      `json:"legal_name"`
+	PlaceOfBirth string      `json:"place_of_birth"`
+	DateOfBirth  string      `json:"date_of_birth"`
+	Salary       money.Money `json:"salary"`
+	KTPImage     string      `json:"ktp_image"`
+	SelfieImage  string      `json:"selfie_image"`
+	KYCStatus    string      `json:"kyc_status"`
+	// KTPImage and SelfieImage are signed links that stop working at ImagesExpireAt
+	ImagesExpireAt time.Time `json:"images_expire_at"`
+	NIKMismatch    string    `json:"nik_mismatch,omitempty"` // only shown to admins
 }
 
 type UserProfileResponse struct {
 	UserID   uint              `json:"user_id"`
 	Email    string            `json:"email"`
 	Consumer *ConsumerResponse `json:"consumer"`
+	// Collectibility is the consumer's worst contract grade (1-5); only shown to admins
+	Collectibility int `json:"collectibility,omitempty"`
+}
+
+// ProfileQuery lets an admin view another user's profile
+type ProfileQuery struct {
+	UserID uint `form:"user_id"`
+}
+
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required"`
 }
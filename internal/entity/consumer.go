@@ -2,19 +2,41 @@ package entity
 
 import (
 	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
 )
 
+// KYCStatus is the review state of a consumer's identity data
+type KYCStatus string
+
+const (
+	KYCSubmitted KYCStatus = "submitted"
+	KYCVerified  KYCStatus = "verified"
+	KYCRejected  KYCStatus = "rejected"
+)
+
+// Consumer holds a user's identity data. NIK, FullName, LegalName, DateOfBirth and Salary are
+// encrypted at rest (pkg/encryption); NIKHash is the NIK's blind index, which lookups and the
+// unique constraint use, and EncryptionKeyID the key the row was last written with.
 type Consumer struct {
-	ID           uint64  `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID       uint    `gorm:"not null" json:"user_id"`
-	NIK          string  `gorm:"uniqueIndex;type:varchar(16);not null" json:"nik"`
-	FullName     string  `gorm:"type:varchar(100);not null" json:"full_name"`
-	LegalName    string  `gorm:"type:varchar(100);not null" json:"legal_name"`
-	PlaceOfBirth string  `gorm:"type:varchar(50)" json:"place_of_birth"`
-	DateOfBirth  string  `gorm:"type:date" json:"date_of_birth"` // Format YYYY-MM-DD
-	Salary       float64 `gorm:"type:decimal(15,2)" json:"salary"`
-	KTPImage     string  `gorm:"type:varchar(255)" json:"ktp_image"`
-	SelfieImage  string  `gorm:"type:varchar(255)" json:"selfie_image"`
+	ID              uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID          uint        `gorm:"not null;uniqueIndex" json:"user_id"`
+	NIK             string      `gorm:"type:varchar(255);serializer:encrypted;not null" json:"nik"`
+	NIKHash         string      `gorm:"uniqueIndex;type:char(64)" json:"-"`
+	FullName        string      `gorm:"type:text;serializer:encrypted;not null" json:"full_name"`
+	LegalName       string      `gorm:"type:text;serializer:encrypted;not null" json:"legal_name"`
+	PlaceOfBirth    string      `gorm:"type:varchar(50)" json:"place_of_birth"`
+	DateOfBirth     string      `gorm:"type:text;serializer:encrypted" json:"date_of_birth"` // Format YYYY-MM-DD
+	Salary          money.Money `gorm:"type:text;serializer:encrypted" json:"salary"`
+	KTPImage        string      `gorm:"type:varchar(255)" json:"ktp_image"`
+	SelfieImage     string      `gorm:"type:varchar(255)" json:"selfie_image"`
+	EncryptionKeyID string      `gorm:"type:varchar(32);index" json:"-"`
+
+	KYCStatus          KYCStatus  `gorm:"type:varchar(20);default:'submitted'" json:"kyc_status"` // submitted, verified, rejected
+	KYCReviewedBy      *uint      `json:"kyc_reviewed_by,omitempty"`
+	KYCReviewedAt      *time.Time `json:"kyc_reviewed_at,omitempty"`
+	KYCRejectionReason string     `gorm:"type:varchar(255)" json:"kyc_rejection_reason,omitempty"`
+	NIKMismatch        string     `gorm:"type:varchar(255)" json:"nik_mismatch,omitempty"` // differences between the NIK and the submitted data, for KYC review
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// ContractSequence holds the last contract number sequence issued for a scope (prefix, branch and date)
+type ContractSequence struct {
+	Scope     string    `gorm:"primaryKey;type:varchar(100)" json:"scope"`
+	LastValue uint64    `gorm:"not null;default:0" json:"last_value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ContractSequence) TableName() string {
+	return "contract_sequences"
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// IdempotencyKey stores the outcome of a request sent with an Idempotency-Key header
+// so a retried request can be answered with the original response.
+type IdempotencyKey struct {
+	Key          string    `gorm:"primaryKey;type:varchar(64)" json:"key"` // sha256 of user, route and client key
+	Fingerprint  string    `gorm:"type:varchar(64);not null" json:"fingerprint"`
+	Completed    bool      `gorm:"not null;default:false" json:"completed"`
+	StatusCode   int       `json:"status_code"`
+	ContentType  string    `gorm:"type:varchar(100)" json:"content_type"`
+	ResponseBody []byte    `gorm:"type:mediumblob" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index:idx_idempotency_keys_expires_at" json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
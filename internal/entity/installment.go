@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
+type InstallmentStatus string
+
+const (
+	InstallmentUnpaid InstallmentStatus = "unpaid"
+	InstallmentPaid   InstallmentStatus = "paid"
+	// InstallmentOverdue marks unpaid installments past their due date (set by the overdue job)
+	InstallmentOverdue InstallmentStatus = "overdue"
+	// InstallmentCancelled marks installments that are no longer due because the contract was cancelled
+	InstallmentCancelled InstallmentStatus = "cancelled"
+	// InstallmentSettled marks installments closed early by a contract payoff
+	InstallmentSettled InstallmentStatus = "settled"
+)
+
+// IsOpen reports whether an installment still has to be paid
+func (s InstallmentStatus) IsOpen() bool {
+	return s == InstallmentUnpaid || s == InstallmentOverdue
+}
+
+type Installment struct {
+	ID                uint64            `gorm:"primaryKey;autoIncrement" json:"id"`
+	TransactionID     uint64            `gorm:"not null;uniqueIndex:idx_installments_transaction_number,priority:1" json:"transaction_id"`
+	InstallmentNumber int               `gorm:"not null;uniqueIndex:idx_installments_transaction_number,priority:2" json:"installment_number"`
+	DueDate           time.Time         `gorm:"type:date;not null;index:idx_installments_due_date" json:"due_date"`
+	PrincipalAmount   money.Money       `gorm:"type:decimal(15,2);not null" json:"principal_amount"`
+	InterestAmount    money.Money       `gorm:"type:decimal(15,2);not null" json:"interest_amount"`
+	AdminFeeAmount    money.Money       `gorm:"type:decimal(15,2);not null" json:"admin_fee_amount"`
+	TotalAmount       money.Money       `gorm:"type:decimal(15,2);not null" json:"total_amount"`
+	PaidAmount        money.Money       `gorm:"type:decimal(15,2);default:0" json:"paid_amount"`
+	Status            InstallmentStatus `gorm:"type:varchar(20);default:'unpaid'" json:"status"` // unpaid, overdue, paid, cancelled, settled
+	PaidAt            *time.Time        `json:"paid_at,omitempty"`
+	DaysPastDue       int               `gorm:"default:0" json:"days_past_due"`
+	PenaltyAmount     money.Money       `gorm:"type:decimal(15,2);default:0" json:"penalty_amount"` // late fees accrued, on top of TotalAmount
+	PenaltyPaid       money.Money       `gorm:"type:decimal(15,2);default:0" json:"penalty_paid"`
+	PenaltyAccruedTo  *time.Time        `gorm:"type:date" json:"penalty_accrued_to,omitempty"` // last day a late fee was charged for
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Installment) TableName() string {
+	return "installments"
+}
+
+// PenaltyDue returns the late fees accrued and not yet paid
+func (i *Installment) PenaltyDue() money.Money {
+	return i.PenaltyAmount - i.PenaltyPaid
+}
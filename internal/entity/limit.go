@@ -2,6 +2,8 @@ package entity
 
 import (
 	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
 )
 
 type Tenor int
@@ -14,9 +16,9 @@ const (
 )
 
 type TenorLimit struct {
-	ID          uint64  `gorm:"primaryKey;autoIncrement" json:"id"`
-	TenorMonth  Tenor   `gorm:"not null;comment:'1, 2, 3, or 6'" json:"tenor_month"`
-	LimitAmount float64 `gorm:"type:decimal(15,2);default:0" json:"limit_amount"`
+	ID          uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenorMonth  Tenor       `gorm:"not null;comment:'1, 2, 3, or 6'" json:"tenor_month"`
+	LimitAmount money.Money `gorm:"type:decimal(15,2);default:0" json:"limit_amount"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -2,26 +2,32 @@ package entity
 
 import (
 	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
 )
 
 type MutationAction string
 
 const (
-	MutationCreate MutationAction = "CREATE"
-	MutationUpdate MutationAction = "UPDATE"
-	MutationDelete MutationAction = "DELETE"
-	MutationUsage  MutationAction = "USAGE"
+	MutationCreate     MutationAction = "CREATE"
+	MutationUpdate     MutationAction = "UPDATE"
+	MutationDelete     MutationAction = "DELETE"
+	MutationUsage      MutationAction = "USAGE"
+	MutationRelease    MutationAction = "RELEASE"    // usage returned to the limit (rejection, cancellation)
+	MutationRepayment  MutationAction = "REPAYMENT"  // repaid principal returned to the limit
+	MutationSettlement MutationAction = "SETTLEMENT" // principal returned to the limit by early settlement
 )
 
 type LimitMutation struct {
 	ID           uint           `gorm:"primaryKey" json:"id"`
-	UserID       uint           `gorm:"index:idx_limit_mutations_user_id" json:"user_id"`
+	UserID       uint           `gorm:"index:idx_limit_mutations_user_id;index:idx_limit_mutations_user_created,priority:1" json:"user_id"`
 	TenorLimitID uint           `json:"tenor_limit_id"`
-	OldAmount    float64        `json:"old_amount"`
-	NewAmount    float64        `json:"new_amount"`
+	OldAmount    money.Money    `gorm:"type:decimal(15,2)" json:"old_amount"`
+	NewAmount    money.Money    `gorm:"type:decimal(15,2)" json:"new_amount"`
+	Amount       money.Money    `gorm:"type:decimal(15,2);default:0" json:"amount"` // amount used, released or repaid
 	Reason       string         `json:"reason"`
-	Action       MutationAction `json:"action"` // CREATE, UPDATE, DELETE
-	CreatedAt    time.Time      `json:"created_at"`
+	Action       MutationAction `json:"action"` // CREATE, UPDATE, DELETE, USAGE, RELEASE, REPAYMENT, SETTLEMENT
+	CreatedAt    time.Time      `gorm:"index:idx_limit_mutations_user_created,priority:2" json:"created_at"`
 }
 
 func (LimitMutation) TableName() string { return "limit_mutations" }
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
+type Payment struct {
+	ID              uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TransactionID   uint64      `gorm:"not null;index:idx_payments_transaction_id" json:"transaction_id"`
+	UserID          uint        `gorm:"not null;index:idx_payments_user_id" json:"user_id"` // contract owner
+	RecordedBy      uint        `gorm:"not null" json:"recorded_by"`
+	Amount          money.Money `gorm:"type:decimal(15,2);not null" json:"amount"`
+	PrincipalAmount money.Money `gorm:"type:decimal(15,2);not null" json:"principal_amount"`
+	InterestAmount  money.Money `gorm:"type:decimal(15,2);not null" json:"interest_amount"`
+	AdminFeeAmount  money.Money `gorm:"type:decimal(15,2);not null" json:"admin_fee_amount"`
+	PenaltyAmount   money.Money `gorm:"type:decimal(15,2);default:0" json:"penalty_amount"` // late fees
+	FeeAmount       money.Money `gorm:"type:decimal(15,2);default:0" json:"fee_amount"`     // early termination fee, not allocated to installments
+	Reference       string      `gorm:"type:varchar(100)" json:"reference"`
+	PaidAt          time.Time   `gorm:"not null" json:"paid_at"`
+
+	Allocations []PaymentAllocation `gorm:"foreignKey:PaymentID" json:"allocations,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Payment) TableName() string {
+	return "payments"
+}
+
+// PaymentAllocation records how much of a payment went to a single installment
+type PaymentAllocation struct {
+	ID              uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	PaymentID       uint64      `gorm:"not null;index:idx_payment_allocations_payment_id" json:"payment_id"`
+	InstallmentID   uint64      `gorm:"not null;index:idx_payment_allocations_installment_id" json:"installment_id"`
+	Amount          money.Money `gorm:"type:decimal(15,2);not null" json:"amount"`
+	PrincipalAmount money.Money `gorm:"type:decimal(15,2);not null" json:"principal_amount"`
+	InterestAmount  money.Money `gorm:"type:decimal(15,2);not null" json:"interest_amount"`
+	AdminFeeAmount  money.Money `gorm:"type:decimal(15,2);not null" json:"admin_fee_amount"`
+	PenaltyAmount   money.Money `gorm:"type:decimal(15,2);default:0" json:"penalty_amount"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (PaymentAllocation) TableName() string {
+	return "payment_allocations"
+}
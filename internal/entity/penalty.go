@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
+// Penalty is one day's late fee on an overdue installment. The unique index on installment and
+// date keeps the overdue job from charging the same day twice.
+type Penalty struct {
+	ID            uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TransactionID uint64      `gorm:"not null;index:idx_penalties_transaction_id" json:"transaction_id"`
+	InstallmentID uint64      `gorm:"not null;uniqueIndex:idx_penalties_installment_date,priority:1" json:"installment_id"`
+	UserID        uint        `gorm:"not null" json:"user_id"`
+	AccrualDate   time.Time   `gorm:"type:date;not null;uniqueIndex:idx_penalties_installment_date,priority:2" json:"accrual_date"`
+	DaysPastDue   int         `gorm:"not null" json:"days_past_due"`
+	BaseAmount    money.Money `gorm:"type:decimal(15,2);not null" json:"base_amount"` // unpaid installment amount the fee was charged on
+	Rate          float64     `gorm:"type:decimal(7,4);not null" json:"rate"`         // percent per day
+	Amount        money.Money `gorm:"type:decimal(15,2);not null" json:"amount"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Penalty) TableName() string {
+	return "penalties"
+}
@@ -8,6 +8,7 @@ type RefreshToken struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	UserID    uint      `gorm:"not null;index" json:"user_id"`
 	Token     string    `gorm:"uniqueIndex;not null;type:varchar(512)" json:"token"`
+	FamilyID  string    `gorm:"type:char(36);index" json:"family_id"` // shared by the tokens rotated from one login
 	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
 	Revoked   bool      `gorm:"default:false" json:"revoked"`
 	CreatedAt time.Time `json:"created_at"`
@@ -1,21 +1,143 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
+type TransactionStatus string
+
+const (
+	TransactionPending   TransactionStatus = "pending"
+	TransactionApproved  TransactionStatus = "approved"
+	TransactionRejected  TransactionStatus = "rejected"
+	TransactionActive    TransactionStatus = "active"
+	TransactionSettled   TransactionStatus = "settled"
+	TransactionCancelled TransactionStatus = "cancelled"
+)
+
+// transactionTransitions lists the statuses a transaction may move to from each status.
+// Statuses without an entry (rejected, settled, cancelled) are final.
+var transactionTransitions = map[TransactionStatus][]TransactionStatus{
+	TransactionPending:  {TransactionApproved, TransactionRejected, TransactionCancelled},
+	TransactionApproved: {TransactionActive, TransactionCancelled},
+	TransactionActive:   {TransactionSettled},
+}
+
+// CanTransitionTo reports whether the lifecycle allows moving from s to next.
+func (s TransactionStatus) CanTransitionTo(next TransactionStatus) bool {
+	for _, allowed := range transactionTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// LimitConsumingStatuses are the statuses whose transactions count against the tenor limit
+var LimitConsumingStatuses = []TransactionStatus{TransactionPending, TransactionApproved, TransactionActive}
+
+// ConsumesLimit reports whether a transaction in this status still counts against the tenor limit.
+func (s TransactionStatus) ConsumesLimit() bool {
+	for _, status := range LimitConsumingStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// InterestMethod is how a transaction's interest is calculated
+type InterestMethod string
+
+const (
+	InterestFlat      InterestMethod = "flat"      // interest on the original OTR for every month
+	InterestEffective InterestMethod = "effective" // annuity, interest on the declining balance
+)
+
+// DPDBucket groups contracts by days past due for collection and reporting
+type DPDBucket string
+
+const (
+	DPDCurrent DPDBucket = "current"
+	DPD1To30   DPDBucket = "1-30"
+	DPD31To60  DPDBucket = "31-60"
+	DPD61To90  DPDBucket = "61-90"
+	DPDOver90  DPDBucket = "90+"
+)
+
+// DPDBucketFor returns the bucket of a contract that is days past due
+func DPDBucketFor(days int) DPDBucket {
+	switch {
+	case days <= 0:
+		return DPDCurrent
+	case days <= 30:
+		return DPD1To30
+	case days <= 60:
+		return DPD31To60
+	case days <= 90:
+		return DPD61To90
+	default:
+		return DPDOver90
+	}
+}
+
+// Collectibility is the OJK collectibility grade (kolektibilitas) of a contract, from
+// 1 (lancar) to 5 (macet). The zero value means the contract has not been classified.
+type Collectibility int
+
+const (
+	CollectibilityCurrent        Collectibility = 1 // lancar: not past due
+	CollectibilitySpecialMention Collectibility = 2 // dalam perhatian khusus: 1-90 days past due
+	CollectibilitySubstandard    Collectibility = 3 // kurang lancar: 91-120 days past due
+	CollectibilityDoubtful       Collectibility = 4 // diragukan: 121-180 days past due
+	CollectibilityLoss           Collectibility = 5 // macet: more than 180 days past due
+)
+
+// CollectibilityFor returns the grade of a contract that is days past due
+func CollectibilityFor(days int) Collectibility {
+	switch {
+	case days <= 0:
+		return CollectibilityCurrent
+	case days <= 90:
+		return CollectibilitySpecialMention
+	case days <= 120:
+		return CollectibilitySubstandard
+	case days <= 180:
+		return CollectibilityDoubtful
+	default:
+		return CollectibilityLoss
+	}
+}
 
 type Transaction struct {
-	ID                uint64  `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID            uint    `gorm:"not null;index:idx_transactions_user_id;index:idx_transactions_user_created,priority:1" json:"user_id"`
-	User              User    `gorm:"foreignKey:UserID" json:"-"`
-	ContractNumber    string  `gorm:"uniqueIndex;type:varchar(50);not null" json:"contract_number"`
-	OTR               float64 `gorm:"type:decimal(15,2);not null" json:"otr"`
-	AdminFee          float64 `gorm:"type:decimal(15,2);not null" json:"admin_fee"`
-	InstallmentAmount float64 `gorm:"type:decimal(15,2);not null" json:"installment_amount"`
-	InterestAmount    float64 `gorm:"type:decimal(15,2);not null" json:"interest_amount"`
-	AssetName         string  `gorm:"type:varchar(255);not null" json:"asset_name"`
-	Status            string  `gorm:"type:varchar(20);default:'pending'" json:"status"` // pending, approved, rejected
-	Tenor             int     `gorm:"type:int;not null" json:"tenor"`
-
-	CreatedAt time.Time `gorm:"index:idx_transactions_user_created,priority:2" json:"created_at"`
+	ID                 uint64            `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID             uint              `gorm:"not null;index:idx_transactions_user_id;index:idx_transactions_user_created,priority:1" json:"user_id"`
+	User               User              `gorm:"foreignKey:UserID" json:"-"`
+	ContractNumber     string            `gorm:"uniqueIndex;type:varchar(50);not null" json:"contract_number"`
+	OTR                money.Money       `gorm:"type:decimal(15,2);not null" json:"otr"`
+	AdminFee           money.Money       `gorm:"type:decimal(15,2);not null" json:"admin_fee"`
+	InstallmentAmount  money.Money       `gorm:"type:decimal(15,2);not null" json:"installment_amount"`
+	InterestAmount     money.Money       `gorm:"type:decimal(15,2);not null" json:"interest_amount"`
+	AssetName          string            `gorm:"type:varchar(255);not null" json:"asset_name"`
+	Product            string            `gorm:"type:varchar(50)" json:"product"`
+	InterestMethod     InterestMethod    `gorm:"type:varchar(20)" json:"interest_method"`          // empty for transactions priced by the client (treated as flat)
+	InterestRate       float64           `gorm:"type:decimal(7,4);default:0" json:"interest_rate"` // annual, percent
+	Status             TransactionStatus `gorm:"type:varchar(20);default:'pending'" json:"status"` // pending, approved, rejected, active, settled, cancelled
+	Tenor              int               `gorm:"type:int;not null" json:"tenor"`
+	OutstandingAmount  money.Money       `gorm:"type:decimal(15,2);default:0" json:"outstanding_amount"` // set on approval, reduced by payments
+	PrincipalPaid      money.Money       `gorm:"type:decimal(15,2);default:0" json:"principal_paid"`
+	ReviewedBy         *uint             `json:"reviewed_by,omitempty"`
+	ReviewedAt         *time.Time        `json:"reviewed_at,omitempty"`
+	RejectionReason    string            `gorm:"type:varchar(255)" json:"rejection_reason,omitempty"`
+	CancellationReason string            `gorm:"type:varchar(255)" json:"cancellation_reason,omitempty"`
+	DaysPastDue        int               `gorm:"default:0" json:"days_past_due"` // of the oldest open installment, updated daily
+	DPDBucket          DPDBucket         `gorm:"type:varchar(10);default:'current'" json:"dpd_bucket"`
+	PenaltyAmount      money.Money       `gorm:"type:decimal(15,2);default:0" json:"penalty_amount"` // late fees accrued over the contract's life
+	Collectibility     Collectibility    `gorm:"-" json:"collectibility,omitempty"`                  // derived from DaysPastDue, only filled in for admins
+
+	CreatedAt time.Time `gorm:"index:idx_transactions_user_created,priority:2;index:idx_transactions_created" json:"created_at"` // idx_transactions_created serves admin listings across users
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
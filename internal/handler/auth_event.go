@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// Actions of the events written to the auth log
+const (
+	authActionRegister       = "register"
+	authActionLoginSuccess   = "login_success"
+	authActionLoginFailure   = "login_failure"
+	authActionLockout        = "login_lockout"
+	authActionUnlock         = "login_unlock"
+	authActionRefresh        = "token_refresh"
+	authActionRefreshFailure = "token_refresh_failure"
+	authActionLogout         = "logout"
+	authActionLogoutAll      = "logout_all"
+	authActionPasswordChange = "password_change"
+)
+
+// Reasons a login fails, logged with authActionLoginFailure
+const (
+	loginFailureInvalidCredentials = "invalid_credentials"
+	loginFailureSuspended          = "account_suspended"
+	loginFailureLocked             = "locked_out"
+	loginFailureError              = "internal_error"
+)
+
+// authEvent fills in the fields every auth log entry carries: the action, the user (0 when not
+// known, e.g. for a failed login), client IP, user agent and the request ID set by RequestLogger
+func authEvent(c *gin.Context, event *zerolog.Event, action string, userID uint) *zerolog.Event {
+	event = event.
+		Str("action", action).
+		Str("ip", c.ClientIP()).
+		Str("user_agent", c.Request.UserAgent()).
+		Str("request_id", c.GetString("request_id"))
+	if userID != 0 {
+		event = event.Uint("user_id", userID)
+	}
+	return event
+}
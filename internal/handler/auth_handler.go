@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hadi-projects/xyz-finance-go/internal/dto"
@@ -13,13 +17,15 @@ import (
 type AuthHandler struct {
 	authService services.AuthService
 	jwtService  services.JWTService
+	loginGuard  *services.LoginGuard
 }
 
 // NewAuthHandler creates a new auth handler instance
-func NewAuthHandler(authService services.AuthService, jwtService services.JWTService) *AuthHandler {
+func NewAuthHandler(authService services.AuthService, jwtService services.JWTService, loginGuard *services.LoginGuard) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
 		jwtService:  jwtService,
+		loginGuard:  loginGuard,
 	}
 }
 
@@ -55,6 +61,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Str("email", user.Email).
 		Uint("user_id", user.ID).
 		Msg("New user registered")
+	authEvent(c, logger.AuthLogger.Info(), authActionRegister, user.ID).
+		Str("email", user.Email).
+		Msg("User registered")
 }
 
 // Login handles user authentication
@@ -65,12 +74,52 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	ctx := c.Request.Context()
+	ip := c.ClientIP()
+	if err := h.loginGuard.Check(ctx, req.Email, ip); err != nil {
+		var locked *services.LoginLockedError
+		if errors.As(err, &locked) {
+			authEvent(c, logger.AuthLogger.Warn(), authActionLoginFailure, 0).
+				Str("email", req.Email).
+				Str("reason", loginFailureLocked).
+				Str("scope", locked.Scope).
+				Time("locked_until", locked.Until).
+				Msg("Login attempt while locked out")
+			writeLoginLocked(c, locked)
+			return
+		}
+		c.Abort() // request cancelled during the delay
+		return
+	}
+
 	user, err := h.authService.Login(req.Email, req.Password)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			h.recordLoginFailure(c, req.Email, ip)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrUserSuspended) {
+			authEvent(c, logger.AuthLogger.Warn(), authActionLoginFailure, 0).
+				Str("email", req.Email).
+				Str("reason", loginFailureSuspended).
+				Msg("Login by suspended user")
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		authEvent(c, logger.AuthLogger.Error(), authActionLoginFailure, 0).
+			Err(err).
+			Str("email", req.Email).
+			Str("reason", loginFailureError).
+			Msg("Login failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if err := h.loginGuard.RecordSuccess(ctx, req.Email); err != nil {
+		logger.SystemLogger.Error().Err(err).Msg("Failed to reset login failures")
+	}
+
 	// Generate JWT token
 	token, err := h.jwtService.GenerateToken(user.ID, user.Email)
 	if err != nil {
@@ -94,4 +143,158 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			"email": user.Email,
 		},
 	})
+
+	authEvent(c, logger.AuthLogger.Info(), authActionLoginSuccess, user.ID).
+		Str("email", user.Email).
+		Msg("Login successful")
+}
+
+// recordLoginFailure counts a wrong password and logs the failure and any lockout it caused
+func (h *AuthHandler) recordLoginFailure(c *gin.Context, email, ip string) {
+	failure, err := h.loginGuard.RecordFailure(c.Request.Context(), email, ip)
+	if err != nil {
+		logger.SystemLogger.Error().Err(err).Msg("Failed to record login failure")
+	}
+
+	authEvent(c, logger.AuthLogger.Warn(), authActionLoginFailure, 0).
+		Str("email", email).
+		Str("reason", loginFailureInvalidCredentials).
+		Int("account_failures", failure.AccountFailures).
+		Int("ip_failures", failure.IPFailures).
+		Msg("Invalid email or password")
+
+	for _, lockout := range failure.Lockouts {
+		authEvent(c, logger.AuthLogger.Warn(), authActionLockout, 0).
+			Str("email", email).
+			Str("scope", lockout.Scope).
+			Time("locked_until", lockout.Until).
+			Msg("Too many failed logins, locked out")
+	}
+}
+
+// writeLoginLocked rejects a login attempt during a lockout, telling the client when to retry
+func writeLoginLocked(c *gin.Context, locked *services.LoginLockedError) {
+	retryAfter := int(math.Ceil(time.Until(locked.Until).Seconds()))
+	c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": services.ErrLoginLocked.Error()})
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pair, err := h.jwtService.RefreshAccessToken(req.RefreshToken)
+	if err != nil {
+		var reuse *services.RefreshTokenReuseError
+		switch {
+		case errors.As(err, &reuse):
+			authEvent(c, logger.AuthLogger.Warn(), authActionRefreshFailure, reuse.UserID).
+				Str("reason", "token_reused").
+				Msg("Revoked refresh token presented, token family revoked")
+		case errors.Is(err, services.ErrInvalidRefreshToken):
+			authEvent(c, logger.AuthLogger.Warn(), authActionRefreshFailure, 0).
+				Str("reason", "invalid_token").
+				Msg("Invalid or expired refresh token presented")
+		}
+		writeAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Token refreshed",
+		"access_token":  pair.AccessToken,
+		"refresh_token": pair.RefreshToken,
+	})
+
+	authEvent(c, logger.AuthLogger.Info(), authActionRefresh, pair.UserID).Msg("Token refreshed")
+}
+
+// Logout revokes the session of the given refresh token along with the access token used
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userId := c.GetUint("user_id")
+	if err := h.jwtService.RevokeSession(userId, req.RefreshToken, c.GetString("token_id"), c.GetTime("token_expires_at")); err != nil {
+		writeAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+
+	authEvent(c, logger.AuthLogger.Info(), authActionLogout, userId).Msg("Logged out")
+}
+
+// LogoutAll revokes every session of the user
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userId := c.GetUint("user_id")
+	if err := h.jwtService.RevokeAllSessions(userId); err != nil {
+		writeAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out of all sessions"})
+
+	authEvent(c, logger.AuthLogger.Info(), authActionLogoutAll, userId).Msg("Logged out of all sessions")
+}
+
+// ChangePassword sets a new password and logs the user out of every session
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if valid, msg := validator.ValidatePassword(req.NewPassword); !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	userId := c.GetUint("user_id")
+	if err := h.authService.ChangePassword(userId, req.CurrentPassword, req.NewPassword); err != nil {
+		if errors.Is(err, services.ErrInvalidCurrentPassword) {
+			authEvent(c, logger.AuthLogger.Warn(), authActionPasswordChange, userId).
+				Bool("success", false).
+				Str("reason", "invalid_current_password").
+				Msg("Password change rejected")
+		}
+		writeAuthError(c, err)
+		return
+	}
+
+	// Tokens issued with the old password must not outlive it
+	if err := h.jwtService.RevokeAllSessions(userId); err != nil {
+		writeAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed, please log in again"})
+
+	logger.AuditLogger.Info().
+		Str("action", "change_password").
+		Uint("user_id", userId).
+		Msg("Password changed")
+	authEvent(c, logger.AuthLogger.Info(), authActionPasswordChange, userId).
+		Bool("success", true).
+		Msg("Password changed, all sessions revoked")
+}
+
+// writeAuthError maps auth and token service errors to HTTP status codes
+func writeAuthError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidRefreshToken), errors.Is(err, services.ErrRefreshTokenReused):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidCurrentPassword), errors.Is(err, services.ErrPasswordUnchanged):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
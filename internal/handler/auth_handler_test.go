@@ -0,0 +1,267 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/handler"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/internal/service/mock"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJWTService := mock.NewMockJWTService(ctrl)
+	authHandler := handler.NewAuthHandler(nil, mockJWTService, nil)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/auth/refresh", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		authHandler.Refresh(c)
+		return w
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockJWTService.EXPECT().RefreshAccessToken("old-token").Return(&services.TokenPair{UserID: 2, AccessToken: "access", RefreshToken: "new-token"}, nil)
+
+		w := post(`{"refresh_token":"old-token"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "access", response["access_token"])
+		assert.Equal(t, "new-token", response["refresh_token"])
+	})
+
+	t.Run("Reused", func(t *testing.T) {
+		mockJWTService.EXPECT().RefreshAccessToken("old-token").Return(nil, &services.RefreshTokenReuseError{UserID: 2})
+
+		w := post(`{"refresh_token":"old-token"}`)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("MissingToken", func(t *testing.T) {
+		w := post(`{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockJWTService := mock.NewMockJWTService(ctrl)
+	authHandler := handler.NewAuthHandler(nil, mockJWTService, nil)
+
+	t.Run("Session", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+		mockJWTService.EXPECT().RevokeSession(uint(2), "token", "jti-1", expiresAt).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/auth/logout", bytes.NewBufferString(`{"refresh_token":"token"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user_id", uint(2))
+		c.Set("token_id", "jti-1")
+		c.Set("token_expires_at", expiresAt)
+
+		authHandler.Logout(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("OtherUsersToken", func(t *testing.T) {
+		mockJWTService.EXPECT().RevokeSession(uint(3), "token", gomock.Any(), gomock.Any()).Return(services.ErrInvalidRefreshToken)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/auth/logout", bytes.NewBufferString(`{"refresh_token":"token"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user_id", uint(3))
+
+		authHandler.Logout(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("AllSessions", func(t *testing.T) {
+		mockJWTService.EXPECT().RevokeAllSessions(uint(2)).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/auth/logout-all", nil)
+		c.Set("user_id", uint(2))
+
+		authHandler.LogoutAll(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mock.NewMockAuthService(ctrl)
+	mockJWTService := mock.NewMockJWTService(ctrl)
+	authHandler := handler.NewAuthHandler(mockAuthService, mockJWTService, nil)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("PUT", "/api/auth/password", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user_id", uint(2))
+
+		authHandler.ChangePassword(c)
+		return w
+	}
+
+	t.Run("RevokesSessions", func(t *testing.T) {
+		mockAuthService.EXPECT().ChangePassword(uint(2), "pAsswj@1873", "n3wPass@word").Return(nil)
+		mockJWTService.EXPECT().RevokeAllSessions(uint(2)).Return(nil)
+
+		w := put(`{"current_password":"pAsswj@1873","new_password":"n3wPass@word"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("WrongCurrentPassword", func(t *testing.T) {
+		mockAuthService.EXPECT().ChangePassword(uint(2), "wrong", "n3wPass@word").Return(services.ErrInvalidCurrentPassword)
+
+		w := put(`{"current_password":"wrong","new_password":"n3wPass@word"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("WeakPassword", func(t *testing.T) {
+		w := put(`{"current_password":"pAsswj@1873","new_password":"password"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestAuthHandler_Login(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mock.NewMockAuthService(ctrl)
+	mockJWTService := mock.NewMockJWTService(ctrl)
+	loginGuard := services.NewLoginGuard(services.NewMemoryLoginAttemptStore(), config.LoginProtectionConfig{
+		MaxAccountFailures:   3,
+		MaxIPFailures:        10,
+		FailureWindowMinutes: 15,
+		LockoutMinutes:       15,
+	})
+	authHandler := handler.NewAuthHandler(mockAuthService, mockJWTService, loginGuard)
+
+	login := func(email, password string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/auth/login", bytes.NewBufferString(`{"email":"`+email+`","password":"`+password+`"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		authHandler.Login(c)
+		return w
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockAuthService.EXPECT().Login("budi@mail.com", "pAsswj@1873").Return(&entity.User{ID: 2, Email: "budi@mail.com"}, nil)
+		mockJWTService.EXPECT().GenerateToken(uint(2), "budi@mail.com").Return("access", nil)
+		mockJWTService.EXPECT().GenerateRefreshToken(uint(2)).Return("refresh", nil)
+
+		w := login("budi@mail.com", "pAsswj@1873")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Suspended", func(t *testing.T) {
+		mockAuthService.EXPECT().Login("annisa@mail.com", "pAsswj@1763").Return(nil, services.ErrUserSuspended)
+
+		w := login("annisa@mail.com", "pAsswj@1763")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("LockoutAfterFailures", func(t *testing.T) {
+		mockAuthService.EXPECT().Login("admin@mail.com", "wrong").Return(nil, services.ErrInvalidCredentials).Times(3)
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusUnauthorized, login("admin@mail.com", "wrong").Code)
+		}
+
+		// Locked: the password is not even checked
+		w := login("admin@mail.com", "pAsswj@123")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		w = login("ADMIN@mail.com", "pAsswj@123")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code, "emails are compared case-insensitively")
+	})
+}
+
+func TestAuthHandler_AuthLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var buf bytes.Buffer
+	previous := logger.AuthLogger
+	logger.AuthLogger = zerolog.New(&buf)
+	defer func() { logger.AuthLogger = previous }()
+
+	mockAuthService := mock.NewMockAuthService(ctrl)
+	loginGuard := services.NewLoginGuard(services.NewMemoryLoginAttemptStore(), config.LoginProtectionConfig{
+		MaxAccountFailures:   5,
+		MaxIPFailures:        20,
+		FailureWindowMinutes: 15,
+		LockoutMinutes:       15,
+	})
+	authHandler := handler.NewAuthHandler(mockAuthService, mock.NewMockJWTService(ctrl), loginGuard)
+
+	mockAuthService.EXPECT().Login("budi@mail.com", "wrong").Return(nil, services.ErrInvalidCredentials)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/api/auth/login", bytes.NewBufferString(`{"email":"budi@mail.com","password":"wrong"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("User-Agent", "test-agent")
+	c.Request.RemoteAddr = "10.0.0.1:1234"
+	c.Set("request_id", "req-1")
+
+	authHandler.Login(c)
+
+	var event map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("auth log entry is not JSON: %v: %q", err, buf.String())
+	}
+	assert.Equal(t, "login_failure", event["action"])
+	assert.Equal(t, "invalid_credentials", event["reason"])
+	assert.Equal(t, "budi@mail.com", event["email"])
+	assert.Equal(t, "10.0.0.1", event["ip"])
+	assert.Equal(t, "test-agent", event["user_agent"])
+	assert.Equal(t, "req-1", event["request_id"])
+	assert.NotContains(t, buf.String(), "wrong", "passwords are not logged")
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+	"github.com/hadi-projects/xyz-finance-go/pkg/validator"
+)
+
+type ConsumerHandler struct {
+	consumerService services.ConsumerService
+	documentService services.DocumentService
+}
+
+func NewConsumerHandler(consumerService services.ConsumerService, documentService services.DocumentService) *ConsumerHandler {
+	return &ConsumerHandler{
+		consumerService: consumerService,
+		documentService: documentService,
+	}
+}
+
+func (h *ConsumerHandler) SubmitConsumer(c *gin.Context) {
+	var req dto.SubmitConsumerRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userId := c.GetUint("user_id")
+	consumer, err := h.consumerService.SubmitConsumer(userId, req)
+	if err != nil {
+		writeConsumerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Consumer data submitted for verification",
+		"data":    consumer,
+	})
+
+	logger.AuditLogger.Info().
+		Str("action", "submit_kyc").
+		Uint("user_id", userId).
+		Uint64("consumer_id", consumer.ID).
+		Str("nik_mismatch", consumer.NIKMismatch).
+		Msg("Consumer KYC submitted")
+}
+
+func (h *ConsumerHandler) UpdateKYCStatus(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req dto.UpdateKYCStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminId := c.GetUint("user_id")
+	consumer, err := h.consumerService.UpdateKYCStatus(adminId, uint(userID), req)
+	if err != nil {
+		writeConsumerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "KYC status updated successfully",
+		"data":    consumer,
+	})
+
+	logger.AuditLogger.Info().
+		Str("action", "update_kyc_status").
+		Uint("admin_id", adminId).
+		Uint("user_id", uint(userID)).
+		Str("status", req.Status).
+		Str("reason", req.Reason).
+		Str("nik_mismatch", consumer.NIKMismatch).
+		Msg("Consumer KYC status updated")
+}
+
+// GetDocuments issues signed links to a user's KTP and selfie images (owner or KYC reviewer)
+func (h *ConsumerHandler) GetDocuments(c *gin.Context) {
+	ownerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	viewerID := c.GetUint("user_id")
+	urls, err := h.documentService.IssueURLs(viewerID, uint(ownerID))
+	if err != nil {
+		writeConsumerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": urls})
+
+	logger.AuditLogger.Info().
+		Str("action", "issue_kyc_document_urls").
+		Uint("viewer_id", viewerID).
+		Uint("owner_id", uint(ownerID)).
+		Msg("KYC document links issued")
+}
+
+// GetDocument serves a KTP or selfie image to the user a valid signed link was issued to, or
+// redirects to a short-lived URL on the storage backend when it issues them
+func (h *ConsumerHandler) GetDocument(c *gin.Context) {
+	kind, name := c.Param("kind"), c.Param("name")
+	viewerID := c.GetUint("user_id")
+
+	var query dto.DocumentQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": services.ErrInvalidDocumentSignature.Error()})
+		return
+	}
+
+	document, err := h.documentService.Fetch(viewerID, kind, name, query)
+	if err != nil {
+		logger.AuditLogger.Warn().
+			Str("action", "view_kyc_document").
+			Uint("viewer_id", viewerID).
+			Uint("issued_to", query.Viewer).
+			Uint("owner_id", query.Owner).
+			Str("kind", kind).
+			Str("file", name).
+			Str("ip", c.ClientIP()).
+			Err(err).
+			Msg("KYC document access denied")
+		writeConsumerError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	if document.RedirectURL != "" {
+		c.Redirect(http.StatusFound, document.RedirectURL)
+	} else {
+		defer document.Body.Close()
+		c.DataFromReader(http.StatusOK, document.Size, document.ContentType, document.Body, nil)
+	}
+
+	logger.AuditLogger.Info().
+		Str("action", "view_kyc_document").
+		Uint("viewer_id", viewerID).
+		Uint("owner_id", query.Owner).
+		Str("kind", kind).
+		Str("file", name).
+		Str("ip", c.ClientIP()).
+		Msg("KYC document accessed")
+}
+
+// writeConsumerError maps consumer service errors to HTTP status codes
+func writeConsumerError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrConsumerNotFound), errors.Is(err, services.ErrDocumentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNIKAlreadyRegistered), errors.Is(err, services.ErrKYCAlreadyVerified),
+		errors.Is(err, services.ErrConcurrentSubmission):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrDocumentAccessDenied), errors.Is(err, services.ErrInvalidDocumentSignature):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrKYCImagesRequired), errors.Is(err, services.ErrInvalidImage),
+		errors.Is(err, services.ErrInvalidDateOfBirth), errors.Is(err, validator.ErrInvalidNIK):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,290 @@
+package handler_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/handler"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/internal/service/mock"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+	"github.com/hadi-projects/xyz-finance-go/pkg/validator"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// kycForm builds a multipart KYC submission; empty values are left out
+func kycForm(t *testing.T, fields map[string]string, files ...string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for key, value := range fields {
+		if value != "" {
+			writer.WriteField(key, value)
+		}
+	}
+	for _, field := range files {
+		part, err := writer.CreateFormFile(field, field+".png")
+		if err != nil {
+			t.Fatalf("failed to create form file: %v", err)
+		}
+		part.Write([]byte("\x89PNG\r\n\x1a\n"))
+	}
+	writer.Close()
+	return &body, writer.FormDataContentType()
+}
+
+func TestConsumerHandler_SubmitConsumer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConsumerService := mock.NewMockConsumerService(ctrl)
+	consumerHandler := handler.NewConsumerHandler(mockConsumerService, nil)
+
+	validFields := func() map[string]string {
+		return map[string]string{
+			"nik":           "3171010101900001",
+			"full_name":     "Siti Rahma",
+			"legal_name":    "Siti Rahma",
+			"date_of_birth": "1990-01-01",
+			"salary":        "8000000",
+		}
+	}
+	submit := func(fields map[string]string, files ...string) *httptest.ResponseRecorder {
+		body, contentType := kycForm(t, fields, files...)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/consumer/", body)
+		c.Request.Header.Set("Content-Type", contentType)
+		c.Set("user_id", uint(5))
+
+		consumerHandler.SubmitConsumer(c)
+		return w
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockConsumerService.EXPECT().SubmitConsumer(uint(5), gomock.Any()).
+			DoAndReturn(func(userID uint, req dto.SubmitConsumerRequest) (*entity.Consumer, error) {
+				assert.Equal(t, "3171010101900001", req.NIK)
+				assert.Equal(t, money.FromRupiah(8000000), req.Salary)
+				assert.NotNil(t, req.KTPImage)
+				assert.NotNil(t, req.SelfieImage)
+				return &entity.Consumer{ID: 3, UserID: userID, KYCStatus: entity.KYCSubmitted}, nil
+			})
+
+		w := submit(validFields(), "ktp_image", "selfie_image")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"kyc_status":"submitted"`)
+	})
+
+	invalid := map[string]func(map[string]string){
+		"NIKNotNumeric":     func(f map[string]string) { f["nik"] = "31710101019000AB" },
+		"NIKTooShort":       func(f map[string]string) { f["nik"] = "317101010190" },
+		"DateOfBirthFormat": func(f map[string]string) { f["date_of_birth"] = "01-01-1990" },
+		"MissingFullName":   func(f map[string]string) { f["full_name"] = "" },
+	}
+	for name, mutate := range invalid {
+		t.Run(name, func(t *testing.T) {
+			fields := validFields()
+			mutate(fields)
+
+			w := submit(fields, "ktp_image", "selfie_image")
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+
+	t.Run("InvalidImage", func(t *testing.T) {
+		mockConsumerService.EXPECT().SubmitConsumer(uint(5), gomock.Any()).Return(nil, services.ErrInvalidImage)
+
+		w := submit(validFields(), "ktp_image", "selfie_image")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("StructurallyInvalidNIK", func(t *testing.T) {
+		mockConsumerService.EXPECT().SubmitConsumer(uint(5), gomock.Any()).Return(nil, validator.ErrInvalidNIK)
+
+		w := submit(validFields(), "ktp_image", "selfie_image")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("AlreadyVerified", func(t *testing.T) {
+		mockConsumerService.EXPECT().SubmitConsumer(uint(5), gomock.Any()).Return(nil, services.ErrKYCAlreadyVerified)
+
+		w := submit(validFields())
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestConsumerHandler_UpdateKYCStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConsumerService := mock.NewMockConsumerService(ctrl)
+	consumerHandler := handler.NewConsumerHandler(mockConsumerService, nil)
+
+	update := func(userID, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("PUT", "/api/consumer/"+userID+"/kyc", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: userID}}
+		c.Set("user_id", uint(1))
+
+		consumerHandler.UpdateKYCStatus(c)
+		return w
+	}
+
+	t.Run("Verify", func(t *testing.T) {
+		mockConsumerService.EXPECT().UpdateKYCStatus(uint(1), uint(5), dto.UpdateKYCStatusRequest{Status: "verified"}).
+			Return(&entity.Consumer{ID: 3, UserID: 5, KYCStatus: entity.KYCVerified}, nil)
+
+		w := update("5", `{"status":"verified"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("RejectRequiresReason", func(t *testing.T) {
+		w := update("5", `{"status":"rejected"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UnknownStatus", func(t *testing.T) {
+		w := update("5", `{"status":"approved"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockConsumerService.EXPECT().UpdateKYCStatus(uint(1), uint(9), dto.UpdateKYCStatusRequest{Status: "verified"}).
+			Return(nil, services.ErrConsumerNotFound)
+
+		w := update("9", `{"status":"verified"}`)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestConsumerHandler_GetDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDocumentService := mock.NewMockDocumentService(ctrl)
+	consumerHandler := handler.NewConsumerHandler(nil, mockDocumentService)
+
+	signature := "0a1b2c"
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/api/documents/ktp/ktp.png?"+query, nil)
+		c.Params = gin.Params{{Key: "kind", Value: "ktp"}, {Key: "name", Value: "ktp.png"}}
+		c.Set("user_id", uint(5))
+
+		consumerHandler.GetDocument(c)
+		return w
+	}
+
+	t.Run("ValidLink", func(t *testing.T) {
+		query := dto.DocumentQuery{Owner: 5, Viewer: 5, Expires: 1893456000, Signature: signature}
+		document := &services.Document{Body: io.NopCloser(strings.NewReader("\x89PNG\r\n\x1a\n")), Size: 8, ContentType: "image/png"}
+		mockDocumentService.EXPECT().Fetch(uint(5), "ktp", "ktp.png", query).Return(document, nil)
+
+		w := get("owner=5&viewer=5&expires=1893456000&signature=" + signature)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "\x89PNG\r\n\x1a\n", w.Body.String())
+	})
+
+	t.Run("StorageRedirect", func(t *testing.T) {
+		redirect := "https://minio.example.com/kyc/ktp/ktp.png?X-Amz-Signature=abc"
+		mockDocumentService.EXPECT().Fetch(uint(5), "ktp", "ktp.png", gomock.Any()).Return(&services.Document{RedirectURL: redirect}, nil)
+
+		w := get("owner=5&viewer=5&expires=1893456000&signature=" + signature)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, redirect, w.Header().Get("Location"))
+	})
+
+	t.Run("InvalidSignature", func(t *testing.T) {
+		mockDocumentService.EXPECT().Fetch(uint(5), "ktp", "ktp.png", gomock.Any()).Return(nil, services.ErrInvalidDocumentSignature)
+
+		w := get("owner=5&viewer=5&expires=1893456000&signature=ffff")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("IssuedToAnotherUser", func(t *testing.T) {
+		mockDocumentService.EXPECT().Fetch(uint(5), "ktp", "ktp.png", gomock.Any()).Return(nil, services.ErrDocumentAccessDenied)
+
+		w := get("owner=5&viewer=1&expires=1893456000&signature=" + signature)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("UnsignedLink", func(t *testing.T) {
+		w := get("")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestConsumerHandler_GetDocuments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDocumentService := mock.NewMockDocumentService(ctrl)
+	consumerHandler := handler.NewConsumerHandler(nil, mockDocumentService)
+
+	get := func(viewerID uint, ownerID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/api/consumer/"+ownerID+"/documents", nil)
+		c.Request.Host = "attacker.example.com"
+		c.Params = gin.Params{{Key: "id", Value: ownerID}}
+		c.Set("user_id", viewerID)
+
+		consumerHandler.GetDocuments(c)
+		return w
+	}
+
+	t.Run("Owner", func(t *testing.T) {
+		mockDocumentService.EXPECT().IssueURLs(uint(5), uint(5)).Return(&dto.DocumentURLs{
+			KTPImage:    "/api/documents/ktp/a.png?signature=1",
+			SelfieImage: "/api/documents/selfie/b.png?signature=2",
+		}, nil)
+
+		w := get(5, "5")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"ktp_image":"/api/documents/ktp/a.png?signature=1"`, "links are not built from the Host header")
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		mockDocumentService.EXPECT().IssueURLs(uint(6), uint(5)).Return(nil, services.ErrDocumentAccessDenied)
+
+		w := get(6, "5")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadi-projects/xyz-finance-go/pkg/jwtkeys"
+)
+
+// jwksMaxAge is how long clients may cache the key set; a newly added key should be configured
+// at least this long before it becomes active
+const jwksMaxAge = "max-age=300"
+
+type JWKSHandler struct {
+	keys *jwtkeys.KeySet
+}
+
+func NewJWKSHandler(keys *jwtkeys.KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GetJWKS publishes the public keys access tokens can be verified with
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, "+jwksMaxAge)
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
@@ -71,7 +71,7 @@ func (h *LimitHandler) CreateLimit(c *gin.Context) {
 		Str("action", "create_limit").
 		Uint("target_user_id", req.TargetUserID).
 		Int("tenor_month", req.TenorMonth).
-		Float64("limit_amount", req.LimitAmount).
+		Str("limit_amount", req.LimitAmount.String()).
 		Msg("Start limit created")
 }
 
@@ -99,7 +99,7 @@ func (h *LimitHandler) UpdateLimit(c *gin.Context) {
 	logger.AuditLogger.Info().
 		Str("action", "update_limit").
 		Uint("limit_id", uint(id)).
-		Float64("new_limit_amount", req.LimitAmount).
+		Str("new_limit_amount", req.LimitAmount.String()).
 		Msg("Limit updated")
 }
 
@@ -123,3 +123,37 @@ func (h *LimitHandler) DeleteLimit(c *gin.Context) {
 		Uint("limit_id", uint(id)).
 		Msg("Limit deleted")
 }
+
+func (h *LimitHandler) GetMutations(c *gin.Context) {
+	userId := c.GetUint("user_id")
+
+	var query dto.LimitMutationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query.SetDefaults()
+
+	if !query.StartDate.IsZero() && !query.EndDate.IsZero() && query.EndDate.Before(query.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return
+	}
+
+	if query.UsesCursor() {
+		mutations, meta, err := h.limitService.GetMutationsByCursor(userId, query)
+		if err != nil {
+			writeListError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.PaginatedResponse{Data: mutations, Pagination: meta})
+		return
+	}
+
+	mutations, total, err := h.limitService.GetMutationsPaginated(userId, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(mutations, query.Page, query.Limit, total))
+}
@@ -10,8 +10,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
 	"github.com/hadi-projects/xyz-finance-go/internal/handler"
 	"github.com/hadi-projects/xyz-finance-go/internal/service/mock"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -28,7 +30,7 @@ func TestLimitHandler_CreateLimit(t *testing.T) {
 		req := dto.CreateLimitRequest{
 			TargetUserID: 1,
 			TenorMonth:   12,
-			LimitAmount:  1000000,
+			LimitAmount:  money.FromRupiah(1000000),
 		}
 		body, _ := json.Marshal(req)
 
@@ -44,7 +46,7 @@ func TestLimitHandler_CreateLimit(t *testing.T) {
 	})
 
 	t.Run("ServiceError", func(t *testing.T) {
-		req := dto.CreateLimitRequest{TargetUserID: 1, TenorMonth: 12, LimitAmount: 100}
+		req := dto.CreateLimitRequest{TargetUserID: 1, TenorMonth: 12, LimitAmount: money.FromRupiah(100)}
 		body, _ := json.Marshal(req)
 
 		mockLimitService.EXPECT().CreateLimit(req).Return(errors.New("service error"))
@@ -69,7 +71,7 @@ func TestLimitHandler_GetLimits(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		userId := uint(1)
-		limits := []dto.LimitResponse{{UserID: 1, TenorMonth: 12, LimitAmount: 10000}}
+		limits := []dto.LimitResponse{{UserID: 1, TenorMonth: 12, LimitAmount: money.FromRupiah(10000)}}
 		var totalCount int64 = 1
 
 		// Handler now uses GetLimitsPaginated with default page=1, limit=20
@@ -108,3 +110,44 @@ func TestLimitHandler_DeleteLimit(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 }
+
+func TestLimitHandler_GetMutations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLimitService := mock.NewMockLimitService(ctrl)
+	limitHandler := handler.NewLimitHandler(mockLimitService)
+
+	t.Run("Success", func(t *testing.T) {
+		userId := uint(2)
+		mockLimitService.EXPECT().GetMutationsPaginated(userId, gomock.Any()).DoAndReturn(
+			func(_ uint, query dto.LimitMutationQuery) ([]entity.LimitMutation, int64, error) {
+				assert.Equal(t, 2, query.Page)
+				assert.Equal(t, 20, query.Limit)
+				assert.Equal(t, "USAGE", query.Action)
+				assert.Equal(t, "2026-01-01", query.StartDate.Format("2006-01-02"))
+				return []entity.LimitMutation{{ID: 1, Action: entity.MutationUsage}}, int64(21), nil
+			})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/api/limit/mutations?page=2&action=USAGE&start_date=2026-01-01", nil)
+		c.Set("user_id", userId)
+
+		limitHandler.GetMutations(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("InvalidAction", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/api/limit/mutations?action=HACK", nil)
+		c.Set("user_id", uint(2))
+
+		limitHandler.GetMutations(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+)
+
+// writeListError maps listing errors to HTTP status codes; a bad cursor is the client's fault
+func writeListError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+)
+
+type PaymentHandler struct {
+	paymentService services.PaymentService
+}
+
+func NewPaymentHandler(paymentService services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+func (h *PaymentHandler) CreatePayment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	var req dto.CreatePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userId := c.GetUint("user_id")
+	payment, err := h.paymentService.CreatePayment(userId, id, req)
+	if err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Payment recorded successfully",
+		"data":    payment,
+	})
+
+	logger.AuditLogger.Info().
+		Str("action", "create_payment").
+		Uint("user_id", userId).
+		Uint64("transaction_id", id).
+		Str("amount", req.Amount.String()).
+		Msg("Payment recorded")
+}
+
+func (h *PaymentHandler) GetPayments(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	userId := c.GetUint("user_id")
+	payments, err := h.paymentService.GetPayments(userId, id)
+	if err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": payments})
+}
+
+func (h *PaymentHandler) GetPayoffQuote(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	quote, err := h.paymentService.GetPayoffQuote(c.GetUint("user_id"), id)
+	if err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": quote})
+}
+
+func (h *PaymentHandler) SettleTransaction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	var req dto.SettleTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userId := c.GetUint("user_id")
+	payment, err := h.paymentService.SettleTransaction(userId, id, req)
+	if err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Transaction settled successfully",
+		"data":    payment,
+	})
+
+	logger.AuditLogger.Info().
+		Str("action", "settle_transaction").
+		Uint("user_id", userId).
+		Uint64("transaction_id", id).
+		Str("amount", req.Amount.String()).
+		Msg("Transaction settled early")
+}
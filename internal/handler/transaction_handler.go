@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hadi-projects/xyz-finance-go/internal/dto"
@@ -25,43 +28,250 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	}
 
 	userId := c.GetUint("user_id")
-	if err := h.transactionService.CreateTransaction(userId, req); err != nil {
+	transaction, err := h.transactionService.CreateTransaction(userId, req)
+	if err != nil {
 		if err.Error() == "insufficient limit" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, services.ErrPricingMismatch) || errors.Is(err, services.ErrUnknownProduct) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrDuplicateContractNumber) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrCollectibilityNotEligible) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Transaction created successfully"})
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Transaction created successfully",
+		"data":    transaction,
+	})
 
 	logger.AuditLogger.Info().
 		Str("action", "create_transaction").
 		Uint("user_id", userId).
-		Float64("otr", req.OTR).
-		Float64("installment", req.InstallmentAmount).
+		Str("contract_number", transaction.ContractNumber).
+		Str("otr", transaction.OTR.String()).
+		Str("installment", transaction.InstallmentAmount.String()).
 		Int("tenor", req.Tenor).
 		Msg("Transaction created")
 }
 
+func (h *TransactionHandler) SimulateTransaction(c *gin.Context) {
+	var req dto.SimulateTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := h.transactionService.SimulateTransaction(req)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownProduct) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": quote})
+}
+
 func (h *TransactionHandler) GetTransactions(c *gin.Context) {
 	userId := c.GetUint("user_id")
 
-	// Parse pagination params
-	var paginationReq dto.PaginationRequest
-	if err := c.ShouldBindQuery(&paginationReq); err != nil {
+	var query dto.TransactionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	paginationReq.SetDefaults()
+	query.SetDefaults()
+
+	if !query.StartDate.IsZero() && !query.EndDate.IsZero() && query.EndDate.Before(query.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return
+	}
+	if query.MinOTR != 0 && query.MaxOTR != 0 && query.MaxOTR < query.MinOTR {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_otr must not be below min_otr"})
+		return
+	}
+
+	if query.UsesCursor() {
+		transactions, meta, err := h.transactionService.GetTransactionsByCursor(userId, query)
+		if err != nil {
+			writeListError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.PaginatedResponse{Data: transactions, Pagination: meta})
+		return
+	}
 
-	// Get paginated transactions
-	transactions, total, err := h.transactionService.GetTransactionsPaginated(userId, paginationReq.Page, paginationReq.Limit)
+	transactions, total, err := h.transactionService.GetTransactionsPaginated(userId, query)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.NewPaginatedResponse(transactions, paginationReq.Page, paginationReq.Limit, total))
+	c.JSON(http.StatusOK, dto.NewPaginatedResponse(transactions, query.Page, query.Limit, total))
+}
+
+func (h *TransactionHandler) GetTransaction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	transaction, err := h.transactionService.GetTransaction(c.GetUint("user_id"), id)
+	if err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": transaction})
+}
+
+func (h *TransactionHandler) ApproveTransaction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	adminId := c.GetUint("user_id")
+	if err := h.transactionService.ApproveTransaction(adminId, id); err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Transaction approved successfully"})
+
+	logger.AuditLogger.Info().
+		Str("action", "approve_transaction").
+		Uint("admin_id", adminId).
+		Uint64("transaction_id", id).
+		Msg("Transaction approved")
+}
+
+func (h *TransactionHandler) RejectTransaction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	var req dto.RejectTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminId := c.GetUint("user_id")
+	if err := h.transactionService.RejectTransaction(adminId, id, req.Reason); err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Transaction rejected successfully"})
+
+	logger.AuditLogger.Info().
+		Str("action", "reject_transaction").
+		Uint("admin_id", adminId).
+		Uint64("transaction_id", id).
+		Str("reason", req.Reason).
+		Msg("Transaction rejected")
+}
+
+func (h *TransactionHandler) DisburseTransaction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	adminId := c.GetUint("user_id")
+	if err := h.transactionService.DisburseTransaction(adminId, id); err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Transaction disbursed successfully"})
+
+	logger.AuditLogger.Info().
+		Str("action", "disburse_transaction").
+		Uint("admin_id", adminId).
+		Uint64("transaction_id", id).
+		Msg("Transaction disbursed")
+}
+
+func (h *TransactionHandler) CancelTransaction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	// The body (reason) is optional
+	var req dto.CancelTransactionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userId := c.GetUint("user_id")
+	if err := h.transactionService.CancelTransaction(userId, id, req.Reason); err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Transaction cancelled successfully"})
+
+	logger.AuditLogger.Info().
+		Str("action", "cancel_transaction").
+		Uint("user_id", userId).
+		Uint64("transaction_id", id).
+		Str("reason", req.Reason).
+		Msg("Transaction cancelled")
+}
+
+func (h *TransactionHandler) GetInstallments(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	userId := c.GetUint("user_id")
+	installments, err := h.transactionService.GetInstallments(userId, id)
+	if err != nil {
+		writeTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": installments})
+}
+
+// writeTransactionError maps transaction service errors to HTTP status codes
+func writeTransactionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTransactionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidStatusTransition), errors.Is(err, services.ErrTransactionNotPayable),
+		errors.Is(err, services.ErrCancellationNotAllowed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPaymentExceedsOutstanding), errors.Is(err, services.ErrSettlementAmountMismatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
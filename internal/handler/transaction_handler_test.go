@@ -7,12 +7,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hadi-projects/xyz-finance-go/internal/dto"
 	"github.com/hadi-projects/xyz-finance-go/internal/entity"
 	"github.com/hadi-projects/xyz-finance-go/internal/handler"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
 	"github.com/hadi-projects/xyz-finance-go/internal/service/mock"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -28,17 +31,17 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		req := dto.CreateTransactionRequest{
 			ContractNumber:    "CTR-001",
-			OTR:               10000,
-			AdminFee:          500,
-			InstallmentAmount: 1100,
-			InterestAmount:    100,
+			OTR:               money.FromRupiah(10000),
+			AdminFee:          money.FromRupiah(500),
+			InstallmentAmount: money.FromRupiah(1100),
+			InterestAmount:    money.FromRupiah(100),
 			AssetName:         "Item1",
 			Tenor:             1,
 		}
 		body, _ := json.Marshal(req)
 		userId := uint(1)
 
-		mockTxService.EXPECT().CreateTransaction(userId, req).Return(nil)
+		mockTxService.EXPECT().CreateTransaction(userId, req).Return(&entity.Transaction{ID: 1, ContractNumber: req.ContractNumber}, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -53,17 +56,17 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 	t.Run("ServiceError", func(t *testing.T) {
 		req := dto.CreateTransactionRequest{
 			ContractNumber:    "CTR-001",
-			OTR:               10000,
-			AdminFee:          500,
-			InstallmentAmount: 1100,
-			InterestAmount:    100,
+			OTR:               money.FromRupiah(10000),
+			AdminFee:          money.FromRupiah(500),
+			InstallmentAmount: money.FromRupiah(1100),
+			InterestAmount:    money.FromRupiah(100),
 			AssetName:         "Item1",
 			Tenor:             1,
 		}
 		body, _ := json.Marshal(req)
 		userId := uint(1)
 
-		mockTxService.EXPECT().CreateTransaction(userId, req).Return(errors.New("insufficient limit"))
+		mockTxService.EXPECT().CreateTransaction(userId, req).Return(nil, errors.New("insufficient limit"))
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -76,6 +79,23 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 
 	})
 
+	t.Run("CollectibilityNotEligible", func(t *testing.T) {
+		req := dto.CreateTransactionRequest{OTR: money.FromRupiah(10000), AssetName: "Item1", Tenor: 1}
+		body, _ := json.Marshal(req)
+		userId := uint(1)
+
+		mockTxService.EXPECT().CreateTransaction(userId, req).Return(nil, services.ErrCollectibilityNotEligible)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/transaction/", bytes.NewBuffer(body))
+		c.Set("user_id", userId)
+
+		txHandler.CreateTransaction(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
 	t.Run("Unauthorized", func(t *testing.T) {
 		req := dto.CreateTransactionRequest{}
 		body, _ := json.Marshal(req)
@@ -116,7 +136,8 @@ func TestTransactionHandler_GetTransactions(t *testing.T) {
 		userId := uint(1)
 		var totalCount int64 = 2
 		// Handler now uses GetTransactionsPaginated with default page=1, limit=20
-		mockTxService.EXPECT().GetTransactionsPaginated(userId, 1, 20).Return([]entity.Transaction{
+		defaults := dto.TransactionQuery{PaginationRequest: dto.PaginationRequest{Page: 1, Limit: 20}}
+		mockTxService.EXPECT().GetTransactionsPaginated(userId, defaults).Return([]entity.Transaction{
 			{ID: 1, ContractNumber: "CTR-001"},
 			{ID: 2, ContractNumber: "CTR-002"},
 		}, totalCount, nil)
@@ -133,7 +154,7 @@ func TestTransactionHandler_GetTransactions(t *testing.T) {
 
 	t.Run("ServiceError", func(t *testing.T) {
 		userId := uint(1)
-		mockTxService.EXPECT().GetTransactionsPaginated(userId, 1, 20).Return(nil, int64(0), errors.New("db error"))
+		mockTxService.EXPECT().GetTransactionsPaginated(userId, gomock.Any()).Return(nil, int64(0), errors.New("db error"))
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -145,3 +166,279 @@ func TestTransactionHandler_GetTransactions(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
 }
+
+func TestTransactionHandler_GetTransactionsFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxService := mock.NewMockTransactionService(ctrl)
+	txHandler := handler.NewTransactionHandler(mockTxService)
+
+	t.Run("BindsFilters", func(t *testing.T) {
+		userId := uint(1)
+		mockTxService.EXPECT().GetTransactionsPaginated(userId, gomock.Any()).
+			DoAndReturn(func(_ uint, query dto.TransactionQuery) ([]entity.Transaction, int64, error) {
+				assert.Equal(t, "active", query.Status)
+				assert.Equal(t, 6, query.Tenor)
+				assert.Equal(t, "honda", query.AssetName)
+				assert.Equal(t, money.FromRupiah(1000000), query.MinOTR)
+				assert.Equal(t, money.MustParse("2500000.50"), query.MaxOTR)
+				assert.Equal(t, "2026-01-01", query.StartDate.Format(time.DateOnly))
+				assert.Equal(t, "otr_desc", query.Sort)
+				assert.Equal(t, 2, query.Page)
+				return nil, 0, nil
+			})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/api/transaction/?status=active&tenor=6&asset_name=honda&min_otr=1000000&max_otr=2500000.50&start_date=2026-01-01&sort=otr_desc&page=2", nil)
+		c.Set("user_id", userId)
+
+		txHandler.GetTransactions(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	for name, rawQuery := range map[string]string{
+		"InvalidStatus":   "status=unknown",
+		"InvalidSort":     "sort=id_desc",
+		"InvalidAmount":   "min_otr=abc",
+		"InvertedOTR":     "min_otr=2000000&max_otr=1000000",
+		"InvertedDates":   "start_date=2026-02-01&end_date=2026-01-01",
+		"InvalidDateForm": "start_date=01-01-2026",
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/api/transaction/?"+rawQuery, nil)
+			c.Set("user_id", uint(1))
+
+			txHandler.GetTransactions(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestTransactionHandler_GetTransactionsByCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxService := mock.NewMockTransactionService(ctrl)
+	txHandler := handler.NewTransactionHandler(mockTxService)
+
+	t.Run("Success", func(t *testing.T) {
+		userId := uint(1)
+		mockTxService.EXPECT().GetTransactionsByCursor(userId, gomock.Any()).
+			Return([]entity.Transaction{{ID: 9}}, dto.NewCursorPaginationMeta(20, "next", ""), nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/api/transaction/?pagination=cursor", nil)
+		c.Set("user_id", userId)
+
+		txHandler.GetTransactions(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body dto.PaginatedResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "next", body.Pagination.NextCursor)
+		assert.True(t, body.Pagination.HasNext)
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		userId := uint(1)
+		mockTxService.EXPECT().GetTransactionsByCursor(userId, gomock.Any()).
+			Return(nil, dto.PaginationMeta{}, services.ErrInvalidCursor)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/api/transaction/?cursor=garbage", nil)
+		c.Set("user_id", userId)
+
+		txHandler.GetTransactions(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTransactionHandler_GetTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxService := mock.NewMockTransactionService(ctrl)
+	txHandler := handler.NewTransactionHandler(mockTxService)
+
+	t.Run("Success", func(t *testing.T) {
+		userId := uint(2)
+		mockTxService.EXPECT().GetTransaction(userId, uint64(10)).Return(&entity.Transaction{ID: 10, UserID: userId}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/api/transaction/10", nil)
+		c.Params = gin.Params{{Key: "id", Value: "10"}}
+		c.Set("user_id", userId)
+
+		txHandler.GetTransaction(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		userId := uint(3)
+		mockTxService.EXPECT().GetTransaction(userId, uint64(10)).Return(nil, services.ErrTransactionNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/api/transaction/10", nil)
+		c.Params = gin.Params{{Key: "id", Value: "10"}}
+		c.Set("user_id", userId)
+
+		txHandler.GetTransaction(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestTransactionHandler_ApproveTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxService := mock.NewMockTransactionService(ctrl)
+	txHandler := handler.NewTransactionHandler(mockTxService)
+
+	t.Run("Success", func(t *testing.T) {
+		adminId := uint(1)
+		mockTxService.EXPECT().ApproveTransaction(adminId, uint64(10)).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/transaction/10/approve", nil)
+		c.Params = gin.Params{{Key: "id", Value: "10"}}
+		c.Set("user_id", adminId)
+
+		txHandler.ApproveTransaction(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("InvalidTransition", func(t *testing.T) {
+		adminId := uint(1)
+		mockTxService.EXPECT().ApproveTransaction(adminId, uint64(11)).Return(services.ErrInvalidStatusTransition)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/transaction/11/approve", nil)
+		c.Params = gin.Params{{Key: "id", Value: "11"}}
+		c.Set("user_id", adminId)
+
+		txHandler.ApproveTransaction(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("InvalidID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/transaction/abc/approve", nil)
+		c.Params = gin.Params{{Key: "id", Value: "abc"}}
+
+		txHandler.ApproveTransaction(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTransactionHandler_SimulateTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxService := mock.NewMockTransactionService(ctrl)
+	txHandler := handler.NewTransactionHandler(mockTxService)
+
+	t.Run("Success", func(t *testing.T) {
+		req := dto.SimulateTransactionRequest{OTR: money.FromRupiah(600000), Tenor: 6}
+		body, _ := json.Marshal(req)
+
+		mockTxService.EXPECT().SimulateTransaction(req).Return(&dto.TransactionQuote{OTR: money.FromRupiah(600000), Tenor: 6, InstallmentAmount: money.MustParse("107666.66")}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/transaction/simulate", bytes.NewBuffer(body))
+
+		txHandler.SimulateTransaction(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"installment_amount":107666.66`)
+	})
+
+	t.Run("InvalidTenor", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/transaction/simulate", bytes.NewBufferString(`{"otr": 600000, "tenor": -1}`))
+
+		txHandler.SimulateTransaction(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTransactionHandler_CancelTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxService := mock.NewMockTransactionService(ctrl)
+	txHandler := handler.NewTransactionHandler(mockTxService)
+
+	t.Run("Success", func(t *testing.T) {
+		userId := uint(2)
+		mockTxService.EXPECT().CancelTransaction(userId, uint64(10), "changed my mind").Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/transaction/10/cancel", bytes.NewBufferString(`{"reason":"changed my mind"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: "10"}}
+		c.Set("user_id", userId)
+
+		txHandler.CancelTransaction(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("WithoutBody", func(t *testing.T) {
+		userId := uint(2)
+		mockTxService.EXPECT().CancelTransaction(userId, uint64(11), "").Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/transaction/11/cancel", nil)
+		c.Params = gin.Params{{Key: "id", Value: "11"}}
+		c.Set("user_id", userId)
+
+		txHandler.CancelTransaction(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		userId := uint(2)
+		mockTxService.EXPECT().CancelTransaction(userId, uint64(12), "").Return(services.ErrCancellationNotAllowed)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/api/transaction/12/cancel", nil)
+		c.Params = gin.Params{{Key: "id", Value: "12"}}
+		c.Set("user_id", userId)
+
+		txHandler.CancelTransaction(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -1,20 +1,35 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hadi-projects/xyz-finance-go/internal/dto"
 	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+	"gorm.io/gorm"
 )
 
 type UserHandler struct {
-	userRepo repository.UserRepository
+	userRepo        repository.UserRepository
+	transactionRepo repository.TransactionRepository
+	collectibility  *services.CollectibilityClassifier
+	documentService services.DocumentService
+	userService     services.UserService
+	jwtService      services.JWTService
 }
 
-func NewUserHandler(userRepo repository.UserRepository) *UserHandler {
+func NewUserHandler(userRepo repository.UserRepository, transactionRepo repository.TransactionRepository, collectibility *services.CollectibilityClassifier, documentService services.DocumentService, userService services.UserService, jwtService services.JWTService) *UserHandler {
 	return &UserHandler{
-		userRepo: userRepo,
+		userRepo:        userRepo,
+		transactionRepo: transactionRepo,
+		collectibility:  collectibility,
+		documentService: documentService,
+		userService:     userService,
+		jwtService:      jwtService,
 	}
 }
 
@@ -25,39 +40,201 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userRepo.FindByID(userId.(uint))
+	var query dto.ProfileQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	viewerID := userId.(uint)
+	user, err := h.userRepo.FindByID(viewerID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Build base URL for static files
-	scheme := "http"
-	if c.Request.TLS != nil {
-		scheme = "https"
+	// Admins may look up any user's profile; user_id is ignored for everyone else
+	isAdmin := user.Role.Name == "admin"
+	if isAdmin && query.UserID != 0 && query.UserID != user.ID {
+		user, err = h.userRepo.FindByID(query.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// KYC images are only reachable through short-lived links signed for the viewer
+	profile := dto.UserProfileResponse{
+		UserID: user.ID,
+		Email:  user.Email,
+		Consumer: func() *dto.ConsumerResponse {
+			if user.Consumer != nil {
+				documents := h.documentService.SignURLs(viewerID, user.Consumer)
+				return &dto.ConsumerResponse{
+					NIK:            user.Consumer.NIK,
+					FullName:       user.Consumer.FullName,
+					LegalName:      user.Consumer.LegalName,
+					PlaceOfBirth:   user.Consumer.PlaceOfBirth,
+					DateOfBirth:    user.Consumer.DateOfBirth,
+					Salary:         user.Consumer.Salary,
+					KTPImage:       documents.KTPImage,
+					SelfieImage:    documents.SelfieImage,
+					ImagesExpireAt: documents.ExpiresAt,
+					KYCStatus:      string(user.Consumer.KYCStatus),
+				}
+			}
+			return nil
+		}(),
+	}
+
+	// The collectibility grade and NIK check are for staff only
+	if isAdmin && user.Consumer != nil {
+		profile.Consumer.NIKMismatch = user.Consumer.NIKMismatch
+
+		grade, err := h.collectibility.ConsumerGrade(h.transactionRepo, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		profile.Collectibility = int(grade)
 	}
-	baseURL := scheme + "://" + c.Request.Host
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Get User Profile",
-		"data": dto.UserProfileResponse{
-			UserID: user.ID,
-			Email:  user.Email,
-			Consumer: func() *dto.ConsumerResponse {
-				if user.Consumer != nil {
-					return &dto.ConsumerResponse{
-						NIK:          user.Consumer.NIK,
-						FullName:     user.Consumer.FullName,
-						LegalName:    user.Consumer.LegalName,
-						PlaceOfBirth: user.Consumer.PlaceOfBirth,
-						DateOfBirth:  user.Consumer.DateOfBirth,
-						Salary:       user.Consumer.Salary,
-						KTPImage:     baseURL + "/uploads/ktp/" + user.Consumer.KTPImage,
-						SelfieImage:  baseURL + "/uploads/selfie/" + user.Consumer.SelfieImage,
-					}
-				}
-				return nil
-			}(),
-		},
+		"data":    profile,
 	})
 }
+
+// ChangeRole moves a user to another role; the user's sessions are revoked so the change
+// takes effect immediately
+func (h *UserHandler) ChangeRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req dto.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminId := c.GetUint("user_id")
+	user, err := h.userService.ChangeRole(adminId, uint(id), req.Role)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	if err := h.jwtService.RevokeAllSessions(user.ID); err != nil {
+		writeUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role changed successfully"})
+
+	logger.AuditLogger.Info().
+		Str("action", "change_role").
+		Uint("admin_id", adminId).
+		Uint("user_id", user.ID).
+		Str("role", user.Role.Name).
+		Msg("User role changed")
+}
+
+// SuspendUser blocks a user from logging in and revokes all of their sessions
+func (h *UserHandler) SuspendUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	adminId := c.GetUint("user_id")
+	if err := h.userService.Suspend(adminId, uint(id)); err != nil {
+		writeUserError(c, err)
+		return
+	}
+	if err := h.jwtService.RevokeAllSessions(uint(id)); err != nil {
+		writeUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User suspended successfully"})
+
+	logger.AuditLogger.Info().
+		Str("action", "suspend_user").
+		Uint("admin_id", adminId).
+		Uint64("user_id", id).
+		Msg("User suspended")
+}
+
+// UnsuspendUser lets a suspended user log in again
+func (h *UserHandler) UnsuspendUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	adminId := c.GetUint("user_id")
+	if err := h.userService.Unsuspend(adminId, uint(id)); err != nil {
+		writeUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unsuspended successfully"})
+
+	logger.AuditLogger.Info().
+		Str("action", "unsuspend_user").
+		Uint("admin_id", adminId).
+		Uint64("user_id", id).
+		Msg("User unsuspended")
+}
+
+// UnlockLogin lifts the lockout of a user who reached the failed login limit
+func (h *UserHandler) UnlockLogin(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	adminId := c.GetUint("user_id")
+	user, err := h.userService.UnlockLogin(uint(id))
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Login unlocked successfully"})
+
+	logger.AuditLogger.Info().
+		Str("action", "unlock_login").
+		Uint("admin_id", adminId).
+		Uint("user_id", user.ID).
+		Msg("User login unlocked")
+	authEvent(c, logger.AuthLogger.Info(), authActionUnlock, user.ID).
+		Str("email", user.Email).
+		Uint("admin_id", adminId).
+		Msg("Account lockout lifted by admin")
+}
+
+// writeUserError maps user service errors to HTTP status codes
+func writeUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRoleNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCannotModifySelf):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUserAlreadySuspended), errors.Is(err, services.ErrUserNotSuspended):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header clients use to make a POST safe to retry
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayHeader is set on responses replayed from a previous request
+	IdempotentReplayHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyRecord is what an IdempotencyStore keeps per key. Completed is false while the
+// first request is still being processed.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// IdempotencyStore persists idempotency records
+type IdempotencyStore interface {
+	// Reserve atomically claims the key for a request with the given fingerprint.
+	// It returns nil when the key was claimed, or the existing record when it was already taken.
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error)
+	// Complete stores the final response for a claimed key
+	Complete(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+	// Release frees a claimed key so the request can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// idempotencyWriter captures the response body so it can be stored for replays
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency returns a middleware that honours the Idempotency-Key header.
+// The first request with a key is processed normally and its response stored for ttl;
+// repeats with the same body get the stored response, repeats with a different body are rejected.
+// While the first request runs the key is only held for pendingTTL, which should cover the request
+// timeout, so a key whose request never finished (crash, cancellation) can be retried soon.
+// Requests without the header are passed through unchanged. Must run after JWTAuth, keys are scoped per user.
+func Idempotency(store IdempotencyStore, pendingTTL, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientKey := c.GetHeader(IdempotencyKeyHeader)
+		if clientKey == "" || store == nil {
+			c.Next()
+			return
+		}
+
+		if len(clientKey) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength)})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := context.Background()
+		key := idempotencyScopedKey(c.GetUint("user_id"), c.Request.Method, c.Request.URL.Path, clientKey)
+		fingerprint := sha256Hex(body)
+
+		existing, err := store.Reserve(ctx, key, fingerprint, pendingTTL)
+		if err != nil {
+			// Don't block the request when the store is unavailable
+			logger.SystemLogger.Warn().Err(err).Msg("Failed to reserve idempotency key")
+			c.Next()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": IdempotencyKeyHeader + " was already used with a different request body"})
+			case !existing.Completed:
+				c.JSON(http.StatusConflict, gin.H{"error": "A request with this " + IdempotencyKeyHeader + " is still being processed"})
+			default:
+				c.Header(IdempotentReplayHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusRequestTimeout {
+			// The request may not have been applied; let the client retry with the same key
+			if err := store.Release(ctx, key); err != nil {
+				logger.SystemLogger.Warn().Err(err).Msg("Failed to release idempotency key")
+			}
+			return
+		}
+
+		record := &IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			StatusCode:  status,
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}
+		if err := store.Complete(ctx, key, record, ttl); err != nil {
+			logger.SystemLogger.Warn().Err(err).Msg("Failed to store idempotent response")
+		}
+	}
+}
+
+// idempotencyScopedKey ties a client key to the user and route it was sent to
+func idempotencyScopedKey(userID uint, method, path, clientKey string) string {
+	return sha256Hex([]byte(fmt.Sprintf("%d|%s|%s|%s", userID, method, path, clientKey)))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	"github.com/hadi-projects/xyz-finance-go/pkg/cache"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const idempotencyCachePrefix = "idempotency:"
+
+var errIdempotencyKeyVanished = errors.New("idempotency key expired while being reserved")
+
+type redisIdempotencyStore struct {
+	redis *cache.RedisClient
+}
+
+// NewRedisIdempotencyStore stores idempotency records in Redis
+func NewRedisIdempotencyStore(redisClient *cache.RedisClient) IdempotencyStore {
+	return &redisIdempotencyStore{redis: redisClient}
+}
+
+func (s *redisIdempotencyStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error) {
+	data, err := json.Marshal(IdempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	reserved, err := s.redis.SetNX(ctx, idempotencyCachePrefix+key, string(data), ttl)
+	if err != nil || reserved {
+		return nil, err
+	}
+
+	stored, err := s.redis.Get(ctx, idempotencyCachePrefix+key)
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errIdempotencyKeyVanished
+		}
+		return nil, err
+	}
+
+	var record IdempotencyRecord
+	if err := json.Unmarshal([]byte(stored), &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *redisIdempotencyStore) Complete(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.redis.Set(ctx, idempotencyCachePrefix+key, string(data), ttl)
+}
+
+func (s *redisIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.redis.Delete(ctx, idempotencyCachePrefix+key)
+}
+
+type dbIdempotencyStore struct {
+	repo repository.IdempotencyKeyRepository
+}
+
+// NewDBIdempotencyStore stores idempotency records in the idempotency_keys table.
+// Used when Redis is not available.
+func NewDBIdempotencyStore(repo repository.IdempotencyKeyRepository) IdempotencyStore {
+	return &dbIdempotencyStore{repo: repo}
+}
+
+func (s *dbIdempotencyStore) Reserve(_ context.Context, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error) {
+	existing, err := s.reserve(key, fingerprint, ttl)
+	if err != nil || existing == nil || existing.ExpiresAt.After(time.Now()) {
+		return toIdempotencyRecord(existing), err
+	}
+
+	// Expired rows are not cleaned up eagerly; drop it and claim the key once more
+	if err := s.repo.DeleteIfExpired(key); err != nil {
+		return nil, err
+	}
+	existing, err = s.reserve(key, fingerprint, ttl)
+	return toIdempotencyRecord(existing), err
+}
+
+// reserve tries to claim the key and returns the existing row when it is already taken
+func (s *dbIdempotencyStore) reserve(key, fingerprint string, ttl time.Duration) (*entity.IdempotencyKey, error) {
+	reserved, err := s.repo.CreateIfAbsent(&entity.IdempotencyKey{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   time.Now().Add(ttl),
+	})
+	if err != nil || reserved {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByKey(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errIdempotencyKeyVanished
+		}
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (s *dbIdempotencyStore) Complete(_ context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	return s.repo.SaveResponse(&entity.IdempotencyKey{
+		Key:          key,
+		StatusCode:   record.StatusCode,
+		ContentType:  record.ContentType,
+		ResponseBody: record.Body,
+		ExpiresAt:    time.Now().Add(ttl),
+	})
+}
+
+func (s *dbIdempotencyStore) Release(_ context.Context, key string) error {
+	return s.repo.Delete(key)
+}
+
+func toIdempotencyRecord(row *entity.IdempotencyKey) *IdempotencyRecord {
+	if row == nil {
+		return nil
+	}
+	return &IdempotencyRecord{
+		Fingerprint: row.Fingerprint,
+		Completed:   row.Completed,
+		StatusCode:  row.StatusCode,
+		ContentType: row.ContentType,
+		Body:        row.ResponseBody,
+	}
+}
@@ -0,0 +1,122 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadi-projects/xyz-finance-go/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryIdempotencyStore is a minimal in-memory IdempotencyStore for tests
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]middleware.IdempotencyRecord
+	ttls    map[string]time.Duration
+}
+
+func (s *memoryIdempotencyStore) Reserve(_ context.Context, key, fingerprint string, ttl time.Duration) (*middleware.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[key]; ok {
+		return &record, nil
+	}
+	s.records[key] = middleware.IdempotencyRecord{Fingerprint: fingerprint}
+	s.ttls[key] = ttl
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, key string, record *middleware.IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = *record
+	s.ttls[key] = ttl
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var store *memoryIdempotencyStore
+	setup := func(status int) (*gin.Engine, *int) {
+		store = &memoryIdempotencyStore{records: map[string]middleware.IdempotencyRecord{}, ttls: map[string]time.Duration{}}
+		calls := 0
+		r := gin.New()
+		r.POST("/api/transaction/", func(c *gin.Context) {
+			c.Set("user_id", uint(2))
+		}, middleware.Idempotency(store, time.Minute, time.Hour), func(c *gin.Context) {
+			calls++
+			for _, ttl := range store.ttls {
+				assert.Equal(t, time.Minute, ttl, "the key is held for the pending TTL while the request runs")
+			}
+			c.JSON(status, gin.H{"call": calls})
+		})
+		return r, &calls
+	}
+
+	send := func(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/transaction/", bytes.NewBufferString(body))
+		if key != "" {
+			req.Header.Set(middleware.IdempotencyKeyHeader, key)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("ReplaysStoredResponse", func(t *testing.T) {
+		r, calls := setup(http.StatusCreated)
+
+		first := send(r, "key-1", `{"otr":1000}`)
+		second := send(r, "key-1", `{"otr":1000}`)
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "true", second.Header().Get(middleware.IdempotentReplayHeader))
+		for _, ttl := range store.ttls {
+			assert.Equal(t, time.Hour, ttl, "the stored response is kept for the full TTL")
+		}
+	})
+
+	t.Run("RejectsDifferentBody", func(t *testing.T) {
+		r, calls := setup(http.StatusCreated)
+
+		send(r, "key-1", `{"otr":1000}`)
+		w := send(r, "key-1", `{"otr":2000}`)
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("ReleasesKeyOnServerError", func(t *testing.T) {
+		r, calls := setup(http.StatusInternalServerError)
+
+		send(r, "key-1", `{"otr":1000}`)
+		send(r, "key-1", `{"otr":1000}`)
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("WithoutHeader", func(t *testing.T) {
+		r, calls := setup(http.StatusCreated)
+
+		send(r, "", `{"otr":1000}`)
+		send(r, "", `{"otr":1000}`)
+
+		assert.Equal(t, 2, *calls)
+	})
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/hadi-projects/xyz-finance-go/pkg/jwtkeys"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
 )
 
 type JWTClaims struct {
@@ -14,7 +16,9 @@ type JWTClaims struct {
 	jwt.RegisteredClaims
 }
 
-func JWTAuth(jwtSecret string) gin.HandlerFunc {
+// JWTAuth verifies the bearer token against the key set: only RS256 and EdDSA tokens whose kid
+// names a configured key, signed with that key's algorithm, are accepted
+func JWTAuth(keys *jwtkeys.KeySet, revocations TokenRevocationStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -34,9 +38,7 @@ func JWTAuth(jwtSecret string) gin.HandlerFunc {
 		tokenString := parts[1]
 
 		// Parse and validate token
-		token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
+		token, err := keys.Parse(tokenString, &JWTClaims{})
 
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
@@ -45,15 +47,34 @@ func JWTAuth(jwtSecret string) gin.HandlerFunc {
 		}
 
 		// Extract claims
-		if claims, ok := token.Claims.(*JWTClaims); ok {
-			c.Set("user_id", claims.UserID)
-			c.Set("user_email", claims.Email)
-		} else {
+		claims, ok := token.Claims.(*JWTClaims)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
 			c.Abort()
 			return
 		}
 
+		// Logged out, suspended or otherwise revoked tokens are rejected before they expire
+		revoked, err := revocations.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			logger.SystemLogger.Error().Err(err).Msg("Failed to check token revocation")
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify token"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("user_email", claims.Email)
+		c.Set("token_id", claims.ID)
+		if claims.ExpiresAt != nil {
+			c.Set("token_expires_at", claims.ExpiresAt.Time)
+		}
+
 		c.Next()
 	}
 }
@@ -0,0 +1,174 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/middleware"
+	"github.com/hadi-projects/xyz-finance-go/pkg/jwtkeys"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestKeySet returns a key set with one fresh Ed25519 key, "test-key"
+func newTestKeySet(t *testing.T) *jwtkeys.KeySet {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("failed to encode key: %v", err)
+	}
+	keys, err := jwtkeys.NewKeySet(config.JWTConfig{
+		SigningKeys: []config.JWTSigningKey{{ID: "test-key", PEM: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})}},
+		ActiveKeyID: "test-key",
+	})
+	if err != nil {
+		t.Fatalf("failed to create key set: %v", err)
+	}
+	return keys
+}
+
+func signTestToken(t *testing.T, keys *jwtkeys.KeySet, userID uint, tokenID string, issuedAt time.Time) string {
+	claims := middleware.JWTClaims{
+		UserID: userID,
+		Email:  "budi@mail.com",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ID:        tokenID,
+		},
+	}
+	token, err := keys.Sign(claims)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func TestJWTAuth_Revocation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	store := middleware.NewMemoryTokenRevocationStore()
+	keys := newTestKeySet(t)
+
+	r := gin.New()
+	r.Use(middleware.JWTAuth(keys, store))
+	r.GET("/me", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"token_id": c.GetString("token_id")})
+	})
+
+	get := func(token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		w := get(signTestToken(t, keys, 2, "jti-valid", time.Now()))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "jti-valid")
+	})
+
+	t.Run("RevokedToken", func(t *testing.T) {
+		token := signTestToken(t, keys, 2, "jti-revoked", time.Now())
+		assert.NoError(t, store.RevokeToken(ctx, "jti-revoked", time.Now().Add(time.Hour)))
+
+		assert.Equal(t, http.StatusUnauthorized, get(token).Code)
+		assert.Equal(t, http.StatusOK, get(signTestToken(t, keys, 2, "jti-other", time.Now())).Code, "other tokens are unaffected")
+	})
+
+	t.Run("RevokedUser", func(t *testing.T) {
+		now := time.Now()
+		before := signTestToken(t, keys, 3, "jti-before", now.Add(-time.Minute))
+		after := signTestToken(t, keys, 3, "jti-after", now.Add(time.Minute))
+		assert.NoError(t, store.RevokeUserTokens(ctx, 3, now, now.Add(time.Hour)))
+
+		assert.Equal(t, http.StatusUnauthorized, get(before).Code, "tokens issued before the revocation are rejected")
+		assert.Equal(t, http.StatusOK, get(after).Code, "tokens issued afterwards are accepted")
+		assert.Equal(t, http.StatusOK, get(signTestToken(t, keys, 2, "jti-user-2", now.Add(-time.Minute))).Code, "other users are unaffected")
+	})
+
+	t.Run("RevokedUserWithinTheSameSecond", func(t *testing.T) {
+		now := time.Now()
+		before := signTestToken(t, keys, 5, "jti-just-before", now.Add(-10*time.Millisecond))
+		after := signTestToken(t, keys, 5, "jti-just-after", now.Add(10*time.Millisecond))
+		assert.NoError(t, store.RevokeUserTokens(ctx, 5, now, now.Add(time.Hour)))
+
+		assert.Equal(t, http.StatusUnauthorized, get(before).Code)
+		assert.Equal(t, http.StatusOK, get(after).Code, "a login right after logout-all is not revoked")
+	})
+
+	t.Run("ExpiredRevocation", func(t *testing.T) {
+		now := time.Now()
+		assert.NoError(t, store.RevokeUserTokens(ctx, 4, now, now.Add(-time.Second)))
+
+		assert.Equal(t, http.StatusOK, get(signTestToken(t, keys, 4, "jti-4", now.Add(-time.Minute))).Code)
+	})
+}
+
+func TestJWTAuth_Algorithms(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys := newTestKeySet(t)
+
+	r := gin.New()
+	r.Use(middleware.JWTAuth(keys, middleware.NewMemoryTokenRevocationStore()))
+	r.GET("/me", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	get := func(token string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	claims := middleware.JWTClaims{
+		UserID: 2,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	t.Run("HS256", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		token.Header["kid"] = "test-key"
+		signed, err := token.SignedString([]byte("guessed-secret"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, get(signed))
+	})
+
+	t.Run("None", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+		token.Header["kid"] = "test-key"
+		signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, get(signed))
+	})
+
+	t.Run("ForeignKey", func(t *testing.T) {
+		other := newTestKeySet(t)
+		signed, err := other.Sign(claims)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, get(signed), "a token signed with another key of the same kid is rejected")
+	})
+}
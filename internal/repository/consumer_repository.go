@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/pkg/encryption"
+	"gorm.io/gorm"
+)
+
+type ConsumerRepository interface {
+	Create(consumer *entity.Consumer) error
+	Update(consumer *entity.Consumer) error
+	FindByUserID(userID uint) (*entity.Consumer, error)
+	FindByNIK(nik string) (*entity.Consumer, error)
+	FindForKeyRotation(keyID string, afterID uint64, limit int) ([]entity.Consumer, error)
+	FindWithoutNIKHash(afterID uint64, limit int) ([]entity.Consumer, error)
+}
+
+type consumerRepository struct {
+	db   *gorm.DB
+	keys *encryption.KeyRing
+}
+
+// NewConsumerRepository needs the key ring for the NIK blind index; the encrypted columns
+// themselves are handled by the encryption serializer
+func NewConsumerRepository(db *gorm.DB, keys *encryption.KeyRing) ConsumerRepository {
+	return &consumerRepository{db: db, keys: keys}
+}
+
+func (r *consumerRepository) Create(consumer *entity.Consumer) error {
+	r.prepare(consumer)
+	return r.db.Create(consumer).Error
+}
+
+func (r *consumerRepository) Update(consumer *entity.Consumer) error {
+	r.prepare(consumer)
+	return r.db.Save(consumer).Error
+}
+
+func (r *consumerRepository) FindByUserID(userID uint) (*entity.Consumer, error) {
+	var consumer entity.Consumer
+	if err := r.db.Where("user_id = ?", userID).First(&consumer).Error; err != nil {
+		return nil, err
+	}
+	return &consumer, nil
+}
+
+func (r *consumerRepository) FindByNIK(nik string) (*entity.Consumer, error) {
+	var consumer entity.Consumer
+	if err := r.db.Where("nik_hash = ?", r.keys.BlindIndex(nik)).First(&consumer).Error; err != nil {
+		return nil, err
+	}
+	return &consumer, nil
+}
+
+// FindForKeyRotation returns up to limit consumers with an ID above afterID, in ID order, that
+// were not written with keyID (all consumers when keyID is empty)
+func (r *consumerRepository) FindForKeyRotation(keyID string, afterID uint64, limit int) ([]entity.Consumer, error) {
+	var consumers []entity.Consumer
+	query := r.db.Where("id > ?", afterID)
+	if keyID != "" {
+		query = query.Where("(encryption_key_id IS NULL OR encryption_key_id <> ?)", keyID)
+	}
+	err := query.Order("id").Limit(limit).Find(&consumers).Error
+	return consumers, err
+}
+
+// FindWithoutNIKHash returns up to limit consumers with an ID above afterID, in ID order, that
+// have no NIK blind index yet (rows stored before encryption was enabled)
+func (r *consumerRepository) FindWithoutNIKHash(afterID uint64, limit int) ([]entity.Consumer, error) {
+	var consumers []entity.Consumer
+	err := r.db.Where("id > ?", afterID).
+		Where("(nik_hash IS NULL OR nik_hash = '')").
+		Order("id").Limit(limit).Find(&consumers).Error
+	return consumers, err
+}
+
+// prepare sets the columns derived from the plaintext before a write
+func (r *consumerRepository) prepare(consumer *entity.Consumer) {
+	consumer.NIKHash = r.keys.BlindIndex(consumer.NIK)
+	consumer.EncryptionKeyID = r.keys.ActiveKeyID()
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContractSequenceRepository interface {
+	Next(scope string) (uint64, error)
+	WithTx(tx *gorm.DB) ContractSequenceRepository
+}
+
+type contractSequenceRepository struct {
+	db *gorm.DB
+}
+
+func NewContractSequenceRepository(db *gorm.DB) ContractSequenceRepository {
+	return &contractSequenceRepository{db: db}
+}
+
+// Next increments and returns the sequence for scope, creating it on first use.
+// Must run inside a DB transaction: the sequence row stays locked until commit, so concurrent
+// callers get consecutive values and a rolled back transaction does not burn a number.
+func (r *contractSequenceRepository) Next(scope string) (uint64, error) {
+	if err := r.db.Clauses(clause.Insert{Modifier: "IGNORE"}).
+		Create(&entity.ContractSequence{Scope: scope}).Error; err != nil {
+		return 0, err
+	}
+
+	var sequence entity.ContractSequence
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("scope = ?", scope).
+		First(&sequence).Error; err != nil {
+		return 0, err
+	}
+
+	sequence.LastValue++
+	if err := r.db.Model(&sequence).Update("last_value", sequence.LastValue).Error; err != nil {
+		return 0, err
+	}
+
+	return sequence.LastValue, nil
+}
+
+func (r *contractSequenceRepository) WithTx(tx *gorm.DB) ContractSequenceRepository {
+	return &contractSequenceRepository{db: tx}
+}
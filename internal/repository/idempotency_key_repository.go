@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyKeyRepository interface {
+	CreateIfAbsent(record *entity.IdempotencyKey) (bool, error)
+	FindByKey(key string) (*entity.IdempotencyKey, error)
+	SaveResponse(record *entity.IdempotencyKey) error
+	Delete(key string) error
+	DeleteIfExpired(key string) error
+}
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// CreateIfAbsent inserts the record unless the key already exists and reports whether it was inserted.
+// The insert is a single statement, so only one of several concurrent callers can claim a key.
+func (r *idempotencyKeyRepository) CreateIfAbsent(record *entity.IdempotencyKey) (bool, error) {
+	result := r.db.Clauses(clause.Insert{Modifier: "IGNORE"}).Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *idempotencyKeyRepository) FindByKey(key string) (*entity.IdempotencyKey, error) {
+	var record entity.IdempotencyKey
+	if err := r.db.Where("`key` = ?", key).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// SaveResponse marks the key completed with the stored response
+func (r *idempotencyKeyRepository) SaveResponse(record *entity.IdempotencyKey) error {
+	return r.db.Model(&entity.IdempotencyKey{}).
+		Where("`key` = ?", record.Key).
+		Updates(map[string]interface{}{
+			"completed":     true,
+			"status_code":   record.StatusCode,
+			"content_type":  record.ContentType,
+			"response_body": record.ResponseBody,
+			"expires_at":    record.ExpiresAt,
+		}).Error
+}
+
+func (r *idempotencyKeyRepository) Delete(key string) error {
+	return r.db.Where("`key` = ?", key).Delete(&entity.IdempotencyKey{}).Error
+}
+
+func (r *idempotencyKeyRepository) DeleteIfExpired(key string) error {
+	return r.db.Where("`key` = ? AND expires_at < ?", key, time.Now()).Delete(&entity.IdempotencyKey{}).Error
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"gorm.io/gorm"
+)
+
+type InstallmentRepository interface {
+	CreateBatch(installments []entity.Installment) error
+	FindByTransactionID(transactionID uint64) ([]entity.Installment, error)
+	Update(installment *entity.Installment) error
+	CancelUnpaidByTransactionID(transactionID uint64) error
+	WithTx(tx *gorm.DB) InstallmentRepository
+}
+
+type installmentRepository struct {
+	db *gorm.DB
+}
+
+func NewInstallmentRepository(db *gorm.DB) InstallmentRepository {
+	return &installmentRepository{db: db}
+}
+
+func (r *installmentRepository) CreateBatch(installments []entity.Installment) error {
+	if len(installments) == 0 {
+		return nil
+	}
+	return r.db.Create(&installments).Error
+}
+
+func (r *installmentRepository) FindByTransactionID(transactionID uint64) ([]entity.Installment, error) {
+	var installments []entity.Installment
+	err := r.db.Where("transaction_id = ?", transactionID).Order("installment_number ASC").Find(&installments).Error
+	return installments, err
+}
+
+func (r *installmentRepository) Update(installment *entity.Installment) error {
+	return r.db.Save(installment).Error
+}
+
+func (r *installmentRepository) CancelUnpaidByTransactionID(transactionID uint64) error {
+	return r.db.Model(&entity.Installment{}).
+		Where("transaction_id = ? AND status = ?", transactionID, entity.InstallmentUnpaid).
+		Update("status", entity.InstallmentCancelled).Error
+}
+
+func (r *installmentRepository) WithTx(tx *gorm.DB) InstallmentRepository {
+	return &installmentRepository{db: tx}
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Keyset is a position in a sorted listing: the sort key of a boundary row plus its id as a
+// tie-breaker. Only the key used by Sort is set. Clients receive it as an opaque cursor.
+type Keyset struct {
+	Sort      string      `json:"s"`
+	ID        uint64      `json:"i"`
+	CreatedAt time.Time   `json:"c,omitempty"`
+	OTR       money.Money `json:"o,omitempty"`
+	Tenor     int         `json:"t,omitempty"`
+	Backward  bool        `json:"b,omitempty"` // the rows before the position rather than after it
+}
+
+// Encode returns the keyset as an opaque, URL-safe cursor
+func (k Keyset) Encode() string {
+	data, _ := json.Marshal(k)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeKeyset parses a cursor produced by Encode
+func DecodeKeyset(cursor string) (Keyset, error) {
+	var k Keyset
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return k, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &k); err != nil || k.ID == 0 {
+		return k, ErrInvalidCursor
+	}
+	return k, nil
+}
+
+// keysetOrder is the column a listing is sorted by, with id breaking ties in the same direction
+type keysetOrder struct {
+	column string
+	desc   bool
+}
+
+// clause returns the ORDER BY, flipped when reading backwards
+func (o keysetOrder) clause(reverse bool) string {
+	dir := "ASC"
+	if o.desc != reverse {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("%s %s, id %s", o.column, dir, dir)
+}
+
+// seek restricts db to the rows after k (before k when k.Backward) in this order.
+// The expanded OR form lets MySQL range-scan the (…, created_at) indexes.
+func (o keysetOrder) seek(db *gorm.DB, k Keyset) *gorm.DB {
+	var value interface{}
+	switch o.column {
+	case "otr":
+		value = k.OTR
+	case "tenor":
+		value = k.Tenor
+	default:
+		value = k.CreatedAt
+	}
+
+	op := ">"
+	if o.desc != k.Backward {
+		op = "<"
+	}
+	return db.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", o.column, op, o.column, op), value, value, k.ID)
+}
+
+// keyset returns the position of a row in this order
+func (o keysetOrder) keyset(sort string, id uint64, createdAt time.Time, otr money.Money, tenor int) Keyset {
+	k := Keyset{Sort: sort, ID: id}
+	switch o.column {
+	case "otr":
+		k.OTR = otr
+	case "tenor":
+		k.Tenor = tenor
+	default:
+		k.CreatedAt = createdAt
+	}
+	return k
+}
+
+// reverse flips rows read backwards back into listing order
+func reverse[T any](rows []T) {
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+}
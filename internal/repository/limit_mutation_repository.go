@@ -1,12 +1,30 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/hadi-projects/xyz-finance-go/internal/entity"
 	"gorm.io/gorm"
 )
 
+// LimitMutationFilter narrows a mutation listing; zero values mean "no filter"
+type LimitMutationFilter struct {
+	UserID       uint
+	Action       entity.MutationAction
+	TenorLimitID uint
+	From         time.Time // inclusive
+	To           time.Time // exclusive
+}
+
+// MutationSort is the only ordering of mutation listings; cursors carry it for validation
+const MutationSort = "created_at_desc"
+
+var mutationOrder = keysetOrder{column: "created_at", desc: true}
+
 type LimitMutationRepository interface {
 	Create(mutation *entity.LimitMutation) error
+	FindPaginated(filter LimitMutationFilter, offset, limit int) ([]entity.LimitMutation, int64, error)
+	FindByCursor(filter LimitMutationFilter, cursor *Keyset, limit int) ([]entity.LimitMutation, bool, error)
 	WithTx(tx *gorm.DB) LimitMutationRepository
 }
 
@@ -22,6 +40,73 @@ func (r *limitMutationRepository) Create(mutation *entity.LimitMutation) error {
 	return r.db.Create(mutation).Error
 }
 
+func (r *limitMutationRepository) FindPaginated(filter LimitMutationFilter, offset, limit int) ([]entity.LimitMutation, int64, error) {
+	var mutations []entity.LimitMutation
+	var total int64
+
+	query := filter.apply(r.db.Model(&entity.LimitMutation{}))
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order(mutationOrder.clause(false)).
+		Offset(offset).
+		Limit(limit).
+		Find(&mutations).Error
+
+	return mutations, total, err
+}
+
+// FindByCursor returns up to limit mutations after cursor (before it when cursor.Backward), or the
+// first page when cursor is nil. The bool reports whether more rows exist in the direction read.
+func (r *limitMutationRepository) FindByCursor(filter LimitMutationFilter, cursor *Keyset, limit int) ([]entity.LimitMutation, bool, error) {
+	var mutations []entity.LimitMutation
+
+	query := filter.apply(r.db.Model(&entity.LimitMutation{}))
+	backward := cursor != nil && cursor.Backward
+	if cursor != nil {
+		query = mutationOrder.seek(query, *cursor)
+	}
+
+	if err := query.Order(mutationOrder.clause(backward)).Limit(limit + 1).Find(&mutations).Error; err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(mutations) > limit
+	if hasMore {
+		mutations = mutations[:limit]
+	}
+	if backward {
+		reverse(mutations)
+	}
+	return mutations, hasMore, nil
+}
+
+// MutationKeyset returns the cursor position of m in a mutation listing
+func MutationKeyset(m entity.LimitMutation) Keyset {
+	return mutationOrder.keyset(MutationSort, uint64(m.ID), m.CreatedAt, 0, 0)
+}
+
 func (r *limitMutationRepository) WithTx(tx *gorm.DB) LimitMutationRepository {
 	return &limitMutationRepository{db: tx}
 }
+
+func (f LimitMutationFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.UserID != 0 {
+		db = db.Where("user_id = ?", f.UserID)
+	}
+	if f.Action != "" {
+		db = db.Where("action = ?", f.Action)
+	}
+	if f.TenorLimitID != 0 {
+		db = db.Where("tenor_limit_id = ?", f.TenorLimitID)
+	}
+	if !f.From.IsZero() {
+		db = db.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		db = db.Where("created_at < ?", f.To)
+	}
+	return db
+}
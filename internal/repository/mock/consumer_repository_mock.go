@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/consumer_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/consumer_repository.go -destination=internal/repository/mock/consumer_repository_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	entity "github.com/hadi-projects/xyz-finance-go/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConsumerRepository is a mock of ConsumerRepository interface.
+type MockConsumerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsumerRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockConsumerRepositoryMockRecorder is the mock recorder for MockConsumerRepository.
+type MockConsumerRepositoryMockRecorder struct {
+	mock *MockConsumerRepository
+}
+
+// NewMockConsumerRepository creates a new mock instance.
+func NewMockConsumerRepository(ctrl *gomock.Controller) *MockConsumerRepository {
+	mock := &MockConsumerRepository{ctrl: ctrl}
+	mock.recorder = &MockConsumerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsumerRepository) EXPECT() *MockConsumerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockConsumerRepository) Create(consumer *entity.Consumer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", consumer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockConsumerRepositoryMockRecorder) Create(consumer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockConsumerRepository)(nil).Create), consumer)
+}
+
+// FindByNIK mocks base method.
+func (m *MockConsumerRepository) FindByNIK(nik string) (*entity.Consumer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByNIK", nik)
+	ret0, _ := ret[0].(*entity.Consumer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByNIK indicates an expected call of FindByNIK.
+func (mr *MockConsumerRepositoryMockRecorder) FindByNIK(nik any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByNIK", reflect.TypeOf((*MockConsumerRepository)(nil).FindByNIK), nik)
+}
+
+// FindByUserID mocks base method.
+func (m *MockConsumerRepository) FindByUserID(userID uint) (*entity.Consumer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", userID)
+	ret0, _ := ret[0].(*entity.Consumer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockConsumerRepositoryMockRecorder) FindByUserID(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockConsumerRepository)(nil).FindByUserID), userID)
+}
+
+// FindForKeyRotation mocks base method.
+func (m *MockConsumerRepository) FindForKeyRotation(keyID string, afterID uint64, limit int) ([]entity.Consumer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindForKeyRotation", keyID, afterID, limit)
+	ret0, _ := ret[0].([]entity.Consumer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindForKeyRotation indicates an expected call of FindForKeyRotation.
+func (mr *MockConsumerRepositoryMockRecorder) FindForKeyRotation(keyID, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindForKeyRotation", reflect.TypeOf((*MockConsumerRepository)(nil).FindForKeyRotation), keyID, afterID, limit)
+}
+
+// FindWithoutNIKHash mocks base method.
+func (m *MockConsumerRepository) FindWithoutNIKHash(afterID uint64, limit int) ([]entity.Consumer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWithoutNIKHash", afterID, limit)
+	ret0, _ := ret[0].([]entity.Consumer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWithoutNIKHash indicates an expected call of FindWithoutNIKHash.
+func (mr *MockConsumerRepositoryMockRecorder) FindWithoutNIKHash(afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWithoutNIKHash", reflect.TypeOf((*MockConsumerRepository)(nil).FindWithoutNIKHash), afterID, limit)
+}
+
+// Update mocks base method.
+func (m *MockConsumerRepository) Update(consumer *entity.Consumer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", consumer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockConsumerRepositoryMockRecorder) Update(consumer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockConsumerRepository)(nil).Update), consumer)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/contract_sequence_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/contract_sequence_repository.go -destination=internal/repository/mock/contract_sequence_repository_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	repository "github.com/hadi-projects/xyz-finance-go/internal/repository"
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockContractSequenceRepository is a mock of ContractSequenceRepository interface.
+type MockContractSequenceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockContractSequenceRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockContractSequenceRepositoryMockRecorder is the mock recorder for MockContractSequenceRepository.
+type MockContractSequenceRepositoryMockRecorder struct {
+	mock *MockContractSequenceRepository
+}
+
+// NewMockContractSequenceRepository creates a new mock instance.
+func NewMockContractSequenceRepository(ctrl *gomock.Controller) *MockContractSequenceRepository {
+	mock := &MockContractSequenceRepository{ctrl: ctrl}
+	mock.recorder = &MockContractSequenceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContractSequenceRepository) EXPECT() *MockContractSequenceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Next mocks base method.
+func (m *MockContractSequenceRepository) Next(scope string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next", scope)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockContractSequenceRepositoryMockRecorder) Next(scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockContractSequenceRepository)(nil).Next), scope)
+}
+
+// WithTx mocks base method.
+func (m *MockContractSequenceRepository) WithTx(tx *gorm.DB) repository.ContractSequenceRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(repository.ContractSequenceRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockContractSequenceRepositoryMockRecorder) WithTx(tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockContractSequenceRepository)(nil).WithTx), tx)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/installment_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/installment_repository.go -destination=internal/repository/mock/installment_repository_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	entity "github.com/hadi-projects/xyz-finance-go/internal/entity"
+	repository "github.com/hadi-projects/xyz-finance-go/internal/repository"
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockInstallmentRepository is a mock of InstallmentRepository interface.
+type MockInstallmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInstallmentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockInstallmentRepositoryMockRecorder is the mock recorder for MockInstallmentRepository.
+type MockInstallmentRepositoryMockRecorder struct {
+	mock *MockInstallmentRepository
+}
+
+// NewMockInstallmentRepository creates a new mock instance.
+func NewMockInstallmentRepository(ctrl *gomock.Controller) *MockInstallmentRepository {
+	mock := &MockInstallmentRepository{ctrl: ctrl}
+	mock.recorder = &MockInstallmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInstallmentRepository) EXPECT() *MockInstallmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CancelUnpaidByTransactionID mocks base method.
+func (m *MockInstallmentRepository) CancelUnpaidByTransactionID(transactionID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelUnpaidByTransactionID", transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelUnpaidByTransactionID indicates an expected call of CancelUnpaidByTransactionID.
+func (mr *MockInstallmentRepositoryMockRecorder) CancelUnpaidByTransactionID(transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelUnpaidByTransactionID", reflect.TypeOf((*MockInstallmentRepository)(nil).CancelUnpaidByTransactionID), transactionID)
+}
+
+// CreateBatch mocks base method.
+func (m *MockInstallmentRepository) CreateBatch(installments []entity.Installment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", installments)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockInstallmentRepositoryMockRecorder) CreateBatch(installments any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockInstallmentRepository)(nil).CreateBatch), installments)
+}
+
+// FindByTransactionID mocks base method.
+func (m *MockInstallmentRepository) FindByTransactionID(transactionID uint64) ([]entity.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTransactionID", transactionID)
+	ret0, _ := ret[0].([]entity.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTransactionID indicates an expected call of FindByTransactionID.
+func (mr *MockInstallmentRepositoryMockRecorder) FindByTransactionID(transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTransactionID", reflect.TypeOf((*MockInstallmentRepository)(nil).FindByTransactionID), transactionID)
+}
+
+// Update mocks base method.
+func (m *MockInstallmentRepository) Update(installment *entity.Installment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", installment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockInstallmentRepositoryMockRecorder) Update(installment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInstallmentRepository)(nil).Update), installment)
+}
+
+// WithTx mocks base method.
+func (m *MockInstallmentRepository) WithTx(tx *gorm.DB) repository.InstallmentRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(repository.InstallmentRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockInstallmentRepositoryMockRecorder) WithTx(tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockInstallmentRepository)(nil).WithTx), tx)
+}
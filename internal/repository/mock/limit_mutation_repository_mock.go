@@ -1,5 +1,10 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: internal/repository/limit_mutation_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/limit_mutation_repository.go -destination=internal/repository/mock/limit_mutation_repository_mock.go -package=mock
+//
 
 // Package mock is a generated GoMock package.
 package mock
@@ -17,6 +22,7 @@ import (
 type MockLimitMutationRepository struct {
 	ctrl     *gomock.Controller
 	recorder *MockLimitMutationRepositoryMockRecorder
+	isgomock struct{}
 }
 
 // MockLimitMutationRepositoryMockRecorder is the mock recorder for MockLimitMutationRepository.
@@ -50,6 +56,38 @@ func (mr *MockLimitMutationRepositoryMockRecorder) Create(mutation any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLimitMutationRepository)(nil).Create), mutation)
 }
 
+// FindByCursor mocks base method.
+func (m *MockLimitMutationRepository) FindByCursor(filter repository.LimitMutationFilter, cursor *repository.Keyset, limit int) ([]entity.LimitMutation, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCursor", filter, cursor, limit)
+	ret0, _ := ret[0].([]entity.LimitMutation)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindByCursor indicates an expected call of FindByCursor.
+func (mr *MockLimitMutationRepositoryMockRecorder) FindByCursor(filter, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCursor", reflect.TypeOf((*MockLimitMutationRepository)(nil).FindByCursor), filter, cursor, limit)
+}
+
+// FindPaginated mocks base method.
+func (m *MockLimitMutationRepository) FindPaginated(filter repository.LimitMutationFilter, offset, limit int) ([]entity.LimitMutation, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPaginated", filter, offset, limit)
+	ret0, _ := ret[0].([]entity.LimitMutation)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPaginated indicates an expected call of FindPaginated.
+func (mr *MockLimitMutationRepositoryMockRecorder) FindPaginated(filter, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPaginated", reflect.TypeOf((*MockLimitMutationRepository)(nil).FindPaginated), filter, offset, limit)
+}
+
 // WithTx mocks base method.
 func (m *MockLimitMutationRepository) WithTx(tx *gorm.DB) repository.LimitMutationRepository {
 	m.ctrl.T.Helper()
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/payment_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/payment_repository.go -destination=internal/repository/mock/payment_repository_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	entity "github.com/hadi-projects/xyz-finance-go/internal/entity"
+	repository "github.com/hadi-projects/xyz-finance-go/internal/repository"
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockPaymentRepository is a mock of PaymentRepository interface.
+type MockPaymentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPaymentRepositoryMockRecorder is the mock recorder for MockPaymentRepository.
+type MockPaymentRepositoryMockRecorder struct {
+	mock *MockPaymentRepository
+}
+
+// NewMockPaymentRepository creates a new mock instance.
+func NewMockPaymentRepository(ctrl *gomock.Controller) *MockPaymentRepository {
+	mock := &MockPaymentRepository{ctrl: ctrl}
+	mock.recorder = &MockPaymentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRepository) EXPECT() *MockPaymentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPaymentRepository) Create(payment *entity.Payment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", payment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPaymentRepositoryMockRecorder) Create(payment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentRepository)(nil).Create), payment)
+}
+
+// FindByTransactionID mocks base method.
+func (m *MockPaymentRepository) FindByTransactionID(transactionID uint64) ([]entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTransactionID", transactionID)
+	ret0, _ := ret[0].([]entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTransactionID indicates an expected call of FindByTransactionID.
+func (mr *MockPaymentRepositoryMockRecorder) FindByTransactionID(transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTransactionID", reflect.TypeOf((*MockPaymentRepository)(nil).FindByTransactionID), transactionID)
+}
+
+// WithTx mocks base method.
+func (m *MockPaymentRepository) WithTx(tx *gorm.DB) repository.PaymentRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(repository.PaymentRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockPaymentRepositoryMockRecorder) WithTx(tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockPaymentRepository)(nil).WithTx), tx)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/penalty_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/penalty_repository.go -destination=internal/repository/mock/penalty_repository_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	entity "github.com/hadi-projects/xyz-finance-go/internal/entity"
+	repository "github.com/hadi-projects/xyz-finance-go/internal/repository"
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockPenaltyRepository is a mock of PenaltyRepository interface.
+type MockPenaltyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPenaltyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPenaltyRepositoryMockRecorder is the mock recorder for MockPenaltyRepository.
+type MockPenaltyRepositoryMockRecorder struct {
+	mock *MockPenaltyRepository
+}
+
+// NewMockPenaltyRepository creates a new mock instance.
+func NewMockPenaltyRepository(ctrl *gomock.Controller) *MockPenaltyRepository {
+	mock := &MockPenaltyRepository{ctrl: ctrl}
+	mock.recorder = &MockPenaltyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPenaltyRepository) EXPECT() *MockPenaltyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateIfAbsent mocks base method.
+func (m *MockPenaltyRepository) CreateIfAbsent(penalty *entity.Penalty) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIfAbsent", penalty)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIfAbsent indicates an expected call of CreateIfAbsent.
+func (mr *MockPenaltyRepositoryMockRecorder) CreateIfAbsent(penalty any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIfAbsent", reflect.TypeOf((*MockPenaltyRepository)(nil).CreateIfAbsent), penalty)
+}
+
+// FindByTransactionID mocks base method.
+func (m *MockPenaltyRepository) FindByTransactionID(transactionID uint64) ([]entity.Penalty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTransactionID", transactionID)
+	ret0, _ := ret[0].([]entity.Penalty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTransactionID indicates an expected call of FindByTransactionID.
+func (mr *MockPenaltyRepositoryMockRecorder) FindByTransactionID(transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTransactionID", reflect.TypeOf((*MockPenaltyRepository)(nil).FindByTransactionID), transactionID)
+}
+
+// WithTx mocks base method.
+func (m *MockPenaltyRepository) WithTx(tx *gorm.DB) repository.PenaltyRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(repository.PenaltyRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockPenaltyRepositoryMockRecorder) WithTx(tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockPenaltyRepository)(nil).WithTx), tx)
+}
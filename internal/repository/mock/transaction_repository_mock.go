@@ -11,6 +11,7 @@ package mock
 
 import (
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/hadi-projects/xyz-finance-go/internal/entity"
 	repository "github.com/hadi-projects/xyz-finance-go/internal/repository"
@@ -56,6 +57,21 @@ func (mr *MockTransactionRepositoryMockRecorder) Create(transaction any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionRepository)(nil).Create), transaction)
 }
 
+// ExistsByContractNumber mocks base method.
+func (m *MockTransactionRepository) ExistsByContractNumber(contractNumber string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsByContractNumber", contractNumber)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsByContractNumber indicates an expected call of ExistsByContractNumber.
+func (mr *MockTransactionRepositoryMockRecorder) ExistsByContractNumber(contractNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByContractNumber", reflect.TypeOf((*MockTransactionRepository)(nil).ExistsByContractNumber), contractNumber)
+}
+
 // FindAll mocks base method.
 func (m *MockTransactionRepository) FindAll() ([]entity.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +87,37 @@ func (mr *MockTransactionRepositoryMockRecorder) FindAll() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockTransactionRepository)(nil).FindAll))
 }
 
+// FindByCursor mocks base method.
+func (m *MockTransactionRepository) FindByCursor(filter repository.TransactionFilter, cursor *repository.Keyset, limit int) ([]entity.Transaction, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCursor", filter, cursor, limit)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindByCursor indicates an expected call of FindByCursor.
+func (mr *MockTransactionRepositoryMockRecorder) FindByCursor(filter, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCursor", reflect.TypeOf((*MockTransactionRepository)(nil).FindByCursor), filter, cursor, limit)
+}
+
+// FindByID mocks base method.
+func (m *MockTransactionRepository) FindByID(id uint64) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockTransactionRepositoryMockRecorder) FindByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockTransactionRepository)(nil).FindByID), id)
+}
+
 // FindByUserID mocks base method.
 func (m *MockTransactionRepository) FindByUserID(userId uint) ([]entity.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -86,48 +133,91 @@ func (mr *MockTransactionRepositoryMockRecorder) FindByUserID(userId any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockTransactionRepository)(nil).FindByUserID), userId)
 }
 
-// WithTx mocks base method.
-func (m *MockTransactionRepository) WithTx(tx *gorm.DB) repository.TransactionRepository {
+// FindOverdueCandidateIDs mocks base method.
+func (m *MockTransactionRepository) FindOverdueCandidateIDs(asOf time.Time) ([]uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WithTx", tx)
-	ret0, _ := ret[0].(repository.TransactionRepository)
-	return ret0
+	ret := m.ctrl.Call(m, "FindOverdueCandidateIDs", asOf)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// WithTx indicates an expected call of WithTx.
-func (mr *MockTransactionRepositoryMockRecorder) WithTx(tx any) *gomock.Call {
+// FindOverdueCandidateIDs indicates an expected call of FindOverdueCandidateIDs.
+func (mr *MockTransactionRepositoryMockRecorder) FindOverdueCandidateIDs(asOf any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockTransactionRepository)(nil).WithTx), tx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOverdueCandidateIDs", reflect.TypeOf((*MockTransactionRepository)(nil).FindOverdueCandidateIDs), asOf)
 }
 
-// FindByUserIDPaginated mocks base method.
-func (m *MockTransactionRepository) FindByUserIDPaginated(userId uint, offset, limit int) ([]entity.Transaction, int64, error) {
+// FindPaginated mocks base method.
+func (m *MockTransactionRepository) FindPaginated(filter repository.TransactionFilter, offset, limit int) ([]entity.Transaction, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByUserIDPaginated", userId, offset, limit)
+	ret := m.ctrl.Call(m, "FindPaginated", filter, offset, limit)
 	ret0, _ := ret[0].([]entity.Transaction)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// FindByUserIDPaginated indicates an expected call of FindByUserIDPaginated.
-func (mr *MockTransactionRepositoryMockRecorder) FindByUserIDPaginated(userId, offset, limit any) *gomock.Call {
+// FindPaginated indicates an expected call of FindPaginated.
+func (mr *MockTransactionRepositoryMockRecorder) FindPaginated(filter, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserIDPaginated", reflect.TypeOf((*MockTransactionRepository)(nil).FindByUserIDPaginated), userId, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPaginated", reflect.TypeOf((*MockTransactionRepository)(nil).FindPaginated), filter, offset, limit)
 }
 
-// FindAllPaginated mocks base method.
-func (m *MockTransactionRepository) FindAllPaginated(offset, limit int) ([]entity.Transaction, int64, error) {
+// GetLimitUsage mocks base method.
+func (m *MockTransactionRepository) GetLimitUsage(userIDs []uint) ([]repository.LimitUsage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllPaginated", offset, limit)
-	ret0, _ := ret[0].([]entity.Transaction)
-	ret1, _ := ret[1].(int64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "GetLimitUsage", userIDs)
+	ret0, _ := ret[0].([]repository.LimitUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimitUsage indicates an expected call of GetLimitUsage.
+func (mr *MockTransactionRepositoryMockRecorder) GetLimitUsage(userIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitUsage", reflect.TypeOf((*MockTransactionRepository)(nil).GetLimitUsage), userIDs)
+}
+
+// MaxDaysPastDueByUserID mocks base method.
+func (m *MockTransactionRepository) MaxDaysPastDueByUserID(userID uint) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxDaysPastDueByUserID", userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// FindAllPaginated indicates an expected call of FindAllPaginated.
-func (mr *MockTransactionRepositoryMockRecorder) FindAllPaginated(offset, limit any) *gomock.Call {
+// MaxDaysPastDueByUserID indicates an expected call of MaxDaysPastDueByUserID.
+func (mr *MockTransactionRepositoryMockRecorder) MaxDaysPastDueByUserID(userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllPaginated", reflect.TypeOf((*MockTransactionRepository)(nil).FindAllPaginated), offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxDaysPastDueByUserID", reflect.TypeOf((*MockTransactionRepository)(nil).MaxDaysPastDueByUserID), userID)
+}
+
+// Update mocks base method.
+func (m *MockTransactionRepository) Update(transaction *entity.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTransactionRepositoryMockRecorder) Update(transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTransactionRepository)(nil).Update), transaction)
+}
+
+// WithTx mocks base method.
+func (m *MockTransactionRepository) WithTx(tx *gorm.DB) repository.TransactionRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", tx)
+	ret0, _ := ret[0].(repository.TransactionRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockTransactionRepositoryMockRecorder) WithTx(tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockTransactionRepository)(nil).WithTx), tx)
 }
@@ -11,6 +11,7 @@ package mock
 
 import (
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/hadi-projects/xyz-finance-go/internal/entity"
 	gomock "go.uber.org/mock/gomock"
@@ -142,6 +143,20 @@ func (mr *MockUserRepositoryMockRecorder) GetLimitsByUserID(userID any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitsByUserID", reflect.TypeOf((*MockUserRepository)(nil).GetLimitsByUserID), userID)
 }
 
+// SetSuspendedAt mocks base method.
+func (m *MockUserRepository) SetSuspendedAt(userID uint, suspendedAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSuspendedAt", userID, suspendedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSuspendedAt indicates an expected call of SetSuspendedAt.
+func (mr *MockUserRepositoryMockRecorder) SetSuspendedAt(userID, suspendedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSuspendedAt", reflect.TypeOf((*MockUserRepository)(nil).SetSuspendedAt), userID, suspendedAt)
+}
+
 // Update mocks base method.
 func (m *MockUserRepository) Update(user *entity.User) error {
 	m.ctrl.T.Helper()
@@ -155,3 +170,31 @@ func (mr *MockUserRepositoryMockRecorder) Update(user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), user)
 }
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepository) UpdatePassword(userID uint, hashedPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", userID, hashedPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepositoryMockRecorder) UpdatePassword(userID, hashedPassword any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), userID, hashedPassword)
+}
+
+// UpdateRole mocks base method.
+func (m *MockUserRepository) UpdateRole(userID, roleID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", userID, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserRepositoryMockRecorder) UpdateRole(userID, roleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateRole), userID, roleID)
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"gorm.io/gorm"
+)
+
+type PaymentRepository interface {
+	Create(payment *entity.Payment) error
+	FindByTransactionID(transactionID uint64) ([]entity.Payment, error)
+	WithTx(tx *gorm.DB) PaymentRepository
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+// Create inserts the payment together with its installment allocations
+func (r *paymentRepository) Create(payment *entity.Payment) error {
+	return r.db.Create(payment).Error
+}
+
+func (r *paymentRepository) FindByTransactionID(transactionID uint64) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	err := r.db.Preload("Allocations").
+		Where("transaction_id = ?", transactionID).
+		Order("paid_at ASC, id ASC").
+		Find(&payments).Error
+	return payments, err
+}
+
+func (r *paymentRepository) WithTx(tx *gorm.DB) PaymentRepository {
+	return &paymentRepository{db: tx}
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PenaltyRepository interface {
+	CreateIfAbsent(penalty *entity.Penalty) (bool, error)
+	FindByTransactionID(transactionID uint64) ([]entity.Penalty, error)
+	WithTx(tx *gorm.DB) PenaltyRepository
+}
+
+type penaltyRepository struct {
+	db *gorm.DB
+}
+
+func NewPenaltyRepository(db *gorm.DB) PenaltyRepository {
+	return &penaltyRepository{db: db}
+}
+
+// CreateIfAbsent inserts the penalty unless its installment was already charged for that day and
+// reports whether it was inserted
+func (r *penaltyRepository) CreateIfAbsent(penalty *entity.Penalty) (bool, error) {
+	result := r.db.Clauses(clause.Insert{Modifier: "IGNORE"}).Create(penalty)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *penaltyRepository) FindByTransactionID(transactionID uint64) ([]entity.Penalty, error) {
+	var penalties []entity.Penalty
+	err := r.db.Where("transaction_id = ?", transactionID).Order("accrual_date ASC, installment_id ASC").Find(&penalties).Error
+	return penalties, err
+}
+
+func (r *penaltyRepository) WithTx(tx *gorm.DB) PenaltyRepository {
+	return &penaltyRepository{db: tx}
+}
@@ -10,7 +10,9 @@ import (
 type RefreshTokenRepository interface {
 	Create(token *entity.RefreshToken) error
 	FindByToken(token string) (*entity.RefreshToken, error)
+	Consume(token string) (bool, error)
 	RevokeByToken(token string) error
+	RevokeFamily(familyID string) error
 	RevokeAllByUserID(userID uint) error
 	DeleteExpired() error
 }
@@ -28,22 +30,38 @@ func (r *refreshTokenRepository) Create(token *entity.RefreshToken) error {
 	return r.db.Create(token).Error
 }
 
+// FindByToken returns the token with its user whether or not it is revoked or expired; callers
+// check its state, so that a revoked token being presented again can be detected
 func (r *refreshTokenRepository) FindByToken(token string) (*entity.RefreshToken, error) {
 	var refreshToken entity.RefreshToken
-	err := r.db.Where("token = ? AND revoked = ? AND expires_at > ?",
-		token, false, time.Now()).First(&refreshToken).Error
+	err := r.db.Preload("User").Where("token = ?", token).First(&refreshToken).Error
 	if err != nil {
 		return nil, err
 	}
 	return &refreshToken, nil
 }
 
+// Consume revokes an active token and reports whether this call did so. Of two concurrent
+// refreshes with the same token only one consumes it.
+func (r *refreshTokenRepository) Consume(token string) (bool, error) {
+	result := r.db.Model(&entity.RefreshToken{}).
+		Where("token = ? AND revoked = ? AND expires_at > ?", token, false, time.Now()).
+		Update("revoked", true)
+	return result.RowsAffected == 1, result.Error
+}
+
 func (r *refreshTokenRepository) RevokeByToken(token string) error {
 	return r.db.Model(&entity.RefreshToken{}).
 		Where("token = ?", token).
 		Update("revoked", true).Error
 }
 
+func (r *refreshTokenRepository) RevokeFamily(familyID string) error {
+	return r.db.Model(&entity.RefreshToken{}).
+		Where("family_id = ?", familyID).
+		Update("revoked", true).Error
+}
+
 func (r *refreshTokenRepository) RevokeAllByUserID(userID uint) error {
 	return r.db.Model(&entity.RefreshToken{}).
 		Where("user_id = ?", userID).
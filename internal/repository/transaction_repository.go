@@ -1,19 +1,81 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
 	"gorm.io/gorm"
 )
 
+// LimitUsage is the outstanding OTR (OTR minus repaid principal) of limit-consuming
+// transactions, grouped per user, tenor and status
+type LimitUsage struct {
+	UserID uint
+	Tenor  int
+	Status entity.TransactionStatus
+	Amount money.Money
+}
+
+// TransactionSort is a whitelisted ordering for transaction listings
+type TransactionSort string
+
+const (
+	SortCreatedAtDesc TransactionSort = "created_at_desc"
+	SortCreatedAtAsc  TransactionSort = "created_at_asc"
+	SortOTRDesc       TransactionSort = "otr_desc"
+	SortOTRAsc        TransactionSort = "otr_asc"
+	SortTenorDesc     TransactionSort = "tenor_desc"
+	SortTenorAsc      TransactionSort = "tenor_asc"
+)
+
+// transactionSortOrders maps each sort to its ordering; id breaks ties so pages are stable
+var transactionSortOrders = map[TransactionSort]keysetOrder{
+	SortCreatedAtDesc: {column: "created_at", desc: true},
+	SortCreatedAtAsc:  {column: "created_at"},
+	SortOTRDesc:       {column: "otr", desc: true},
+	SortOTRAsc:        {column: "otr"},
+	SortTenorDesc:     {column: "tenor", desc: true},
+	SortTenorAsc:      {column: "tenor"},
+}
+
+// TransactionFilter narrows a transaction listing; zero values mean "no filter"
+type TransactionFilter struct {
+	UserID         uint
+	Status         entity.TransactionStatus
+	Tenor          int
+	AssetName      string // case-insensitive substring match
+	ContractNumber string // exact match
+	MinOTR         money.Money
+	MaxOTR         money.Money
+	From           time.Time // inclusive
+	To             time.Time // exclusive
+	Sort           TransactionSort
+}
+
 type TransactionRepository interface {
 	Create(transaction *entity.Transaction) error
+	FindByID(id uint64) (*entity.Transaction, error)
+	ExistsByContractNumber(contractNumber string) (bool, error)
+	Update(transaction *entity.Transaction) error
 	FindByUserID(userId uint) ([]entity.Transaction, error)
-	FindByUserIDPaginated(userId uint, offset, limit int) ([]entity.Transaction, int64, error)
 	FindAll() ([]entity.Transaction, error)
-	FindAllPaginated(offset, limit int) ([]entity.Transaction, int64, error)
+	FindPaginated(filter TransactionFilter, offset, limit int) ([]entity.Transaction, int64, error)
+	FindByCursor(filter TransactionFilter, cursor *Keyset, limit int) ([]entity.Transaction, bool, error)
+	GetLimitUsage(userIDs []uint) ([]LimitUsage, error)
+	FindOverdueCandidateIDs(asOf time.Time) ([]uint64, error)
+	MaxDaysPastDueByUserID(userID uint) (int, error)
 	WithTx(tx *gorm.DB) TransactionRepository
 }
 
+// transactionListColumns are the columns selected by the paginated listings
+var transactionListColumns = []string{
+	"id", "user_id", "contract_number", "otr", "admin_fee", "installment_amount", "interest_amount",
+	"asset_name", "status", "tenor", "outstanding_amount", "principal_paid", "reviewed_by", "reviewed_at", "rejection_reason", "cancellation_reason", "days_past_due", "dpd_bucket", "penalty_amount",
+	"created_at", "updated_at",
+}
+
 type transactionRepository struct {
 	db *gorm.DB
 }
@@ -26,23 +88,50 @@ func (r *transactionRepository) Create(transaction *entity.Transaction) error {
 	return r.db.Create(transaction).Error
 }
 
+func (r *transactionRepository) FindByID(id uint64) (*entity.Transaction, error) {
+	var transaction entity.Transaction
+	err := r.db.First(&transaction, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+func (r *transactionRepository) ExistsByContractNumber(contractNumber string) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Transaction{}).Where("contract_number = ?", contractNumber).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *transactionRepository) Update(transaction *entity.Transaction) error {
+	return r.db.Save(transaction).Error
+}
+
 func (r *transactionRepository) FindByUserID(userId uint) ([]entity.Transaction, error) {
 	var transactions []entity.Transaction
 	err := r.db.Where("user_id = ?", userId).Order("created_at DESC").Find(&transactions).Error
 	return transactions, err
 }
 
-func (r *transactionRepository) FindByUserIDPaginated(userId uint, offset, limit int) ([]entity.Transaction, int64, error) {
+func (r *transactionRepository) FindAll() ([]entity.Transaction, error) {
+	var transactions []entity.Transaction
+	err := r.db.Order("created_at DESC").Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *transactionRepository) FindPaginated(filter TransactionFilter, offset, limit int) ([]entity.Transaction, int64, error) {
 	var transactions []entity.Transaction
 	var total int64
 
-	if err := r.db.Model(&entity.Transaction{}).Where("user_id = ?", userId).Count(&total).Error; err != nil {
+	query := filter.apply(r.db.Model(&entity.Transaction{}))
+
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := r.db.Select("id", "user_id", "contract_number", "otr", "admin_fee", "installment_amount", "interest_amount", "asset_name", "status", "tenor", "created_at", "updated_at").
-		Where("user_id = ?", userId).
-		Order("created_at DESC").
+	// Get paginated data with Select for specific columns
+	err := query.Select(transactionListColumns).
+		Order(filter.order().clause(false)).
 		Offset(offset).
 		Limit(limit).
 		Find(&transactions).Error
@@ -50,31 +139,129 @@ func (r *transactionRepository) FindByUserIDPaginated(userId uint, offset, limit
 	return transactions, total, err
 }
 
-func (r *transactionRepository) FindAll() ([]entity.Transaction, error) {
-	var transactions []entity.Transaction
-	err := r.db.Order("created_at DESC").Find(&transactions).Error
-	return transactions, err
-}
-
-func (r *transactionRepository) FindAllPaginated(offset, limit int) ([]entity.Transaction, int64, error) {
+// FindByCursor returns up to limit transactions after cursor (before it when cursor.Backward), or
+// the first page when cursor is nil, without counting the whole listing. The bool reports whether
+// more rows exist beyond the page in the direction read.
+func (r *transactionRepository) FindByCursor(filter TransactionFilter, cursor *Keyset, limit int) ([]entity.Transaction, bool, error) {
 	var transactions []entity.Transaction
-	var total int64
 
-	// Count total
-	if err := r.db.Model(&entity.Transaction{}).Count(&total).Error; err != nil {
-		return nil, 0, err
+	order := filter.order()
+	query := filter.apply(r.db.Model(&entity.Transaction{}))
+	backward := cursor != nil && cursor.Backward
+	if cursor != nil {
+		query = order.seek(query, *cursor)
 	}
 
-	// Get paginated data with Select for specific columns
-	err := r.db.Select("id", "user_id", "contract_number", "otr", "admin_fee", "installment_amount", "interest_amount", "asset_name", "status", "tenor", "created_at", "updated_at").
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(limit).
+	err := query.Select(transactionListColumns).
+		Order(order.clause(backward)).
+		Limit(limit + 1).
 		Find(&transactions).Error
+	if err != nil {
+		return nil, false, err
+	}
 
-	return transactions, total, err
+	hasMore := len(transactions) > limit
+	if hasMore {
+		transactions = transactions[:limit]
+	}
+	if backward {
+		reverse(transactions)
+	}
+	return transactions, hasMore, nil
+}
+
+func (r *transactionRepository) GetLimitUsage(userIDs []uint) ([]LimitUsage, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var usages []LimitUsage
+	err := r.db.Model(&entity.Transaction{}).
+		Select("user_id, tenor, status, SUM(otr - principal_paid) AS amount").
+		Where("user_id IN ? AND status IN ?", userIDs, entity.LimitConsumingStatuses).
+		Group("user_id, tenor, status").
+		Scan(&usages).Error
+	return usages, err
+}
+
+// FindOverdueCandidateIDs returns the active transactions the overdue job has to look at: those
+// with an open installment due before asOf, and those still flagged past due (which may have caught up)
+func (r *transactionRepository) FindOverdueCandidateIDs(asOf time.Time) ([]uint64, error) {
+	var ids []uint64
+	err := r.db.Model(&entity.Transaction{}).
+		Where("status = ?", entity.TransactionActive).
+		Where(r.db.Where("days_past_due > 0").
+			Or("EXISTS (SELECT 1 FROM installments WHERE installments.transaction_id = transactions.id AND installments.status IN ? AND installments.due_date < ?)",
+				[]entity.InstallmentStatus{entity.InstallmentUnpaid, entity.InstallmentOverdue}, asOf)).
+		Order("id ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// MaxDaysPastDueByUserID returns the highest days past due among the user's active contracts,
+// or 0 when none is past due
+func (r *transactionRepository) MaxDaysPastDueByUserID(userID uint) (int, error) {
+	var days int
+	err := r.db.Model(&entity.Transaction{}).
+		Where("user_id = ? AND status = ?", userID, entity.TransactionActive).
+		Select("COALESCE(MAX(days_past_due), 0)").
+		Scan(&days).Error
+	return days, err
 }
 
 func (r *transactionRepository) WithTx(tx *gorm.DB) TransactionRepository {
 	return &transactionRepository{db: tx}
 }
+
+func (f TransactionFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.UserID != 0 {
+		db = db.Where("user_id = ?", f.UserID)
+	}
+	if f.Status != "" {
+		db = db.Where("status = ?", f.Status)
+	}
+	if f.Tenor != 0 {
+		db = db.Where("tenor = ?", f.Tenor)
+	}
+	if f.AssetName != "" {
+		db = db.Where("LOWER(asset_name) LIKE ?", "%"+escapeLike(strings.ToLower(f.AssetName))+"%")
+	}
+	if f.ContractNumber != "" {
+		db = db.Where("contract_number = ?", f.ContractNumber)
+	}
+	if f.MinOTR != 0 {
+		db = db.Where("otr >= ?", f.MinOTR)
+	}
+	if f.MaxOTR != 0 {
+		db = db.Where("otr <= ?", f.MaxOTR)
+	}
+	if !f.From.IsZero() {
+		db = db.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		db = db.Where("created_at < ?", f.To)
+	}
+	return db
+}
+
+// SortOrDefault returns the filter's sort, created_at_desc when unset or unknown
+func (f TransactionFilter) SortOrDefault() TransactionSort {
+	if _, ok := transactionSortOrders[f.Sort]; ok {
+		return f.Sort
+	}
+	return SortCreatedAtDesc
+}
+
+func (f TransactionFilter) order() keysetOrder {
+	return transactionSortOrders[f.SortOrDefault()]
+}
+
+// TransactionKeyset returns the cursor position of t in a listing sorted by sort
+func TransactionKeyset(t entity.Transaction, sort TransactionSort) Keyset {
+	return transactionSortOrders[sort].keyset(string(sort), t.ID, t.CreatedAt, t.OTR, t.Tenor)
+}
+
+// escapeLike escapes the LIKE wildcards in user input so they match literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
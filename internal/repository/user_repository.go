@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/hadi-projects/xyz-finance-go/internal/entity"
 	"gorm.io/gorm"
 )
@@ -10,6 +12,9 @@ type UserRepository interface {
 	FindByID(id uint) (*entity.User, error)
 	FindByEmail(email string) (*entity.User, error)
 	Update(user *entity.User) error
+	UpdatePassword(userID uint, hashedPassword string) error
+	UpdateRole(userID, roleID uint) error
+	SetSuspendedAt(userID uint, suspendedAt *time.Time) error
 	Delete(id uint) error
 	CreateUserHasTenorLimit(userId uint, limitID uint) error
 	GetLimitsByUserID(userID uint) ([]entity.TenorLimit, error)
@@ -53,6 +58,21 @@ func (r *userRepository) Update(user *entity.User) error {
 	return r.db.Save(user).Error
 }
 
+// UpdatePassword, UpdateRole and SetSuspendedAt write a single column, leaving the preloaded
+// associations of a user found by FindByID alone
+
+func (r *userRepository) UpdatePassword(userID uint, hashedPassword string) error {
+	return r.db.Model(&entity.User{ID: userID}).Update("password", hashedPassword).Error
+}
+
+func (r *userRepository) UpdateRole(userID, roleID uint) error {
+	return r.db.Model(&entity.User{ID: userID}).Update("role_id", roleID).Error
+}
+
+func (r *userRepository) SetSuspendedAt(userID uint, suspendedAt *time.Time) error {
+	return r.db.Model(&entity.User{ID: userID}).Update("suspended_at", suspendedAt).Error
+}
+
 func (r *userRepository) Delete(id uint) error {
 	return r.db.Delete(&entity.User{}, id).Error
 }
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/hadi-projects/xyz-finance-go/internal/middleware"
 )
@@ -9,16 +11,42 @@ func (r *Router) setupPrivateRoutes(api *gin.Engine) {
 
 	protected := api.Group("/api")
 	protected.Use(middleware.APIKeyMiddleware(r.Config.Security.APIKey))
-	protected.Use(middleware.JWTAuth(r.Config.JWT.Secret))
+	protected.Use(middleware.JWTAuth(r.JWTKeys, r.TokenRevocations))
+
+	idempotencyTTL := time.Duration(r.Config.Security.IdempotencyTTLHours) * time.Hour
+	// Requests are cancelled after RequestTimeout; the margin lets a cancelled handler unwind
+	idempotencyPendingTTL := 2 * time.Duration(r.Config.Security.RequestTimeout) * time.Second
 	{
+		auth := protected.Group("/auth")
+		{
+			auth.POST("/logout", r.AuthHandler.Logout)
+			auth.POST("/logout-all", r.AuthHandler.LogoutAll)
+			auth.PUT("/password", r.AuthHandler.ChangePassword)
+		}
+
 		user := protected.Group("/user")
 		{
 			user.GET("/profile", r.UserHandler.GetProfile)
+			user.PUT("/:id/role", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "manage-users"), r.UserHandler.ChangeRole)
+			user.POST("/:id/suspend", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "manage-users"), r.UserHandler.SuspendUser)
+			user.POST("/:id/unsuspend", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "manage-users"), r.UserHandler.UnsuspendUser)
+			user.POST("/:id/unlock", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "manage-users"), r.UserHandler.UnlockLogin)
 		}
 
+		consumer := protected.Group("/consumer")
+		{
+			consumer.POST("/", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "submit-kyc"), r.ConsumerHandler.SubmitConsumer)
+			consumer.GET("/:id/documents", r.ConsumerHandler.GetDocuments)
+			consumer.PUT("/:id/kyc", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "verify-kyc"), r.ConsumerHandler.UpdateKYCStatus)
+		}
+
+		// KYC images: the signed link must have been issued to the authenticated user
+		protected.GET("/documents/:kind/:name", r.ConsumerHandler.GetDocument)
+
 		limit := protected.Group("/limit")
 		{
 			limit.GET("/", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "get-limit"), r.LimitHandler.GetLimits)
+			limit.GET("/mutations", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "get-limit-mutations"), r.LimitHandler.GetMutations)
 			limit.POST("/", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "create-limit"), r.LimitHandler.CreateLimit)
 			limit.PUT("/:id", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "edit-limit"), r.LimitHandler.UpdateLimit)
 			limit.DELETE("/:id", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "delete-limit"), r.LimitHandler.DeleteLimit)
@@ -26,8 +54,19 @@ func (r *Router) setupPrivateRoutes(api *gin.Engine) {
 
 		transaction := protected.Group("/transaction")
 		{
-			transaction.POST("/", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "create-transaction"), r.TransactionHandler.CreateTransaction)
+			transaction.POST("/", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "create-transaction"), middleware.Idempotency(r.IdempotencyStore, idempotencyPendingTTL, idempotencyTTL), r.TransactionHandler.CreateTransaction)
+			transaction.POST("/simulate", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "create-transaction"), r.TransactionHandler.SimulateTransaction)
 			transaction.GET("/", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "get-transactions"), r.TransactionHandler.GetTransactions)
+			transaction.GET("/:id", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "get-transactions"), r.TransactionHandler.GetTransaction)
+			transaction.GET("/:id/installments", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "get-transactions"), r.TransactionHandler.GetInstallments)
+			transaction.GET("/:id/payments", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "get-transactions"), r.PaymentHandler.GetPayments)
+			transaction.POST("/:id/payments", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "create-payment"), r.PaymentHandler.CreatePayment)
+			transaction.GET("/:id/payoff", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "get-transactions"), r.PaymentHandler.GetPayoffQuote)
+			transaction.POST("/:id/settle", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "settle-transaction"), r.PaymentHandler.SettleTransaction)
+			transaction.POST("/:id/approve", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "approve-transaction"), r.TransactionHandler.ApproveTransaction)
+			transaction.POST("/:id/reject", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "reject-transaction"), r.TransactionHandler.RejectTransaction)
+			transaction.POST("/:id/cancel", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "cancel-transaction"), r.TransactionHandler.CancelTransaction)
+			transaction.POST("/:id/disburse", middleware.PermissionMiddleware(r.UserRepo, r.PermCache, "disburse-transaction"), r.TransactionHandler.DisburseTransaction)
 		}
 
 		logs := protected.Group("/logs")
@@ -8,9 +8,6 @@ import (
 
 func (r *Router) setupPublicRoutes(router *gin.Engine) {
 
-	// Serve static files from storage/uploads
-	router.Static("/uploads", "./storage/uploads")
-
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "UP",
@@ -19,12 +16,16 @@ func (r *Router) setupPublicRoutes(router *gin.Engine) {
 		})
 	})
 
+	// Public keys for verifying access tokens, e.g. by the partner gateway
+	router.GET("/.well-known/jwks.json", r.JWKSHandler.GetJWKS)
+
 	api := router.Group("/api")
 	{
 		auth := api.Group("/auth")
 		{
 			auth.POST("/register", r.AuthHandler.Register)
 			auth.POST("/login", r.AuthHandler.Login)
+			auth.POST("/refresh", r.AuthHandler.Refresh)
 		}
 	}
 
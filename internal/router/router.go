@@ -9,6 +9,7 @@ import (
 	"github.com/hadi-projects/xyz-finance-go/internal/middleware"
 	"github.com/hadi-projects/xyz-finance-go/internal/repository"
 	"github.com/hadi-projects/xyz-finance-go/pkg/cache"
+	"github.com/hadi-projects/xyz-finance-go/pkg/jwtkeys"
 )
 
 type Router struct {
@@ -17,9 +18,15 @@ type Router struct {
 	LimitHandler       *handler.LimitHandler
 	UserHandler        *handler.UserHandler
 	TransactionHandler *handler.TransactionHandler
+	PaymentHandler     *handler.PaymentHandler
+	ConsumerHandler    *handler.ConsumerHandler
 	LogHandler         *handler.LogHandler
+	JWKSHandler        *handler.JWKSHandler
+	JWTKeys            *jwtkeys.KeySet
 	UserRepo           repository.UserRepository
 	PermCache          *cache.PermissionCache
+	IdempotencyStore   middleware.IdempotencyStore
+	TokenRevocations   middleware.TokenRevocationStore
 }
 
 func NewRouter(
@@ -28,9 +35,15 @@ func NewRouter(
 	limitHandler *handler.LimitHandler,
 	userHandler *handler.UserHandler,
 	transactionHandler *handler.TransactionHandler,
+	paymentHandler *handler.PaymentHandler,
+	consumerHandler *handler.ConsumerHandler,
 	logHandler *handler.LogHandler,
+	jwksHandler *handler.JWKSHandler,
+	jwtKeys *jwtkeys.KeySet,
 	userRepo repository.UserRepository,
 	permCache *cache.PermissionCache,
+	idempotencyStore middleware.IdempotencyStore,
+	tokenRevocations middleware.TokenRevocationStore,
 ) *Router {
 	return &Router{
 		Config:             cfg,
@@ -38,9 +51,15 @@ func NewRouter(
 		LimitHandler:       limitHandler,
 		UserHandler:        userHandler,
 		TransactionHandler: transactionHandler,
+		PaymentHandler:     paymentHandler,
+		ConsumerHandler:    consumerHandler,
 		LogHandler:         logHandler,
+		JWKSHandler:        jwksHandler,
+		JWTKeys:            jwtKeys,
 		UserRepo:           userRepo,
 		PermCache:          permCache,
+		IdempotencyStore:   idempotencyStore,
+		TokenRevocations:   tokenRevocations,
 	}
 }
 
@@ -11,9 +11,17 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrInvalidCredentials     = errors.New("invalid email or password")
+	ErrUserSuspended          = errors.New("account is suspended")
+	ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+	ErrPasswordUnchanged      = errors.New("new password must differ from the current password")
+)
+
 type AuthService interface {
 	Register(email, password string) (*entity.User, error)
 	Login(email, password string) (*entity.User, error)
+	ChangePassword(userID uint, currentPassword, newPassword string) error
 }
 
 type authService struct {
@@ -61,15 +69,46 @@ func (s *authService) Login(email, password string) (*entity.User, error) {
 	user, err := s.userRepo.FindByEmail(email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("invalid email or password")
+			return nil, ErrInvalidCredentials
 		}
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, fmt.Errorf("invalid email or password")
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.SuspendedAt != nil {
+		return nil, ErrUserSuspended
 	}
 
 	return user, nil
 }
+
+// ChangePassword replaces the user's password after checking the current one. The caller is
+// responsible for revoking the user's sessions afterwards.
+func (s *authService) ChangePassword(userID uint, currentPassword, newPassword string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return ErrInvalidCurrentPassword
+	}
+	if currentPassword == newPassword {
+		return ErrPasswordUnchanged
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), s.bcryptCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(user.ID, string(hashedPassword)); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return nil
+}
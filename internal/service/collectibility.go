@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+)
+
+var ErrCollectibilityNotEligible = errors.New("collectibility grade does not allow new financing")
+
+// CollectibilityClassifier grades contracts and consumers by the OJK collectibility
+// (kolektibilitas) rules, which are based on days past due
+type CollectibilityClassifier struct {
+	cfg config.CollectibilityConfig
+}
+
+func NewCollectibilityClassifier(cfg config.CollectibilityConfig) *CollectibilityClassifier {
+	return &CollectibilityClassifier{cfg: cfg}
+}
+
+// Classify returns the grade of a single contract
+func (c *CollectibilityClassifier) Classify(transaction *entity.Transaction) entity.Collectibility {
+	return entity.CollectibilityFor(transaction.DaysPastDue)
+}
+
+// ClassifyAll fills in the grade of every transaction
+func (c *CollectibilityClassifier) ClassifyAll(transactions []entity.Transaction) {
+	for i := range transactions {
+		transactions[i].Collectibility = c.Classify(&transactions[i])
+	}
+}
+
+// ConsumerGrade returns the worst grade among the user's active contracts. A consumer without
+// active contracts is current.
+func (c *CollectibilityClassifier) ConsumerGrade(transactionRepo repository.TransactionRepository, userID uint) (entity.Collectibility, error) {
+	days, err := transactionRepo.MaxDaysPastDueByUserID(userID)
+	if err != nil {
+		return 0, err
+	}
+	return entity.CollectibilityFor(days), nil
+}
+
+// CheckEligible returns ErrCollectibilityNotEligible when the user's grade is above the
+// configured maximum for new financing
+func (c *CollectibilityClassifier) CheckEligible(transactionRepo repository.TransactionRepository, userID uint) error {
+	grade, err := c.ConsumerGrade(transactionRepo, userID)
+	if err != nil {
+		return err
+	}
+	if int(grade) > c.cfg.MaxGradeForNewTransaction {
+		return ErrCollectibilityNotEligible
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+	"github.com/hadi-projects/xyz-finance-go/pkg/storage"
+	"github.com/hadi-projects/xyz-finance-go/pkg/validator"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrConsumerNotFound     = errors.New("consumer not found")
+	ErrNIKAlreadyRegistered = errors.New("NIK is already registered to another user")
+	ErrKYCAlreadyVerified   = errors.New("verified consumer data can no longer be changed")
+	ErrConcurrentSubmission = errors.New("consumer data is already being submitted, retry the request")
+	ErrKYCImagesRequired    = errors.New("ktp_image and selfie_image are required")
+	ErrInvalidImage         = errors.New("invalid image")
+	ErrInvalidDateOfBirth   = errors.New("date_of_birth must be in the past")
+)
+
+// kycImageTypes are the accepted image types, detected from the file content, with the
+// extension the stored file gets
+var kycImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+const (
+	kycImageKTP    = "ktp"
+	kycImageSelfie = "selfie"
+)
+
+type ConsumerService interface {
+	SubmitConsumer(userID uint, req dto.SubmitConsumerRequest) (*entity.Consumer, error)
+	UpdateKYCStatus(adminID uint, userID uint, req dto.UpdateKYCStatusRequest) (*entity.Consumer, error)
+}
+
+type consumerService struct {
+	consumerRepo repository.ConsumerRepository
+	store        storage.Storage
+	cfg          config.KYCConfig
+}
+
+func NewConsumerService(consumerRepo repository.ConsumerRepository, store storage.Storage, cfg config.KYCConfig) ConsumerService {
+	return &consumerService{
+		consumerRepo: consumerRepo,
+		store:        store,
+		cfg:          cfg,
+	}
+}
+
+// SubmitConsumer creates the user's consumer profile or replaces a profile that has not been
+// verified yet. Every submission goes (back) to KYC review.
+func (s *consumerService) SubmitConsumer(userID uint, req dto.SubmitConsumerRequest) (*entity.Consumer, error) {
+	dateOfBirth, err := time.Parse(time.DateOnly, req.DateOfBirth)
+	if err != nil || !dateOfBirth.Before(time.Now()) {
+		return nil, ErrInvalidDateOfBirth
+	}
+	nik, err := validator.ParseNIK(req.NIK)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer, err := s.consumerRepo.FindByUserID(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	isNew := consumer == nil
+	if isNew {
+		if req.KTPImage == nil || req.SelfieImage == nil {
+			return nil, ErrKYCImagesRequired
+		}
+		consumer = &entity.Consumer{UserID: userID}
+	} else if consumer.KYCStatus == entity.KYCVerified {
+		return nil, ErrKYCAlreadyVerified
+	}
+
+	owner, err := s.consumerRepo.FindByNIK(req.NIK)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if owner != nil && owner.UserID != userID {
+		return nil, ErrNIKAlreadyRegistered
+	}
+
+	// Store the new images first; they are removed again if the profile cannot be saved
+	var stored, replaced []string
+	saveImage := func(file *multipart.FileHeader, kind string, current *string) error {
+		if file == nil {
+			return nil
+		}
+		name, err := s.saveImage(file, kind)
+		if err != nil {
+			return err
+		}
+		stored = append(stored, documentKey(kind, name))
+		if *current != "" {
+			replaced = append(replaced, documentKey(kind, *current))
+		}
+		*current = name
+		return nil
+	}
+	err = saveImage(req.KTPImage, kycImageKTP, &consumer.KTPImage)
+	if err == nil {
+		err = saveImage(req.SelfieImage, kycImageSelfie, &consumer.SelfieImage)
+	}
+	if err != nil {
+		s.removeImages(stored)
+		return nil, err
+	}
+
+	consumer.NIK = req.NIK
+	consumer.FullName = req.FullName
+	consumer.LegalName = req.LegalName
+	consumer.PlaceOfBirth = req.PlaceOfBirth
+	consumer.DateOfBirth = req.DateOfBirth
+	consumer.Salary = req.Salary
+	consumer.KYCStatus = entity.KYCSubmitted
+	consumer.KYCReviewedBy = nil
+	consumer.KYCReviewedAt = nil
+	consumer.KYCRejectionReason = ""
+	consumer.NIKMismatch = ""
+	// A birth date that differs from the NIK is left to the reviewer rather than rejected
+	if !nik.MatchesBirthDate(dateOfBirth) {
+		consumer.NIKMismatch = fmt.Sprintf("NIK encodes date of birth %s (DDMMYY), submitted %s",
+			nik.BirthDate.Format("020106"), req.DateOfBirth)
+	}
+
+	if isNew {
+		err = s.consumerRepo.Create(consumer)
+	} else {
+		err = s.consumerRepo.Update(consumer)
+	}
+	if err != nil {
+		s.removeImages(stored)
+		// The checks above are only a fast path; concurrent submissions are caught by the unique
+		// indexes on user_id (the same user, e.g. a double submit) and nik_hash (another user)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, s.duplicateConsumerError(userID, isNew)
+		}
+		return nil, err
+	}
+
+	s.removeImages(replaced)
+	return consumer, nil
+}
+
+// duplicateConsumerError tells which unique index a failed save hit: a profile created for the
+// user in the meantime, or the NIK registered by someone else
+func (s *consumerService) duplicateConsumerError(userID uint, isNew bool) error {
+	if isNew {
+		if _, err := s.consumerRepo.FindByUserID(userID); err == nil {
+			return ErrConcurrentSubmission
+		}
+	}
+	return ErrNIKAlreadyRegistered
+}
+
+// UpdateKYCStatus records an admin's KYC decision on a user's consumer profile
+func (s *consumerService) UpdateKYCStatus(adminID uint, userID uint, req dto.UpdateKYCStatusRequest) (*entity.Consumer, error) {
+	consumer, err := s.consumerRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrConsumerNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	consumer.KYCStatus = entity.KYCStatus(req.Status)
+	consumer.KYCReviewedBy = &adminID
+	consumer.KYCReviewedAt = &now
+	consumer.KYCRejectionReason = ""
+	if consumer.KYCStatus == entity.KYCRejected {
+		consumer.KYCRejectionReason = req.Reason
+	}
+
+	if err := s.consumerRepo.Update(consumer); err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
+// saveImage checks the upload's size and content type and stores it under a generated name
+// with the kind's prefix. It returns the file name.
+func (s *consumerService) saveImage(file *multipart.FileHeader, kind string) (string, error) {
+	maxSize := int64(s.cfg.MaxImageSizeMB) << 20
+	if file.Size > maxSize {
+		return "", fmt.Errorf("%w: %s exceeds %d MB", ErrInvalidImage, kind, s.cfg.MaxImageSizeMB)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	// The client's Content-Type and file name are not trusted; sniff the content instead
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", fmt.Errorf("%w: %s is empty", ErrInvalidImage, kind)
+	}
+	contentType := http.DetectContentType(head[:n])
+	ext, ok := kycImageTypes[contentType]
+	if !ok {
+		return "", fmt.Errorf("%w: %s must be a JPEG, PNG or WebP image", ErrInvalidImage, kind)
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	name := uuid.NewString() + ext
+	if err := s.store.Put(context.Background(), documentKey(kind, name), src, file.Size, contentType); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// removeImages deletes stored images by key; failures are only logged
+func (s *consumerService) removeImages(keys []string) {
+	for _, key := range keys {
+		if err := s.store.Delete(context.Background(), key); err != nil {
+			logger.SystemLogger.Warn().Err(err).Str("key", key).Msg("Failed to remove KYC image")
+		}
+	}
+}
+
+// documentKey is the storage key of a KYC image
+func documentKey(kind, name string) string {
+	return kind + "/" + name
+}
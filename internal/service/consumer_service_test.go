@@ -0,0 +1,292 @@
+package services_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository/mock"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+	"github.com/hadi-projects/xyz-finance-go/pkg/storage"
+	"github.com/hadi-projects/xyz-finance-go/pkg/validator"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+var pngImage = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+
+// uploadedFile builds the file header of a multipart upload with the given content
+func uploadedFile(t *testing.T, name string, content []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("failed to read form: %v", err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["file"][0]
+}
+
+func storedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", dir, err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestConsumerService_SubmitConsumer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConsumerRepo := mock.NewMockConsumerRepository(ctrl)
+	userID := uint(5)
+
+	newRequest := func(t *testing.T) dto.SubmitConsumerRequest {
+		return dto.SubmitConsumerRequest{
+			NIK:          "3171010101900001",
+			FullName:     "Siti Rahma",
+			LegalName:    "Siti Rahma",
+			PlaceOfBirth: "Jakarta",
+			DateOfBirth:  "1990-01-01",
+			Salary:       money.FromRupiah(8000000),
+			KTPImage:     uploadedFile(t, "ktp.png", pngImage),
+			SelfieImage:  uploadedFile(t, "selfie.png", pngImage),
+		}
+	}
+
+	t.Run("NewConsumer", func(t *testing.T) {
+		uploadDir := t.TempDir()
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(uploadDir), config.KYCConfig{MaxImageSizeMB: 1})
+
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(nil, gorm.ErrRecordNotFound)
+		mockConsumerRepo.EXPECT().FindByNIK("3171010101900001").Return(nil, gorm.ErrRecordNotFound)
+		mockConsumerRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+		consumer, err := service.SubmitConsumer(userID, newRequest(t))
+		assert.NoError(t, err)
+		assert.Equal(t, userID, consumer.UserID)
+		assert.Equal(t, entity.KYCSubmitted, consumer.KYCStatus)
+		assert.Equal(t, ".png", filepath.Ext(consumer.KTPImage))
+		assert.NotEqual(t, "ktp.png", consumer.KTPImage, "client file names are not used")
+		assert.Equal(t, []string{consumer.KTPImage}, storedFiles(t, filepath.Join(uploadDir, "ktp")))
+		assert.Equal(t, []string{consumer.SelfieImage}, storedFiles(t, filepath.Join(uploadDir, "selfie")))
+		assert.Empty(t, consumer.NIKMismatch)
+	})
+
+	t.Run("InvalidNIK", func(t *testing.T) {
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(t.TempDir()), config.KYCConfig{MaxImageSizeMB: 1})
+
+		req := newRequest(t)
+		req.NIK = "3171013201900001" // day 32
+
+		_, err := service.SubmitConsumer(userID, req)
+		assert.ErrorIs(t, err, validator.ErrInvalidNIK)
+	})
+
+	t.Run("BirthDateMismatchFlaggedForReview", func(t *testing.T) {
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(t.TempDir()), config.KYCConfig{MaxImageSizeMB: 1})
+
+		req := newRequest(t)
+		req.DateOfBirth = "1990-01-02"
+
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(nil, gorm.ErrRecordNotFound)
+		mockConsumerRepo.EXPECT().FindByNIK(req.NIK).Return(nil, gorm.ErrRecordNotFound)
+		mockConsumerRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+		consumer, err := service.SubmitConsumer(userID, req)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.KYCSubmitted, consumer.KYCStatus)
+		assert.Equal(t, "NIK encodes date of birth 010190 (DDMMYY), submitted 1990-01-02", consumer.NIKMismatch)
+	})
+
+	t.Run("RejectsNonImage", func(t *testing.T) {
+		uploadDir := t.TempDir()
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(uploadDir), config.KYCConfig{MaxImageSizeMB: 1})
+
+		req := newRequest(t)
+		req.SelfieImage = uploadedFile(t, "selfie.png", []byte("#!/bin/sh\necho not an image\n"))
+
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(nil, gorm.ErrRecordNotFound)
+		mockConsumerRepo.EXPECT().FindByNIK(req.NIK).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.SubmitConsumer(userID, req)
+		assert.ErrorIs(t, err, services.ErrInvalidImage)
+		assert.Empty(t, storedFiles(t, filepath.Join(uploadDir, "ktp")), "the KTP image stored before is removed")
+	})
+
+	t.Run("RejectsOversizedImage", func(t *testing.T) {
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(t.TempDir()), config.KYCConfig{MaxImageSizeMB: 1})
+
+		req := newRequest(t)
+		req.KTPImage = uploadedFile(t, "ktp.png", append(pngImage, make([]byte, 1<<20)...))
+
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(nil, gorm.ErrRecordNotFound)
+		mockConsumerRepo.EXPECT().FindByNIK(req.NIK).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.SubmitConsumer(userID, req)
+		assert.ErrorIs(t, err, services.ErrInvalidImage)
+	})
+
+	t.Run("NewConsumerRequiresImages", func(t *testing.T) {
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(t.TempDir()), config.KYCConfig{MaxImageSizeMB: 1})
+
+		req := newRequest(t)
+		req.SelfieImage = nil
+
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.SubmitConsumer(userID, req)
+		assert.ErrorIs(t, err, services.ErrKYCImagesRequired)
+	})
+
+	t.Run("FutureDateOfBirth", func(t *testing.T) {
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(t.TempDir()), config.KYCConfig{MaxImageSizeMB: 1})
+
+		req := newRequest(t)
+		req.DateOfBirth = "2999-01-01"
+
+		_, err := service.SubmitConsumer(userID, req)
+		assert.ErrorIs(t, err, services.ErrInvalidDateOfBirth)
+	})
+
+	t.Run("NIKRegisteredToAnotherUser", func(t *testing.T) {
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(t.TempDir()), config.KYCConfig{MaxImageSizeMB: 1})
+
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(nil, gorm.ErrRecordNotFound)
+		mockConsumerRepo.EXPECT().FindByNIK("3171010101900001").Return(&entity.Consumer{ID: 1, UserID: 2}, nil)
+
+		_, err := service.SubmitConsumer(userID, newRequest(t))
+		assert.ErrorIs(t, err, services.ErrNIKAlreadyRegistered)
+	})
+
+	t.Run("NIKRegisteredConcurrently", func(t *testing.T) {
+		uploadDir := t.TempDir()
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(uploadDir), config.KYCConfig{MaxImageSizeMB: 1})
+
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(nil, gorm.ErrRecordNotFound)
+		mockConsumerRepo.EXPECT().FindByNIK("3171010101900001").Return(nil, gorm.ErrRecordNotFound)
+		mockConsumerRepo.EXPECT().Create(gomock.Any()).Return(gorm.ErrDuplicatedKey)
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.SubmitConsumer(userID, newRequest(t))
+		assert.ErrorIs(t, err, services.ErrNIKAlreadyRegistered)
+		assert.Empty(t, storedFiles(t, filepath.Join(uploadDir, "ktp")), "stored images are removed")
+		assert.Empty(t, storedFiles(t, filepath.Join(uploadDir, "selfie")))
+	})
+
+	t.Run("SubmittedTwiceConcurrently", func(t *testing.T) {
+		// Both first submissions found no profile; the other one was saved first
+		uploadDir := t.TempDir()
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(uploadDir), config.KYCConfig{MaxImageSizeMB: 1})
+
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(nil, gorm.ErrRecordNotFound)
+		mockConsumerRepo.EXPECT().FindByNIK("3171010101900001").Return(nil, gorm.ErrRecordNotFound)
+		mockConsumerRepo.EXPECT().Create(gomock.Any()).Return(gorm.ErrDuplicatedKey)
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(&entity.Consumer{ID: 8, UserID: userID}, nil)
+
+		_, err := service.SubmitConsumer(userID, newRequest(t))
+		assert.ErrorIs(t, err, services.ErrConcurrentSubmission)
+		assert.Empty(t, storedFiles(t, filepath.Join(uploadDir, "ktp")), "stored images are removed")
+	})
+
+	t.Run("VerifiedConsumerLocked", func(t *testing.T) {
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(t.TempDir()), config.KYCConfig{MaxImageSizeMB: 1})
+
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(&entity.Consumer{ID: 3, UserID: userID, KYCStatus: entity.KYCVerified}, nil)
+
+		_, err := service.SubmitConsumer(userID, newRequest(t))
+		assert.ErrorIs(t, err, services.ErrKYCAlreadyVerified)
+	})
+
+	t.Run("ResubmitAfterRejection", func(t *testing.T) {
+		uploadDir := t.TempDir()
+		service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(uploadDir), config.KYCConfig{MaxImageSizeMB: 1})
+		assert.NoError(t, os.MkdirAll(filepath.Join(uploadDir, "ktp"), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(uploadDir, "ktp", "old.png"), pngImage, 0o644))
+
+		adminID := uint(1)
+		existing := &entity.Consumer{
+			ID: 3, UserID: userID, NIK: "3171010101900001", KTPImage: "old.png", SelfieImage: "selfie.png",
+			KYCStatus: entity.KYCRejected, KYCReviewedBy: &adminID, KYCRejectionReason: "blurry KTP",
+		}
+		req := newRequest(t)
+		req.SelfieImage = nil // keeps the selfie on file
+
+		mockConsumerRepo.EXPECT().FindByUserID(userID).Return(existing, nil)
+		mockConsumerRepo.EXPECT().FindByNIK(req.NIK).Return(existing, nil)
+		mockConsumerRepo.EXPECT().Update(existing).Return(nil)
+
+		consumer, err := service.SubmitConsumer(userID, req)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.KYCSubmitted, consumer.KYCStatus)
+		assert.Nil(t, consumer.KYCReviewedBy)
+		assert.Empty(t, consumer.KYCRejectionReason)
+		assert.Equal(t, "selfie.png", consumer.SelfieImage)
+		assert.Equal(t, []string{consumer.KTPImage}, storedFiles(t, filepath.Join(uploadDir, "ktp")), "the replaced KTP image is removed")
+	})
+}
+
+func TestConsumerService_UpdateKYCStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConsumerRepo := mock.NewMockConsumerRepository(ctrl)
+	service := services.NewConsumerService(mockConsumerRepo, storage.NewLocal(t.TempDir()), config.KYCConfig{MaxImageSizeMB: 1})
+	adminID := uint(1)
+
+	t.Run("Reject", func(t *testing.T) {
+		consumer := &entity.Consumer{ID: 3, UserID: 5, KYCStatus: entity.KYCSubmitted}
+		mockConsumerRepo.EXPECT().FindByUserID(uint(5)).Return(consumer, nil)
+		mockConsumerRepo.EXPECT().Update(consumer).Return(nil)
+
+		result, err := service.UpdateKYCStatus(adminID, 5, dto.UpdateKYCStatusRequest{Status: "rejected", Reason: "KTP unreadable"})
+		assert.NoError(t, err)
+		assert.Equal(t, entity.KYCRejected, result.KYCStatus)
+		assert.Equal(t, "KTP unreadable", result.KYCRejectionReason)
+		assert.Equal(t, adminID, *result.KYCReviewedBy)
+		assert.NotNil(t, result.KYCReviewedAt)
+	})
+
+	t.Run("VerifyClearsRejectionReason", func(t *testing.T) {
+		consumer := &entity.Consumer{ID: 3, UserID: 5, KYCStatus: entity.KYCRejected, KYCRejectionReason: "KTP unreadable"}
+		mockConsumerRepo.EXPECT().FindByUserID(uint(5)).Return(consumer, nil)
+		mockConsumerRepo.EXPECT().Update(consumer).Return(nil)
+
+		result, err := service.UpdateKYCStatus(adminID, 5, dto.UpdateKYCStatusRequest{Status: "verified"})
+		assert.NoError(t, err)
+		assert.Equal(t, entity.KYCVerified, result.KYCStatus)
+		assert.Empty(t, result.KYCRejectionReason)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockConsumerRepo.EXPECT().FindByUserID(uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.UpdateKYCStatus(adminID, 9, dto.UpdateKYCStatusRequest{Status: "verified"})
+		assert.ErrorIs(t, err, services.ErrConsumerNotFound)
+	})
+}
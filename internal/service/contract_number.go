@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ContractNumberGenerator issues contract numbers in the configured format backed by a DB sequence
+type ContractNumberGenerator struct {
+	cfg          config.ContractNumberConfig
+	sequenceRepo repository.ContractSequenceRepository
+}
+
+func NewContractNumberGenerator(cfg config.ContractNumberConfig, sequenceRepo repository.ContractSequenceRepository) *ContractNumberGenerator {
+	return &ContractNumberGenerator{cfg: cfg, sequenceRepo: sequenceRepo}
+}
+
+// Generate reserves the next sequence number for the date of at and renders the contract number.
+// tx must be the DB transaction that creates the contract.
+func (g *ContractNumberGenerator) Generate(tx *gorm.DB, at time.Time) (string, error) {
+	date := at.Format(g.cfg.DateFormat)
+
+	seq, err := g.sequenceRepo.WithTx(tx).Next(g.cfg.Prefix + "|" + g.cfg.Branch + "|" + date)
+	if err != nil {
+		return "", err
+	}
+
+	number := strings.NewReplacer(
+		"{PREFIX}", g.cfg.Prefix,
+		"{BRANCH}", g.cfg.Branch,
+		"{DATE}", date,
+		"{SEQ}", fmt.Sprintf("%0*d", g.cfg.SequenceDigits, seq),
+	).Replace(g.cfg.Format)
+
+	if strings.Contains(number, "{CHECK}") {
+		check := luhnCheckDigit(strings.Replace(number, "{CHECK}", "", 1))
+		number = strings.Replace(number, "{CHECK}", strconv.Itoa(check), 1)
+	}
+
+	return number, nil
+}
+
+// luhnCheckDigit computes the Luhn (mod 10) check digit over the digits of s, ignoring other characters
+func luhnCheckDigit(s string) int {
+	sum := 0
+	double := true // the check digit is appended on the right, so the rightmost digit is doubled
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
@@ -0,0 +1,54 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository/mock"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestContractNumberGenerator_Generate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSequenceRepo := mock.NewMockContractSequenceRepository(ctrl)
+	at := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	t.Run("DefaultFormatWithCheckDigit", func(t *testing.T) {
+		generator := services.NewContractNumberGenerator(config.ContractNumberConfig{
+			Prefix:         "XYZ",
+			Branch:         "001",
+			DateFormat:     "200601",
+			SequenceDigits: 6,
+			Format:         "{PREFIX}-{BRANCH}-{DATE}-{SEQ}{CHECK}",
+		}, mockSequenceRepo)
+
+		mockSequenceRepo.EXPECT().WithTx(gomock.Any()).Return(mockSequenceRepo)
+		mockSequenceRepo.EXPECT().Next("XYZ|001|202610").Return(uint64(1), nil)
+
+		number, err := generator.Generate(nil, at)
+		assert.NoError(t, err)
+		assert.Equal(t, "XYZ-001-202610-0000018", number)
+	})
+
+	t.Run("CustomFormatWithoutCheckDigit", func(t *testing.T) {
+		generator := services.NewContractNumberGenerator(config.ContractNumberConfig{
+			Prefix:         "KB",
+			Branch:         "BDG",
+			DateFormat:     "060102",
+			SequenceDigits: 4,
+			Format:         "{PREFIX}/{DATE}/{BRANCH}/{SEQ}",
+		}, mockSequenceRepo)
+
+		mockSequenceRepo.EXPECT().WithTx(gomock.Any()).Return(mockSequenceRepo)
+		mockSequenceRepo.EXPECT().Next("KB|BDG|261018").Return(uint64(42), nil)
+
+		number, err := generator.Generate(nil, at)
+		assert.NoError(t, err)
+		assert.Equal(t, "KB/261018/BDG/0042", number)
+	})
+}
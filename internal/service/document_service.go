@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	"github.com/hadi-projects/xyz-finance-go/pkg/storage"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDocumentAccessDenied     = errors.New("not allowed to access these documents")
+	ErrInvalidDocumentSignature = errors.New("invalid or expired document link")
+	ErrDocumentNotFound         = errors.New("document not found")
+)
+
+// kycReviewPermission lets a user view every consumer's KYC documents
+const kycReviewPermission = "verify-kyc"
+
+// documentPathPrefix is where signed document URLs are served
+const documentPathPrefix = "/api/documents/"
+
+// storageURLTTL is how long the storage backend's own URL, which a checked document link
+// redirects to, stays valid. It only has to outlive the redirect.
+const storageURLTTL = time.Minute
+
+// Document is a fetched KYC image: either a URL on the storage backend to redirect to, or the
+// content itself when the backend cannot issue URLs. The caller must close Body.
+type Document struct {
+	RedirectURL string
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+}
+
+type DocumentService interface {
+	IssueURLs(viewerID uint, ownerID uint) (*dto.DocumentURLs, error)
+	SignURLs(viewerID uint, consumer *entity.Consumer) dto.DocumentURLs
+	Fetch(viewerID uint, kind string, name string, query dto.DocumentQuery) (*Document, error)
+}
+
+type documentService struct {
+	userRepo     repository.UserRepository
+	consumerRepo repository.ConsumerRepository
+	store        storage.Storage
+	cfg          config.KYCConfig
+}
+
+func NewDocumentService(userRepo repository.UserRepository, consumerRepo repository.ConsumerRepository, store storage.Storage, cfg config.KYCConfig) DocumentService {
+	return &documentService{
+		userRepo:     userRepo,
+		consumerRepo: consumerRepo,
+		store:        store,
+		cfg:          cfg,
+	}
+}
+
+// IssueURLs signs links to the owner's KYC images for the viewer, who must be the owner or
+// hold the KYC review permission
+func (s *documentService) IssueURLs(viewerID uint, ownerID uint) (*dto.DocumentURLs, error) {
+	if err := s.checkAccess(viewerID, ownerID); err != nil {
+		return nil, err
+	}
+
+	consumer, err := s.consumerRepo.FindByUserID(ownerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrConsumerNotFound
+		}
+		return nil, err
+	}
+
+	urls := s.SignURLs(viewerID, consumer)
+	return &urls, nil
+}
+
+// SignURLs signs links to the consumer's KYC images without checking access; callers must
+// already have established that the viewer may see them
+func (s *documentService) SignURLs(viewerID uint, consumer *entity.Consumer) dto.DocumentURLs {
+	expires := time.Now().Add(time.Duration(s.cfg.URLTTLMinutes) * time.Minute).Truncate(time.Second)
+	return dto.DocumentURLs{
+		KTPImage:    s.signedPath(kycImageKTP, consumer.KTPImage, consumer.UserID, viewerID, expires.Unix()),
+		SelfieImage: s.signedPath(kycImageSelfie, consumer.SelfieImage, consumer.UserID, viewerID, expires.Unix()),
+		ExpiresAt:   expires,
+	}
+}
+
+// Fetch checks a document link's signature and expiry, that it was issued to viewerID (the
+// authenticated user) and that the viewer still is the owner or a KYC reviewer, and returns the
+// image from storage. A leaked link is of no use to anyone else.
+func (s *documentService) Fetch(viewerID uint, kind string, name string, query dto.DocumentQuery) (*Document, error) {
+	if kind != kycImageKTP && kind != kycImageSelfie {
+		return nil, ErrDocumentNotFound
+	}
+
+	expected := s.signature(kind, name, query.Owner, query.Viewer, query.Expires)
+	given, err := hex.DecodeString(query.Signature)
+	if err != nil || !hmac.Equal(given, expected) {
+		return nil, ErrInvalidDocumentSignature
+	}
+	if time.Now().Unix() > query.Expires {
+		return nil, ErrInvalidDocumentSignature
+	}
+	if viewerID != query.Viewer {
+		return nil, ErrDocumentAccessDenied
+	}
+	if err := s.checkAccess(viewerID, query.Owner); err != nil {
+		return nil, err
+	}
+
+	// Names are generated on upload; anything that is not a plain file name is not ours
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return nil, ErrDocumentNotFound
+	}
+
+	ctx := context.Background()
+	key := documentKey(kind, name)
+	redirect, err := s.store.SignedURL(ctx, key, storageURLTTL)
+	if err == nil {
+		return &Document{RedirectURL: redirect}, nil
+	}
+	if !errors.Is(err, storage.ErrSignedURLUnsupported) {
+		return nil, err
+	}
+
+	object, err := s.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	return &Document{Body: object.Body, Size: object.Size, ContentType: object.ContentType}, nil
+}
+
+// checkAccess allows the owner and users with the KYC review permission
+func (s *documentService) checkAccess(viewerID, ownerID uint) error {
+	if viewerID == ownerID {
+		return nil
+	}
+	viewer, err := s.userRepo.FindByID(viewerID)
+	if err != nil {
+		return err
+	}
+	if !hasPermission(viewer, kycReviewPermission) {
+		return ErrDocumentAccessDenied
+	}
+	return nil
+}
+
+func (s *documentService) signedPath(kind, name string, ownerID, viewerID uint, expires int64) string {
+	query := url.Values{}
+	query.Set("owner", strconv.FormatUint(uint64(ownerID), 10))
+	query.Set("viewer", strconv.FormatUint(uint64(viewerID), 10))
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", hex.EncodeToString(s.signature(kind, name, ownerID, viewerID, expires)))
+	return s.cfg.PublicBaseURL + documentPathPrefix + kind + "/" + url.PathEscape(name) + "?" + query.Encode()
+}
+
+// signature is the HMAC over everything a link grants: which file, for whom, until when
+func (s *documentService) signature(kind, name string, ownerID, viewerID uint, expires int64) []byte {
+	mac := hmac.New(sha256.New, []byte(s.cfg.URLSecret))
+	fmt.Fprintf(mac, "%s/%s|%d|%d|%d", kind, name, ownerID, viewerID, expires)
+	return mac.Sum(nil)
+}
+
+func hasPermission(user *entity.User, permission string) bool {
+	for _, p := range user.Role.Permissions {
+		if p.Name == permission {
+			return true
+		}
+	}
+	return false
+}
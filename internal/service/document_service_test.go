@@ -0,0 +1,233 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository/mock"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+// parseDocumentURL splits a signed document path into the kind, file name and query it carries
+func parseDocumentURL(t *testing.T, raw string) (string, string, dto.DocumentQuery) {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", raw, err)
+	}
+	parts := strings.Split(strings.TrimPrefix(u.EscapedPath(), "/api/documents/"), "/")
+	if len(parts) != 2 {
+		t.Fatalf("unexpected document path %s", u.EscapedPath())
+	}
+	name, err := url.PathUnescape(parts[1])
+	if err != nil {
+		t.Fatalf("failed to unescape %s: %v", parts[1], err)
+	}
+
+	q := u.Query()
+	owner, _ := strconv.ParseUint(q.Get("owner"), 10, 32)
+	viewer, _ := strconv.ParseUint(q.Get("viewer"), 10, 32)
+	expires, _ := strconv.ParseInt(q.Get("expires"), 10, 64)
+	return parts[0], name, dto.DocumentQuery{Owner: uint(owner), Viewer: uint(viewer), Expires: expires, Signature: q.Get("signature")}
+}
+
+func TestDocumentService_SignedURLs(t *testing.T) {
+	uploadDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(uploadDir, "ktp"), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(uploadDir, "ktp", "budi.webp"), []byte("RIFF"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	store := storage.NewLocal(uploadDir)
+	cfg := config.KYCConfig{URLSecret: "document-secret", URLTTLMinutes: 5}
+	service := services.NewDocumentService(nil, nil, store, cfg)
+	consumer := &entity.Consumer{UserID: 2, KTPImage: "budi.webp", SelfieImage: "budi.jpeg"}
+
+	urls := service.SignURLs(2, consumer)
+	kind, name, query := parseDocumentURL(t, urls.KTPImage)
+	assert.Equal(t, "ktp", kind)
+	assert.Equal(t, "budi.webp", name)
+	assert.Equal(t, uint(2), query.Owner)
+	assert.Equal(t, urls.ExpiresAt.Unix(), query.Expires)
+
+	t.Run("Valid", func(t *testing.T) {
+		document, err := service.Fetch(2, kind, name, query)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer document.Body.Close()
+		content, err := io.ReadAll(document.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "RIFF", string(content))
+		assert.Empty(t, document.RedirectURL, "local storage is served by the API itself")
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		tampered := map[string]func(q *dto.DocumentQuery) string{
+			"Viewer":  func(q *dto.DocumentQuery) string { q.Viewer = 3; return name },
+			"Expires": func(q *dto.DocumentQuery) string { q.Expires += 3600; return name },
+			"File":    func(q *dto.DocumentQuery) string { return "annisa.jpeg" },
+		}
+		for field, tamper := range tampered {
+			q := query
+			file := tamper(&q)
+			_, err := service.Fetch(2, kind, file, q)
+			assert.ErrorIs(t, err, services.ErrInvalidDocumentSignature, field)
+		}
+
+		_, err := service.Fetch(2, "selfie", name, query)
+		assert.ErrorIs(t, err, services.ErrInvalidDocumentSignature)
+	})
+
+	t.Run("OtherSecret", func(t *testing.T) {
+		other := services.NewDocumentService(nil, nil, store, config.KYCConfig{URLSecret: "other", URLTTLMinutes: 5})
+		_, err := other.Fetch(2, kind, name, query)
+		assert.ErrorIs(t, err, services.ErrInvalidDocumentSignature)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		// A negative lifetime signs links that have already expired
+		expired := services.NewDocumentService(nil, nil, store, config.KYCConfig{URLSecret: "document-secret", URLTTLMinutes: -1})
+		kind, name, query := parseDocumentURL(t, expired.SignURLs(2, consumer).KTPImage)
+
+		_, err := service.Fetch(2, kind, name, query)
+		assert.ErrorIs(t, err, services.ErrInvalidDocumentSignature)
+	})
+
+	t.Run("OtherViewer", func(t *testing.T) {
+		// A valid link used by someone it was not issued to
+		_, err := service.Fetch(3, kind, name, query)
+		assert.ErrorIs(t, err, services.ErrDocumentAccessDenied)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		kind, name, query := parseDocumentURL(t, urls.SelfieImage)
+		_, err := service.Fetch(2, kind, name, query)
+		assert.ErrorIs(t, err, services.ErrDocumentNotFound)
+	})
+
+	t.Run("PathTraversal", func(t *testing.T) {
+		traversal := &entity.Consumer{UserID: 2, KTPImage: "../../config.env"}
+		kind, name, query := parseDocumentURL(t, service.SignURLs(2, traversal).KTPImage)
+		assert.Equal(t, "../../config.env", name)
+
+		_, err := service.Fetch(2, kind, name, query)
+		assert.ErrorIs(t, err, services.ErrDocumentNotFound)
+	})
+}
+
+// signingStore is a storage backend that hands out its own URLs, like S3
+type signingStore struct {
+	storage.Storage
+}
+
+func (signingStore) SignedURL(_ context.Context, key string, ttl time.Duration) (string, error) {
+	return "https://storage.example.com/" + key + "?ttl=" + ttl.String(), nil
+}
+
+func TestDocumentService_FetchRedirectsToStorage(t *testing.T) {
+	service := services.NewDocumentService(nil, nil, signingStore{}, config.KYCConfig{URLSecret: "document-secret", URLTTLMinutes: 5})
+	consumer := &entity.Consumer{UserID: 2, KTPImage: "budi.webp", SelfieImage: "budi.jpeg"}
+
+	kind, name, query := parseDocumentURL(t, service.SignURLs(2, consumer).SelfieImage)
+	document, err := service.Fetch(2, kind, name, query)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://storage.example.com/selfie/budi.jpeg?ttl=1m0s", document.RedirectURL)
+	assert.Nil(t, document.Body)
+}
+
+func TestDocumentService_FetchChecksReviewer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	service := services.NewDocumentService(mockUserRepo, nil, signingStore{}, config.KYCConfig{URLSecret: "document-secret", URLTTLMinutes: 5})
+	consumer := &entity.Consumer{UserID: 2, KTPImage: "budi.webp", SelfieImage: "budi.jpeg"}
+	kind, name, query := parseDocumentURL(t, service.SignURLs(1, consumer).KTPImage)
+
+	t.Run("Reviewer", func(t *testing.T) {
+		reviewer := &entity.User{ID: 1, Role: entity.Role{Name: "admin", Permissions: []entity.Permission{{Name: "verify-kyc"}}}}
+		mockUserRepo.EXPECT().FindByID(uint(1)).Return(reviewer, nil)
+
+		_, err := service.Fetch(1, kind, name, query)
+		assert.NoError(t, err)
+	})
+
+	t.Run("PermissionRevoked", func(t *testing.T) {
+		demoted := &entity.User{ID: 1, Role: entity.Role{Name: "user"}}
+		mockUserRepo.EXPECT().FindByID(uint(1)).Return(demoted, nil)
+
+		_, err := service.Fetch(1, kind, name, query)
+		assert.ErrorIs(t, err, services.ErrDocumentAccessDenied)
+	})
+}
+
+func TestDocumentService_IssueURLs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockConsumerRepo := mock.NewMockConsumerRepository(ctrl)
+	service := services.NewDocumentService(mockUserRepo, mockConsumerRepo, nil, config.KYCConfig{URLSecret: "document-secret", URLTTLMinutes: 5})
+	consumer := &entity.Consumer{UserID: 2, KTPImage: "budi.webp", SelfieImage: "budi.jpeg"}
+
+	t.Run("Owner", func(t *testing.T) {
+		mockConsumerRepo.EXPECT().FindByUserID(uint(2)).Return(consumer, nil)
+
+		urls, err := service.IssueURLs(2, 2)
+		assert.NoError(t, err)
+		assert.Contains(t, urls.KTPImage, "/api/documents/ktp/budi.webp?")
+		assert.Contains(t, urls.KTPImage, "viewer=2")
+	})
+
+	t.Run("Reviewer", func(t *testing.T) {
+		reviewer := &entity.User{ID: 1, Role: entity.Role{Name: "admin", Permissions: []entity.Permission{{Name: "verify-kyc"}}}}
+		mockUserRepo.EXPECT().FindByID(uint(1)).Return(reviewer, nil)
+		mockConsumerRepo.EXPECT().FindByUserID(uint(2)).Return(consumer, nil)
+
+		urls, err := service.IssueURLs(1, 2)
+		assert.NoError(t, err)
+		assert.Contains(t, urls.SelfieImage, "viewer=1")
+	})
+
+	t.Run("PublicBaseURL", func(t *testing.T) {
+		public := services.NewDocumentService(mockUserRepo, mockConsumerRepo, nil, config.KYCConfig{URLSecret: "document-secret", URLTTLMinutes: 5, PublicBaseURL: "https://api.example.com"})
+		mockConsumerRepo.EXPECT().FindByUserID(uint(2)).Return(consumer, nil)
+
+		urls, err := public.IssueURLs(2, 2)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(urls.KTPImage, "https://api.example.com/api/documents/ktp/budi.webp?"), urls.KTPImage)
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		other := &entity.User{ID: 3, Role: entity.Role{Name: "user", Permissions: []entity.Permission{{Name: "get-limit"}}}}
+		mockUserRepo.EXPECT().FindByID(uint(3)).Return(other, nil)
+
+		_, err := service.IssueURLs(3, 2)
+		assert.ErrorIs(t, err, services.ErrDocumentAccessDenied)
+	})
+
+	t.Run("NoConsumer", func(t *testing.T) {
+		mockConsumerRepo.EXPECT().FindByUserID(uint(4)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.IssueURLs(4, 4)
+		assert.ErrorIs(t, err, services.ErrConsumerNotFound)
+	})
+}
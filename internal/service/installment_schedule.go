@@ -0,0 +1,81 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
+// buildInstallmentSchedule splits a transaction into one installment per month of its tenor.
+// Flat-rate transactions (and client-priced ones without an interest method) spread OTR and
+// interest evenly; effective-rate transactions follow an annuity on the declining balance.
+// The admin fee is always spread evenly. Rounding leftovers land on the last installment so the
+// schedule adds up to the contract amounts. The first installment is due one month after start.
+func buildInstallmentSchedule(transaction *entity.Transaction, start time.Time) []entity.Installment {
+	tenor := transaction.Tenor
+	if tenor <= 0 {
+		return nil
+	}
+
+	var principals, interests []money.Money
+	if transaction.InterestMethod == entity.InterestEffective {
+		principals, interests = annuitySplit(transaction.OTR, transaction.InterestRate/100/12, tenor)
+	} else {
+		principals = transaction.OTR.Split(tenor)
+		interests = transaction.InterestAmount.Split(tenor)
+	}
+	adminFees := transaction.AdminFee.Split(tenor)
+
+	installments := make([]entity.Installment, 0, tenor)
+	for i := 0; i < tenor; i++ {
+		installments = append(installments, entity.Installment{
+			TransactionID:     transaction.ID,
+			InstallmentNumber: i + 1,
+			DueDate:           addMonths(start, i+1),
+			PrincipalAmount:   principals[i],
+			InterestAmount:    interests[i],
+			AdminFeeAmount:    adminFees[i],
+			TotalAmount:       principals[i] + interests[i] + adminFees[i],
+			Status:            entity.InstallmentUnpaid,
+		})
+	}
+	return installments
+}
+
+// annuitySplit splits principal into n equal monthly payments at monthlyRate and returns the
+// principal and interest part of each payment. Interest is charged on the remaining balance and
+// rounded to the sen; the last payment clears whatever balance rounding left over.
+func annuitySplit(principal money.Money, monthlyRate float64, n int) ([]money.Money, []money.Money) {
+	if monthlyRate <= 0 {
+		return principal.Split(n), make([]money.Money, n)
+	}
+
+	payment := principal.MulRate(monthlyRate / (1 - math.Pow(1+monthlyRate, -float64(n))))
+	balance := principal
+
+	principals := make([]money.Money, n)
+	interests := make([]money.Money, n)
+	for i := 0; i < n; i++ {
+		interests[i] = balance.MulRate(monthlyRate)
+		principals[i] = payment - interests[i]
+		if i == n-1 || principals[i] > balance {
+			principals[i] = balance
+		}
+		balance -= principals[i]
+	}
+	return principals, interests
+}
+
+// addMonths adds n calendar months to t, clamping to the last day of the target month
+// (e.g. 31 Jan + 1 month = 28/29 Feb instead of rolling over into March).
+func addMonths(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	firstOfTarget := time.Date(year, month+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(firstOfTarget.Year(), firstOfTarget.Month(), day, 0, 0, 0, 0, t.Location())
+}
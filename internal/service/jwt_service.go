@@ -1,33 +1,73 @@
 package services
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/hadi-projects/xyz-finance-go/internal/entity"
 	"github.com/hadi-projects/xyz-finance-go/internal/middleware"
 	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	"github.com/hadi-projects/xyz-finance-go/pkg/jwtkeys"
+	"gorm.io/gorm"
 )
 
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used; all sessions from this login are revoked")
+)
+
+// TokenPair is the result of a token refresh
+type TokenPair struct {
+	UserID       uint
+	AccessToken  string
+	RefreshToken string
+}
+
+// RefreshTokenReuseError is returned when a used refresh token is presented again; it unwraps to
+// ErrRefreshTokenReused
+type RefreshTokenReuseError struct {
+	UserID uint
+}
+
+func (e *RefreshTokenReuseError) Error() string {
+	return ErrRefreshTokenReused.Error()
+}
+
+func (e *RefreshTokenReuseError) Unwrap() error {
+	return ErrRefreshTokenReused
+}
+
+// refreshTokenTTL is how long a refresh token can be exchanged; every refresh issues a new one
+const refreshTokenTTL = 7 * 24 * time.Hour
+
 type JWTService interface {
 	GenerateToken(userID uint, email string) (string, error)
 	GenerateRefreshToken(userID uint) (string, error)
+	ValidateToken(tokenString string) (*middleware.JWTClaims, error)
+	RefreshAccessToken(refreshToken string) (*TokenPair, error)
+	RevokeSession(userID uint, refreshToken, tokenID string, tokenExpiresAt time.Time) error
+	RevokeAllSessions(userID uint) error
 }
 
 type jwtService struct {
-	secret           string
+	keys             *jwtkeys.KeySet
 	expiryHours      int
 	refreshTokenRepo repository.RefreshTokenRepository
+	revocations      middleware.TokenRevocationStore
 }
 
-func NewJWTService(secret string, expiryHours int, refreshTokenRepo repository.RefreshTokenRepository) JWTService {
+func NewJWTService(keys *jwtkeys.KeySet, expiryHours int, refreshTokenRepo repository.RefreshTokenRepository, revocations middleware.TokenRevocationStore) JWTService {
 	return &jwtService{
-		secret:           secret,
+		keys:             keys,
 		expiryHours:      expiryHours,
 		refreshTokenRepo: refreshTokenRepo,
+		revocations:      revocations,
 	}
 }
 
@@ -38,11 +78,11 @@ func (s *jwtService) GenerateToken(userID uint, email string) (string, error) {
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(s.expiryHours))),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.secret))
+	tokenString, err := s.keys.Sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token: %w", err)
 	}
@@ -50,7 +90,12 @@ func (s *jwtService) GenerateToken(userID uint, email string) (string, error) {
 	return tokenString, nil
 }
 
+// GenerateRefreshToken issues the first refresh token of a new login, starting a token family
 func (s *jwtService) GenerateRefreshToken(userID uint) (string, error) {
+	return s.issueRefreshToken(userID, uuid.NewString())
+}
+
+func (s *jwtService) issueRefreshToken(userID uint, familyID string) (string, error) {
 	b := make([]byte, 32)
 	if _, err := rand.Read(b); err != nil {
 		return "", fmt.Errorf("failed to generate random token: %w", err)
@@ -60,7 +105,8 @@ func (s *jwtService) GenerateRefreshToken(userID uint) (string, error) {
 	refreshToken := &entity.RefreshToken{
 		UserID:    userID,
 		Token:     tokenString,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour), // 7 days
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
 		Revoked:   false,
 	}
 
@@ -72,10 +118,7 @@ func (s *jwtService) GenerateRefreshToken(userID uint) (string, error) {
 }
 
 func (s *jwtService) ValidateToken(tokenString string) (*middleware.JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &middleware.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.secret), nil
-	})
-
+	token, err := s.keys.Parse(tokenString, &middleware.JWTClaims{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
@@ -87,22 +130,99 @@ func (s *jwtService) ValidateToken(tokenString string) (*middleware.JWTClaims, e
 	return nil, fmt.Errorf("invalid token")
 }
 
-func (s *jwtService) RefreshAccessToken(refreshToken string) (string, string, error) {
+// RefreshAccessToken exchanges a refresh token for a new access token and a new refresh token
+// of the same family; the presented token is used up. Presenting a token that was already used
+// means it has leaked (or the legitimate client is racing an attacker), so the whole family is
+// revoked and a *RefreshTokenReuseError returned.
+func (s *jwtService) RefreshAccessToken(refreshToken string) (*TokenPair, error) {
 	storedToken, err := s.refreshTokenRepo.FindByToken(refreshToken)
 	if err != nil {
-		return "", "", fmt.Errorf("invalid refresh token")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if storedToken.Revoked {
+		return nil, s.revokeReusedFamily(storedToken)
+	}
+	if !storedToken.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	consumed, err := s.refreshTokenRepo.Consume(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		// Another request used the token between the lookup and now
+		return nil, s.revokeReusedFamily(storedToken)
 	}
 
-	accessToken, err := s.GenerateToken(storedToken.UserID, "")
+	accessToken, err := s.GenerateToken(storedToken.UserID, storedToken.User.Email)
+	if err != nil {
+		return nil, err
+	}
+	newRefreshToken, err := s.issueRefreshToken(storedToken.UserID, storedToken.FamilyID)
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+
+	return &TokenPair{UserID: storedToken.UserID, AccessToken: accessToken, RefreshToken: newRefreshToken}, nil
+}
+
+// revokeReusedFamily revokes every token rotated from the same login as a reused token. Tokens
+// issued before families existed have none; for those all of the user's tokens are revoked.
+func (s *jwtService) revokeReusedFamily(token *entity.RefreshToken) error {
+	var err error
+	if token.FamilyID == "" {
+		err = s.refreshTokenRepo.RevokeAllByUserID(token.UserID)
+	} else {
+		err = s.refreshTokenRepo.RevokeFamily(token.FamilyID)
 	}
-	newRefreshToken, err := s.GenerateRefreshToken(storedToken.UserID)
 	if err != nil {
-		return "", "", err
+		return err
 	}
+	return &RefreshTokenReuseError{UserID: token.UserID}
+}
 
-	_ = s.refreshTokenRepo.RevokeByToken(refreshToken)
+// RevokeSession logs out one session: the refresh token's family is revoked, so neither the
+// token nor any token rotated from the same login can be used again, and the access token
+// identified by tokenID is rejected until it expires. Revoking an already revoked session is
+// not an error.
+func (s *jwtService) RevokeSession(userID uint, refreshToken, tokenID string, tokenExpiresAt time.Time) error {
+	storedToken, err := s.refreshTokenRepo.FindByToken(refreshToken)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+	if storedToken.UserID != userID {
+		return ErrInvalidRefreshToken
+	}
+	if storedToken.FamilyID == "" {
+		err = s.refreshTokenRepo.RevokeByToken(refreshToken)
+	} else {
+		err = s.refreshTokenRepo.RevokeFamily(storedToken.FamilyID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.revocations.RevokeToken(context.Background(), tokenID, tokenExpiresAt)
+}
+
+// RevokeAllSessions logs the user out of every session: all refresh tokens are revoked and
+// every access token issued so far is rejected until it would have expired. Used on logout-all,
+// password change, role change and suspension.
+func (s *jwtService) RevokeAllSessions(userID uint) error {
+	if err := s.refreshTokenRepo.RevokeAllByUserID(userID); err != nil {
+		return err
+	}
 
-	return accessToken, newRefreshToken, nil
+	// iat has millisecond precision: the cutoff is the start of the next millisecond, so every
+	// token issued up to now is rejected and tokens issued afterwards are not
+	now := time.Now()
+	cutoff := now.Truncate(time.Millisecond).Add(time.Millisecond)
+	return s.revocations.RevokeUserTokens(context.Background(), userID, cutoff, now.Add(time.Hour*time.Duration(s.expiryHours)))
 }
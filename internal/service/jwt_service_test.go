@@ -0,0 +1,214 @@
+package services_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/middleware"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository/mock"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/jwtkeys"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+// newTestKeySet returns a key set with one fresh Ed25519 key
+func newTestKeySet(t *testing.T) *jwtkeys.KeySet {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("failed to encode key: %v", err)
+	}
+	keys, err := jwtkeys.NewKeySet(config.JWTConfig{
+		SigningKeys: []config.JWTSigningKey{{ID: "test-key", PEM: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})}},
+		ActiveKeyID: "test-key",
+	})
+	if err != nil {
+		t.Fatalf("failed to create key set: %v", err)
+	}
+	return keys
+}
+
+func TestJWTService_RefreshAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+	service := services.NewJWTService(newTestKeySet(t), 1, mockRefreshRepo, middleware.NewMemoryTokenRevocationStore())
+
+	activeToken := func() *entity.RefreshToken {
+		return &entity.RefreshToken{
+			UserID:    2,
+			Token:     "old-token",
+			FamilyID:  "family-1",
+			ExpiresAt: time.Now().Add(time.Hour),
+			User:      entity.User{ID: 2, Email: "budi@mail.com"},
+		}
+	}
+
+	t.Run("Rotates", func(t *testing.T) {
+		mockRefreshRepo.EXPECT().FindByToken("old-token").Return(activeToken(), nil)
+		mockRefreshRepo.EXPECT().Consume("old-token").Return(true, nil)
+		mockRefreshRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *entity.RefreshToken) error {
+			assert.Equal(t, uint(2), token.UserID)
+			assert.Equal(t, "family-1", token.FamilyID, "the new token stays in the login's family")
+			return nil
+		})
+
+		pair, err := service.RefreshAccessToken("old-token")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), pair.UserID)
+		assert.NotEqual(t, "old-token", pair.RefreshToken)
+
+		claims, err := service.ValidateToken(pair.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), claims.UserID)
+		assert.Equal(t, "budi@mail.com", claims.Email)
+	})
+
+	t.Run("ReusedTokenRevokesFamily", func(t *testing.T) {
+		used := activeToken()
+		used.Revoked = true
+		mockRefreshRepo.EXPECT().FindByToken("old-token").Return(used, nil)
+		mockRefreshRepo.EXPECT().RevokeFamily("family-1").Return(nil)
+
+		_, err := service.RefreshAccessToken("old-token")
+		assert.ErrorIs(t, err, services.ErrRefreshTokenReused)
+		var reuse *services.RefreshTokenReuseError
+		if assert.ErrorAs(t, err, &reuse) {
+			assert.Equal(t, uint(2), reuse.UserID)
+		}
+	})
+
+	t.Run("ConcurrentReuse", func(t *testing.T) {
+		mockRefreshRepo.EXPECT().FindByToken("old-token").Return(activeToken(), nil)
+		mockRefreshRepo.EXPECT().Consume("old-token").Return(false, nil)
+		mockRefreshRepo.EXPECT().RevokeFamily("family-1").Return(nil)
+
+		_, err := service.RefreshAccessToken("old-token")
+		assert.ErrorIs(t, err, services.ErrRefreshTokenReused)
+	})
+
+	t.Run("LegacyTokenWithoutFamily", func(t *testing.T) {
+		legacy := activeToken()
+		legacy.FamilyID = ""
+		legacy.Revoked = true
+		mockRefreshRepo.EXPECT().FindByToken("old-token").Return(legacy, nil)
+		mockRefreshRepo.EXPECT().RevokeAllByUserID(uint(2)).Return(nil)
+
+		_, err := service.RefreshAccessToken("old-token")
+		assert.ErrorIs(t, err, services.ErrRefreshTokenReused)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		expired := activeToken()
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		mockRefreshRepo.EXPECT().FindByToken("old-token").Return(expired, nil)
+
+		_, err := service.RefreshAccessToken("old-token")
+		assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		mockRefreshRepo.EXPECT().FindByToken("nope").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.RefreshAccessToken("nope")
+		assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+	})
+}
+
+func TestJWTService_GenerateToken(t *testing.T) {
+	service := services.NewJWTService(newTestKeySet(t), 1, nil, middleware.NewMemoryTokenRevocationStore())
+
+	first, err := service.GenerateToken(2, "budi@mail.com")
+	assert.NoError(t, err)
+	second, err := service.GenerateToken(2, "budi@mail.com")
+	assert.NoError(t, err)
+
+	firstClaims, err := service.ValidateToken(first)
+	assert.NoError(t, err)
+	secondClaims, err := service.ValidateToken(second)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, firstClaims.ID)
+	assert.NotEqual(t, firstClaims.ID, secondClaims.ID, "every token gets its own jti")
+}
+
+func TestJWTService_RevokeSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+	revocations := middleware.NewMemoryTokenRevocationStore()
+	service := services.NewJWTService(newTestKeySet(t), 1, mockRefreshRepo, revocations)
+	token := &entity.RefreshToken{UserID: 2, Token: "token", FamilyID: "family-1"}
+
+	t.Run("OwnToken", func(t *testing.T) {
+		accessToken, err := service.GenerateToken(2, "budi@mail.com")
+		assert.NoError(t, err)
+		claims, err := service.ValidateToken(accessToken)
+		assert.NoError(t, err)
+
+		mockRefreshRepo.EXPECT().FindByToken("token").Return(token, nil)
+		mockRefreshRepo.EXPECT().RevokeFamily("family-1").Return(nil)
+
+		assert.NoError(t, service.RevokeSession(2, "token", claims.ID, claims.ExpiresAt.Time))
+
+		revoked, err := revocations.IsRevoked(context.Background(), claims)
+		assert.NoError(t, err)
+		assert.True(t, revoked, "the access token of the session is revoked")
+	})
+
+	t.Run("OtherUsersToken", func(t *testing.T) {
+		mockRefreshRepo.EXPECT().FindByToken("token").Return(token, nil)
+
+		err := service.RevokeSession(3, "token", "jti", time.Now().Add(time.Hour))
+		assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+
+		revoked, _ := revocations.IsRevoked(context.Background(), &middleware.JWTClaims{UserID: 3, RegisteredClaims: jwt.RegisteredClaims{ID: "jti"}})
+		assert.False(t, revoked)
+	})
+}
+
+func TestJWTService_RevokeAllSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+	revocations := middleware.NewMemoryTokenRevocationStore()
+	service := services.NewJWTService(newTestKeySet(t), 1, mockRefreshRepo, revocations)
+
+	accessToken, err := service.GenerateToken(2, "budi@mail.com")
+	assert.NoError(t, err)
+	claims, err := service.ValidateToken(accessToken)
+	assert.NoError(t, err)
+
+	mockRefreshRepo.EXPECT().RevokeAllByUserID(uint(2)).Return(nil)
+
+	assert.NoError(t, service.RevokeAllSessions(2))
+
+	revoked, err := revocations.IsRevoked(context.Background(), claims)
+	assert.NoError(t, err)
+	assert.True(t, revoked, "access tokens issued before the revocation are rejected")
+
+	time.Sleep(2 * time.Millisecond)
+	freshToken, err := service.GenerateToken(2, "budi@mail.com")
+	assert.NoError(t, err)
+	freshClaims, err := service.ValidateToken(freshToken)
+	assert.NoError(t, err)
+
+	revoked, err = revocations.IsRevoked(context.Background(), freshClaims)
+	assert.NoError(t, err)
+	assert.False(t, revoked, "a token issued after the revocation, even within the same second, is accepted")
+}
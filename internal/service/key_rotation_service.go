@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+)
+
+type KeyRotationService interface {
+	RotateConsumers(all bool, batchSize int) (int, error)
+	BackfillNIKHashes(batchSize int) (int, error)
+}
+
+type keyRotationService struct {
+	consumerRepo repository.ConsumerRepository
+	activeKeyID  string
+}
+
+func NewKeyRotationService(consumerRepo repository.ConsumerRepository, activeKeyID string) KeyRotationService {
+	return &keyRotationService{
+		consumerRepo: consumerRepo,
+		activeKeyID:  activeKeyID,
+	}
+}
+
+// RotateConsumers re-encrypts the PII of every consumer not yet written with the active key,
+// including rows stored before encryption was enabled, and recomputes their NIK blind index.
+// With all set every consumer is rewritten, which is needed after changing the blind index key.
+// Rows are decrypted with whichever key of the ring they were written with, so old keys must stay
+// configured until this has run. It returns the number of consumers rewritten.
+func (s *keyRotationService) RotateConsumers(all bool, batchSize int) (int, error) {
+	keyID := s.activeKeyID
+	if all {
+		keyID = ""
+	}
+
+	return s.rewrite(batchSize, func(afterID uint64) ([]entity.Consumer, error) {
+		return s.consumerRepo.FindForKeyRotation(keyID, afterID, batchSize)
+	})
+}
+
+// BackfillNIKHashes encrypts the consumers stored before encryption was enabled and computes their
+// NIK blind index. It must complete before the plaintext NIK index is dropped: until then those
+// consumers are invisible to NIK lookups and their NIKs are not covered by the nik_hash unique
+// index. It returns the number of consumers rewritten.
+func (s *keyRotationService) BackfillNIKHashes(batchSize int) (int, error) {
+	return s.rewrite(batchSize, func(afterID uint64) ([]entity.Consumer, error) {
+		return s.consumerRepo.FindWithoutNIKHash(afterID, batchSize)
+	})
+}
+
+// rewrite saves every consumer returned by find, batch by batch, so the repository re-encrypts
+// it with the active key and recomputes its blind index
+func (s *keyRotationService) rewrite(batchSize int, find func(afterID uint64) ([]entity.Consumer, error)) (int, error) {
+	rotated := 0
+	var afterID uint64
+	for {
+		consumers, err := find(afterID)
+		if err != nil {
+			return rotated, err
+		}
+		for i := range consumers {
+			if err := s.consumerRepo.Update(&consumers[i]); err != nil {
+				return rotated, fmt.Errorf("rotate consumer %d: %w", consumers[i].ID, err)
+			}
+			rotated++
+		}
+		if len(consumers) < batchSize {
+			break
+		}
+		afterID = consumers[len(consumers)-1].ID
+		logger.SystemLogger.Info().Int("rotated", rotated).Uint64("last_id", afterID).Msg("Consumer key rotation progress")
+	}
+	return rotated, nil
+}
@@ -0,0 +1,68 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository/mock"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestKeyRotationService_RotateConsumers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConsumerRepo := mock.NewMockConsumerRepository(ctrl)
+	service := services.NewKeyRotationService(mockConsumerRepo, "2026-01")
+
+	t.Run("InBatches", func(t *testing.T) {
+		gomock.InOrder(
+			mockConsumerRepo.EXPECT().FindForKeyRotation("2026-01", uint64(0), 2).Return([]entity.Consumer{{ID: 1}, {ID: 4}}, nil),
+			mockConsumerRepo.EXPECT().FindForKeyRotation("2026-01", uint64(4), 2).Return([]entity.Consumer{{ID: 7}}, nil),
+		)
+		mockConsumerRepo.EXPECT().Update(gomock.Any()).Return(nil).Times(3)
+
+		rotated, err := service.RotateConsumers(false, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, rotated)
+	})
+
+	t.Run("All", func(t *testing.T) {
+		mockConsumerRepo.EXPECT().FindForKeyRotation("", uint64(0), 100).Return(nil, nil)
+
+		rotated, err := service.RotateConsumers(true, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, rotated)
+	})
+
+	t.Run("UpdateFails", func(t *testing.T) {
+		mockConsumerRepo.EXPECT().FindForKeyRotation("2026-01", uint64(0), 100).Return([]entity.Consumer{{ID: 1}, {ID: 2}}, nil)
+		mockConsumerRepo.EXPECT().Update(gomock.Any()).Return(nil)
+		mockConsumerRepo.EXPECT().Update(gomock.Any()).Return(errors.New("deadlock"))
+
+		rotated, err := service.RotateConsumers(false, 100)
+		assert.ErrorContains(t, err, "rotate consumer 2")
+		assert.Equal(t, 1, rotated)
+	})
+}
+
+func TestKeyRotationService_BackfillNIKHashes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConsumerRepo := mock.NewMockConsumerRepository(ctrl)
+	service := services.NewKeyRotationService(mockConsumerRepo, "2026-01")
+
+	gomock.InOrder(
+		mockConsumerRepo.EXPECT().FindWithoutNIKHash(uint64(0), 2).Return([]entity.Consumer{{ID: 1}, {ID: 3}}, nil),
+		mockConsumerRepo.EXPECT().FindWithoutNIKHash(uint64(3), 2).Return(nil, nil),
+	)
+	mockConsumerRepo.EXPECT().Update(gomock.Any()).Return(nil).Times(2)
+
+	backfilled, err := service.BackfillNIKHashes(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, backfilled)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
+var ErrLimitNotFound = errors.New("limit not found for the requested tenor")
+
+// LimitCalculator computes ceiling, used, reserved and available amounts per tenor.
+// It is the single place limit usage is derived from transactions, shared by the limit
+// endpoints and the transaction service so the numbers never diverge.
+type LimitCalculator struct {
+	limitRepo       repository.LimitRepository
+	transactionRepo repository.TransactionRepository
+}
+
+// NewLimitCalculator creates a calculator over the given repositories. Pass repositories bound
+// with WithTx to calculate inside a DB transaction.
+func NewLimitCalculator(limitRepo repository.LimitRepository, transactionRepo repository.TransactionRepository) *LimitCalculator {
+	return &LimitCalculator{
+		limitRepo:       limitRepo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+// Calculate returns the limit usage for every tenor of a user
+func (c *LimitCalculator) Calculate(userID uint) ([]dto.LimitResponse, error) {
+	limits, err := c.limitRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	usages, err := c.transactionRepo.GetLimitUsage([]uint{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return SummarizeLimits(userID, limits, usages), nil
+}
+
+// CalculateTenor returns the limit usage of a single tenor, or ErrLimitNotFound
+func (c *LimitCalculator) CalculateTenor(userID uint, tenor int) (*dto.LimitResponse, error) {
+	responses, err := c.Calculate(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range responses {
+		if responses[i].TenorMonth == tenor {
+			return &responses[i], nil
+		}
+	}
+	return nil, ErrLimitNotFound
+}
+
+// CalculateUsers returns the limit usage of several users at once; users must have TenorLimit loaded
+func (c *LimitCalculator) CalculateUsers(users []entity.User) ([]dto.LimitResponse, error) {
+	userIDs := make([]uint, 0, len(users))
+	for _, u := range users {
+		userIDs = append(userIDs, u.ID)
+	}
+
+	usages, err := c.transactionRepo.GetLimitUsage(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	usagesByUser := make(map[uint][]repository.LimitUsage, len(users))
+	for _, u := range usages {
+		usagesByUser[u.UserID] = append(usagesByUser[u.UserID], u)
+	}
+
+	// Pre-allocate with estimated capacity (avg 4 tenors per user)
+	responses := make([]dto.LimitResponse, 0, len(users)*4)
+	for _, u := range users {
+		responses = append(responses, SummarizeLimits(u.ID, u.TenorLimit, usagesByUser[u.ID])...)
+	}
+	return responses, nil
+}
+
+// SummarizeLimits combines a user's tenor limits with their usage. Pending transactions are
+// reserved; approved and active ones are used.
+func SummarizeLimits(userID uint, limits []entity.TenorLimit, usages []repository.LimitUsage) []dto.LimitResponse {
+	responses := make([]dto.LimitResponse, 0, len(limits))
+	for _, l := range limits {
+		response := dto.LimitResponse{
+			LimitID:     l.ID,
+			UserID:      userID,
+			TenorMonth:  int(l.TenorMonth),
+			LimitAmount: l.LimitAmount,
+		}
+
+		for _, u := range usages {
+			if u.Tenor != response.TenorMonth {
+				continue
+			}
+			if u.Status == entity.TransactionPending {
+				response.ReservedAmount += u.Amount
+			} else {
+				response.UsedAmount += u.Amount
+			}
+		}
+
+		response.AvailableAmount = money.Max(response.LimitAmount-response.UsedAmount-response.ReservedAmount, 0)
+
+		responses = append(responses, response)
+	}
+	return responses
+}
@@ -16,20 +16,24 @@ type LimitService interface {
 	CreateLimit(req dto.CreateLimitRequest) error
 	UpdateLimit(id uint, req dto.UpdateLimitRequest) error
 	DeleteLimit(id uint) error
+	GetMutationsPaginated(userId uint, query dto.LimitMutationQuery) ([]entity.LimitMutation, int64, error)
+	GetMutationsByCursor(userId uint, query dto.LimitMutationQuery) ([]entity.LimitMutation, dto.PaginationMeta, error)
 }
 
 type limitService struct {
 	limitRepo    repository.LimitRepository
 	userRepo     repository.UserRepository
 	mutationRepo repository.LimitMutationRepository
+	calculator   *LimitCalculator
 	db           *gorm.DB
 }
 
-func NewLimitService(limitRepo repository.LimitRepository, userRepo repository.UserRepository, mutationRepo repository.LimitMutationRepository, db *gorm.DB) LimitService {
+func NewLimitService(limitRepo repository.LimitRepository, userRepo repository.UserRepository, mutationRepo repository.LimitMutationRepository, transactionRepo repository.TransactionRepository, db *gorm.DB) LimitService {
 	return &limitService{
 		limitRepo:    limitRepo,
 		userRepo:     userRepo,
 		mutationRepo: mutationRepo,
+		calculator:   NewLimitCalculator(limitRepo, transactionRepo),
 		db:           db,
 	}
 }
@@ -41,44 +45,17 @@ func (s *limitService) GetLimits(userId uint) ([]dto.LimitResponse, error) {
 		return nil, err
 	}
 
-	var responses []dto.LimitResponse
-
 	// 2. Admin: Find All Limits (via Users)
 	if user.Role.Name == "admin" {
 		users, err := s.userRepo.FindAllWithLimits()
 		if err != nil {
 			return nil, err
 		}
-
-		for _, u := range users {
-			for _, l := range u.TenorLimit {
-				responses = append(responses, dto.LimitResponse{
-					UserID:      u.ID,
-					TenorMonth:  int(l.TenorMonth),
-					LimitAmount: l.LimitAmount,
-				})
-			}
-		}
-		return responses, nil
+		return s.calculator.CalculateUsers(users)
 	}
 
 	// User: Find Own Limits (no pagination needed, max 4 tenors)
-	limits, err := s.limitRepo.FindByUserID(userId)
-	if err != nil {
-		return nil, err
-	}
-
-	// Pre-allocate with known length
-	responses = make([]dto.LimitResponse, 0, len(limits))
-	for _, l := range limits {
-		responses = append(responses, dto.LimitResponse{
-			UserID:      userId,
-			TenorMonth:  int(l.TenorMonth),
-			LimitAmount: l.LimitAmount,
-		})
-	}
-
-	return responses, nil
+	return s.calculator.Calculate(userId)
 }
 
 func (s *limitService) GetLimitsPaginated(userId uint, page, limit int) ([]dto.LimitResponse, int64, error) {
@@ -88,8 +65,6 @@ func (s *limitService) GetLimitsPaginated(userId uint, page, limit int) ([]dto.L
 		return nil, 0, err
 	}
 
-	var responses []dto.LimitResponse
-	var total int64
 	offset := (page - 1) * limit
 
 	// Admin: Find All Limits (via Users) with pagination
@@ -99,19 +74,12 @@ func (s *limitService) GetLimitsPaginated(userId uint, page, limit int) ([]dto.L
 			return nil, 0, err
 		}
 
-		// Pre-allocate with estimated capacity (avg 4 tenors per user)
-		allLimits := make([]dto.LimitResponse, 0, len(users)*4)
-		for _, u := range users {
-			for _, l := range u.TenorLimit {
-				allLimits = append(allLimits, dto.LimitResponse{
-					UserID:      u.ID,
-					TenorMonth:  int(l.TenorMonth),
-					LimitAmount: l.LimitAmount,
-				})
-			}
+		allLimits, err := s.calculator.CalculateUsers(users)
+		if err != nil {
+			return nil, 0, err
 		}
 
-		total = int64(len(allLimits))
+		total := int64(len(allLimits))
 
 		// Apply pagination in-memory (for small datasets)
 		start := offset
@@ -122,27 +90,17 @@ func (s *limitService) GetLimitsPaginated(userId uint, page, limit int) ([]dto.L
 		if end > len(allLimits) {
 			end = len(allLimits)
 		}
-		responses = allLimits[start:end]
 
-		return responses, total, nil
+		return allLimits[start:end], total, nil
 	}
 
 	// User: Find Own Limits (no pagination needed, max 4 tenors)
-	limits, err := s.limitRepo.FindByUserID(userId)
+	responses, err := s.calculator.Calculate(userId)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	for _, l := range limits {
-		responses = append(responses, dto.LimitResponse{
-			UserID:      userId,
-			TenorMonth:  int(l.TenorMonth),
-			LimitAmount: l.LimitAmount,
-		})
-	}
-
-	total = int64(len(responses))
-	return responses, total, nil
+	return responses, int64(len(responses)), nil
 }
 
 func (s *limitService) CreateLimit(req dto.CreateLimitRequest) error {
@@ -214,7 +172,7 @@ func (s *limitService) CreateLimit(req dto.CreateLimitRequest) error {
 		logger.AuditLogger.Info().
 			Uint("user_id", req.TargetUserID).
 			Uint("limit_id", uint(limit.ID)).
-			Float64("limit_amount", req.LimitAmount).
+			Str("limit_amount", req.LimitAmount.String()).
 			Msg("Limit Created")
 
 		return nil
@@ -268,8 +226,8 @@ func (s *limitService) UpdateLimit(id uint, req dto.UpdateLimitRequest) error {
 		logger.AuditLogger.Info().
 			Uint("user_id", userID).
 			Uint("limit_id", uint(limit.ID)).
-			Float64("old_amount", oldAmount).
-			Float64("new_amount", req.LimitAmount).
+			Str("old_amount", oldAmount.String()).
+			Str("new_amount", req.LimitAmount.String()).
 			Msg("Limit Updated")
 
 		return nil
@@ -317,3 +275,55 @@ func (s *limitService) DeleteLimit(id uint) error {
 		return nil
 	})
 }
+
+func (s *limitService) GetMutationsPaginated(userId uint, query dto.LimitMutationQuery) ([]entity.LimitMutation, int64, error) {
+	filter, err := s.mutationFilter(userId, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.mutationRepo.FindPaginated(filter, query.GetOffset(), query.Limit)
+}
+
+func (s *limitService) GetMutationsByCursor(userId uint, query dto.LimitMutationQuery) ([]entity.LimitMutation, dto.PaginationMeta, error) {
+	filter, err := s.mutationFilter(userId, query)
+	if err != nil {
+		return nil, dto.PaginationMeta{}, err
+	}
+
+	cursor, err := decodeCursor(query.Cursor, repository.MutationSort)
+	if err != nil {
+		return nil, dto.PaginationMeta{}, err
+	}
+
+	mutations, hasMore, err := s.mutationRepo.FindByCursor(filter, cursor, query.Limit)
+	if err != nil {
+		return nil, dto.PaginationMeta{}, err
+	}
+
+	return mutations, cursorMeta(mutations, cursor, hasMore, query.Limit, repository.MutationKeyset), nil
+}
+
+func (s *limitService) mutationFilter(userId uint, query dto.LimitMutationQuery) (repository.LimitMutationFilter, error) {
+	user, err := s.userRepo.FindByID(userId)
+	if err != nil {
+		return repository.LimitMutationFilter{}, err
+	}
+
+	filter := repository.LimitMutationFilter{
+		UserID:       query.UserID,
+		Action:       entity.MutationAction(query.Action),
+		TenorLimitID: query.TenorLimitID,
+		From:         query.StartDate,
+	}
+	if !query.EndDate.IsZero() {
+		filter.To = query.EndDate.AddDate(0, 0, 1)
+	}
+
+	// Non-admin users may only see their own history
+	if user.Role.Name != "admin" {
+		filter.UserID = userId
+	}
+
+	return filter, nil
+}
@@ -2,12 +2,15 @@ package services_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/hadi-projects/xyz-finance-go/internal/dto"
 	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
 	"github.com/hadi-projects/xyz-finance-go/internal/repository/mock"
 	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"gorm.io/driver/mysql"
@@ -35,14 +38,15 @@ func TestLimitService_CreateLimit(t *testing.T) {
 	mockLimitRepo := mock.NewMockLimitRepository(ctrl)
 	mockUserRepo := mock.NewMockUserRepository(ctrl)
 	mockMutationRepo := mock.NewMockLimitMutationRepository(ctrl)
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
 
-	service := services.NewLimitService(mockLimitRepo, mockUserRepo, mockMutationRepo, gormDB)
+	service := services.NewLimitService(mockLimitRepo, mockUserRepo, mockMutationRepo, mockTxRepo, gormDB)
 
 	t.Run("Success", func(t *testing.T) {
 		req := dto.CreateLimitRequest{
 			TargetUserID: 1,
 			TenorMonth:   1,
-			LimitAmount:  1000000,
+			LimitAmount:  money.FromRupiah(1000000),
 		}
 
 		mockUserRepo.EXPECT().FindByID(req.TargetUserID).Return(&entity.User{ID: 1}, nil)
@@ -70,8 +74,8 @@ func TestLimitService_CreateLimit(t *testing.T) {
 			assert.Equal(t, uint(1), m.UserID)
 			assert.Equal(t, uint(123), m.TenorLimitID)
 			assert.Equal(t, entity.MutationCreate, m.Action)
-			assert.Equal(t, 0.0, m.OldAmount)
-			assert.Equal(t, 1000000.0, m.NewAmount)
+			assert.Equal(t, money.FromRupiah(0), m.OldAmount)
+			assert.Equal(t, money.FromRupiah(1000000), m.NewAmount)
 		}).Return(nil)
 
 		sqlMock.ExpectCommit()
@@ -85,10 +89,10 @@ func TestLimitService_CreateLimit(t *testing.T) {
 	})
 
 	t.Run("DuplicateLimit", func(t *testing.T) {
-		req := dto.CreateLimitRequest{TargetUserID: 1, TenorMonth: 1, LimitAmount: 100}
+		req := dto.CreateLimitRequest{TargetUserID: 1, TenorMonth: 1, LimitAmount: money.FromRupiah(100)}
 		mockUserRepo.EXPECT().FindByID(req.TargetUserID).Return(&entity.User{ID: 1}, nil)
 		mockLimitRepo.EXPECT().FindByUserID(req.TargetUserID).Return([]entity.TenorLimit{
-			{TenorMonth: 1, LimitAmount: 50000},
+			{TenorMonth: 1, LimitAmount: money.FromRupiah(50000)},
 		}, nil)
 
 		err := service.CreateLimit(req)
@@ -118,20 +122,21 @@ func TestLimitService_UpdateLimit(t *testing.T) {
 	mockLimitRepo := mock.NewMockLimitRepository(ctrl)
 	mockUserRepo := mock.NewMockUserRepository(ctrl)
 	mockMutationRepo := mock.NewMockLimitMutationRepository(ctrl)
-	service := services.NewLimitService(mockLimitRepo, mockUserRepo, mockMutationRepo, gormDB)
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
+	service := services.NewLimitService(mockLimitRepo, mockUserRepo, mockMutationRepo, mockTxRepo, gormDB)
 
 	t.Run("Success", func(t *testing.T) {
 		limitID := uint(1)
 		req := dto.UpdateLimitRequest{
 			TenorMonth:  1,
-			LimitAmount: 200000,
+			LimitAmount: money.FromRupiah(200000),
 		}
 
 		sqlMock.ExpectBegin()
 		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
 		mockMutationRepo.EXPECT().WithTx(gomock.Any()).Return(mockMutationRepo)
 
-		mockLimitRepo.EXPECT().FindByID(limitID).Return(&entity.TenorLimit{ID: 1, TenorMonth: 2, LimitAmount: 100000}, nil)
+		mockLimitRepo.EXPECT().FindByID(limitID).Return(&entity.TenorLimit{ID: 1, TenorMonth: 2, LimitAmount: money.FromRupiah(100000)}, nil)
 		mockLimitRepo.EXPECT().GetUserIDByLimitID(limitID).Return(uint(101), nil)
 
 		mockLimitRepo.EXPECT().Update(gomock.Any()).Return(nil)
@@ -140,8 +145,8 @@ func TestLimitService_UpdateLimit(t *testing.T) {
 			assert.Equal(t, uint(101), m.UserID)
 			assert.Equal(t, uint(1), m.TenorLimitID)
 			assert.Equal(t, entity.MutationUpdate, m.Action)
-			assert.Equal(t, 100000.0, m.OldAmount)
-			assert.Equal(t, 200000.0, m.NewAmount)
+			assert.Equal(t, money.FromRupiah(100000), m.OldAmount)
+			assert.Equal(t, money.FromRupiah(200000), m.NewAmount)
 		}).Return(nil)
 
 		sqlMock.ExpectCommit()
@@ -172,7 +177,8 @@ func TestLimitService_DeleteLimit(t *testing.T) {
 	mockLimitRepo := mock.NewMockLimitRepository(ctrl)
 	mockUserRepo := mock.NewMockUserRepository(ctrl)
 	mockMutationRepo := mock.NewMockLimitMutationRepository(ctrl)
-	service := services.NewLimitService(mockLimitRepo, mockUserRepo, mockMutationRepo, gormDB)
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
+	service := services.NewLimitService(mockLimitRepo, mockUserRepo, mockMutationRepo, mockTxRepo, gormDB)
 
 	t.Run("Success", func(t *testing.T) {
 		limitID := uint(10)
@@ -181,15 +187,15 @@ func TestLimitService_DeleteLimit(t *testing.T) {
 		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
 		mockMutationRepo.EXPECT().WithTx(gomock.Any()).Return(mockMutationRepo)
 
-		mockLimitRepo.EXPECT().FindByID(limitID).Return(&entity.TenorLimit{ID: 10, LimitAmount: 50000}, nil)
+		mockLimitRepo.EXPECT().FindByID(limitID).Return(&entity.TenorLimit{ID: 10, LimitAmount: money.FromRupiah(50000)}, nil)
 		mockLimitRepo.EXPECT().GetUserIDByLimitID(limitID).Return(uint(101), nil)
 		mockLimitRepo.EXPECT().Delete(limitID).Return(nil)
 
 		mockMutationRepo.EXPECT().Create(gomock.Any()).Do(func(m *entity.LimitMutation) {
 			assert.Equal(t, uint(101), m.UserID)
 			assert.Equal(t, entity.MutationDelete, m.Action)
-			assert.Equal(t, 50000.0, m.OldAmount)
-			assert.Equal(t, 0.0, m.NewAmount)
+			assert.Equal(t, money.FromRupiah(50000), m.OldAmount)
+			assert.Equal(t, money.FromRupiah(0), m.NewAmount)
 		}).Return(nil)
 
 		sqlMock.ExpectCommit()
@@ -217,7 +223,8 @@ func TestLimitService_GetLimits(t *testing.T) {
 	mockLimitRepo := mock.NewMockLimitRepository(ctrl)
 	mockUserRepo := mock.NewMockUserRepository(ctrl)
 	mockMutationRepo := mock.NewMockLimitMutationRepository(ctrl)
-	service := services.NewLimitService(mockLimitRepo, mockUserRepo, mockMutationRepo, gormDB)
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
+	service := services.NewLimitService(mockLimitRepo, mockUserRepo, mockMutationRepo, mockTxRepo, gormDB)
 
 	t.Run("Admin_Success", func(t *testing.T) {
 		userID := uint(1)
@@ -226,13 +233,15 @@ func TestLimitService_GetLimits(t *testing.T) {
 
 		mockUserRepo.EXPECT().FindByID(userID).Return(user, nil)
 		mockUserRepo.EXPECT().FindAllWithLimits().Return([]entity.User{
-			{ID: 2, TenorLimit: []entity.TenorLimit{{TenorMonth: 1, LimitAmount: 100}}},
+			{ID: 2, TenorLimit: []entity.TenorLimit{{TenorMonth: 1, LimitAmount: money.FromRupiah(100)}}},
 		}, nil)
+		mockTxRepo.EXPECT().GetLimitUsage([]uint{2}).Return(nil, nil)
 
 		limits, err := service.GetLimits(userID)
 		assert.NoError(t, err)
 		assert.Len(t, limits, 1)
 		assert.Equal(t, uint(2), limits[0].UserID)
+		assert.Equal(t, money.FromRupiah(100), limits[0].AvailableAmount)
 	})
 
 	t.Run("User_Success", func(t *testing.T) {
@@ -242,12 +251,87 @@ func TestLimitService_GetLimits(t *testing.T) {
 
 		mockUserRepo.EXPECT().FindByID(userID).Return(user, nil)
 		mockLimitRepo.EXPECT().FindByUserID(userID).Return([]entity.TenorLimit{
-			{TenorMonth: 1, LimitAmount: 100},
+			{TenorMonth: 1, LimitAmount: money.FromRupiah(100)},
 		}, nil)
+		mockTxRepo.EXPECT().GetLimitUsage([]uint{userID}).Return(nil, nil)
 
 		limits, err := service.GetLimits(userID)
 		assert.NoError(t, err)
 		assert.Len(t, limits, 1)
 		assert.Equal(t, userID, limits[0].UserID)
 	})
+
+	t.Run("User_UsedReservedAvailable", func(t *testing.T) {
+		userID := uint(3)
+		user := &entity.User{ID: userID, Role: entity.Role{Name: "user"}}
+
+		mockUserRepo.EXPECT().FindByID(userID).Return(user, nil)
+		mockLimitRepo.EXPECT().FindByUserID(userID).Return([]entity.TenorLimit{
+			{ID: 1, TenorMonth: 1, LimitAmount: money.FromRupiah(100000)},
+			{ID: 2, TenorMonth: 3, LimitAmount: money.FromRupiah(500000)},
+		}, nil)
+		mockTxRepo.EXPECT().GetLimitUsage([]uint{userID}).Return([]repository.LimitUsage{
+			{UserID: userID, Tenor: 3, Status: entity.TransactionActive, Amount: money.FromRupiah(200000)},
+			{UserID: userID, Tenor: 3, Status: entity.TransactionPending, Amount: money.FromRupiah(50000)},
+			{UserID: userID, Tenor: 1, Status: entity.TransactionApproved, Amount: money.FromRupiah(150000)},
+		}, nil)
+
+		limits, err := service.GetLimits(userID)
+		assert.NoError(t, err)
+		assert.Len(t, limits, 2)
+
+		assert.Equal(t, money.FromRupiah(150000), limits[0].UsedAmount)
+		assert.Equal(t, money.FromRupiah(0), limits[0].AvailableAmount) // over-used after a limit decrease
+
+		assert.Equal(t, uint64(2), limits[1].LimitID)
+		assert.Equal(t, money.FromRupiah(200000), limits[1].UsedAmount)
+		assert.Equal(t, money.FromRupiah(50000), limits[1].ReservedAmount)
+		assert.Equal(t, money.FromRupiah(250000), limits[1].AvailableAmount)
+	})
+}
+
+func TestLimitService_GetMutationsPaginated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLimitRepo := mock.NewMockLimitRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockMutationRepo := mock.NewMockLimitMutationRepository(ctrl)
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
+	service := services.NewLimitService(mockLimitRepo, mockUserRepo, mockMutationRepo, mockTxRepo, nil)
+
+	query := dto.LimitMutationQuery{
+		PaginationRequest: dto.PaginationRequest{Page: 1, Limit: 20},
+		UserID:            3,
+		Action:            "USAGE",
+		EndDate:           time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("User_OwnHistoryOnly", func(t *testing.T) {
+		userID := uint(2)
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockMutationRepo.EXPECT().FindPaginated(repository.LimitMutationFilter{
+			UserID: userID,
+			Action: entity.MutationUsage,
+			To:     time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		}, 0, 20).Return([]entity.LimitMutation{}, int64(0), nil)
+
+		_, _, err := service.GetMutationsPaginated(userID, query)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Admin_AnyUser", func(t *testing.T) {
+		adminID := uint(1)
+		mockUserRepo.EXPECT().FindByID(adminID).Return(&entity.User{ID: adminID, Role: entity.Role{Name: "admin"}}, nil)
+		mockMutationRepo.EXPECT().FindPaginated(repository.LimitMutationFilter{
+			UserID: 3,
+			Action: entity.MutationUsage,
+			To:     time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		}, 0, 20).Return([]entity.LimitMutation{{ID: 1, UserID: 3}}, int64(1), nil)
+
+		mutations, total, err := service.GetMutationsPaginated(adminID, query)
+		assert.NoError(t, err)
+		assert.Len(t, mutations, 1)
+		assert.Equal(t, int64(1), total)
+	})
 }
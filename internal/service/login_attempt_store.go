@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	loginFailuresPrefix = "login:failures:"
+	loginLockPrefix     = "login:locked:"
+)
+
+// LoginAttemptStore counts failed logins and keeps lockouts, per key (an account or an IP)
+type LoginAttemptStore interface {
+	// AddFailure counts a failed attempt and returns the failures in the current window, which
+	// starts with the first failure and lasts window
+	AddFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	// Failures returns the failures in the current window
+	Failures(ctx context.Context, key string) (int, error)
+	// Lock locks the key until the given time and clears its failures
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil returns when the key's lockout ends, or the zero time when it is not locked
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Reset clears the key's failures and lockout
+	Reset(ctx context.Context, key string) error
+}
+
+type redisLoginAttemptStore struct {
+	redis *cache.RedisClient
+}
+
+// NewRedisLoginAttemptStore keeps login failures and lockouts in Redis, shared by all instances
+func NewRedisLoginAttemptStore(redisClient *cache.RedisClient) LoginAttemptStore {
+	return &redisLoginAttemptStore{redis: redisClient}
+}
+
+func (s *redisLoginAttemptStore) AddFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	count, err := s.redis.IncrWithTTL(ctx, loginFailuresPrefix+key, window)
+	return int(count), err
+}
+
+func (s *redisLoginAttemptStore) Failures(ctx context.Context, key string) (int, error) {
+	value, err := s.redis.Get(ctx, loginFailuresPrefix+key)
+	if err != nil {
+		if err == redis.Nil {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+func (s *redisLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	if err := s.redis.Set(ctx, loginLockPrefix+key, strconv.FormatInt(until.Unix(), 10), time.Until(until)); err != nil {
+		return err
+	}
+	return s.redis.Delete(ctx, loginFailuresPrefix+key)
+}
+
+func (s *redisLoginAttemptStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	value, err := s.redis.Get(ctx, loginLockPrefix+key)
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(unix, 0), nil
+}
+
+func (s *redisLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	if err := s.redis.Delete(ctx, loginFailuresPrefix+key); err != nil {
+		return err
+	}
+	return s.redis.Delete(ctx, loginLockPrefix+key)
+}
+
+type loginFailures struct {
+	count     int
+	expiresAt time.Time
+}
+
+type memoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	failures map[string]loginFailures
+	locks    map[string]time.Time
+}
+
+// NewMemoryLoginAttemptStore keeps login failures and lockouts in process memory. Used when Redis
+// is not available; counters are per instance and lost on restart.
+func NewMemoryLoginAttemptStore() LoginAttemptStore {
+	return &memoryLoginAttemptStore{
+		failures: make(map[string]loginFailures),
+		locks:    make(map[string]time.Time),
+	}
+}
+
+func (s *memoryLoginAttemptStore) AddFailure(_ context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.prune(now)
+	entry, ok := s.failures[key]
+	if !ok {
+		entry = loginFailures{expiresAt: now.Add(window)}
+	}
+	entry.count++
+	s.failures[key] = entry
+	return entry.count, nil
+}
+
+func (s *memoryLoginAttemptStore) Failures(_ context.Context, key string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.failures[key]
+	if !ok || !entry.expiresAt.After(time.Now()) {
+		return 0, nil
+	}
+	return entry.count, nil
+}
+
+func (s *memoryLoginAttemptStore) Lock(_ context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locks[key] = until
+	delete(s.failures, key)
+	return nil
+}
+
+func (s *memoryLoginAttemptStore) LockedUntil(_ context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.locks[key]
+	if !ok || !until.After(time.Now()) {
+		return time.Time{}, nil
+	}
+	return until, nil
+}
+
+func (s *memoryLoginAttemptStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	delete(s.locks, key)
+	return nil
+}
+
+// prune drops expired counters and lockouts; callers hold mu
+func (s *memoryLoginAttemptStore) prune(now time.Time) {
+	for key, entry := range s.failures {
+		if !entry.expiresAt.After(now) {
+			delete(s.failures, key)
+		}
+	}
+	for key, until := range s.locks {
+		if !until.After(now) {
+			delete(s.locks, key)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+)
+
+var ErrLoginLocked = errors.New("too many failed login attempts, try again later")
+
+// Lockout scopes
+const (
+	LockoutAccount = "account"
+	LockoutIP      = "ip"
+)
+
+// LoginLockedError is returned for attempts on a locked account or from a locked IP
+type LoginLockedError struct {
+	Scope string // LockoutAccount or LockoutIP
+	Until time.Time
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s locked until %s", e.Scope, e.Until.Format(time.RFC3339))
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrLoginLocked
+}
+
+// LoginFailure is the state after a failed login was recorded
+type LoginFailure struct {
+	AccountFailures int
+	IPFailures      int
+	// Lockouts caused by this failure, if any
+	Lockouts []LoginLockedError
+}
+
+// LoginGuard protects logins against password guessing: it counts failures per account and per
+// IP, delays attempts on accounts with recent failures and locks accounts and IPs that reach
+// their limit. Accounts are keyed by the submitted email whether or not it exists, so lockouts
+// do not reveal which emails are registered.
+//
+// When the store cannot be reached logins are let through (logged), since the password is still
+// checked; throttling is best effort, availability of login is not.
+type LoginGuard struct {
+	store              LoginAttemptStore
+	maxAccountFailures int
+	maxIPFailures      int
+	window             time.Duration
+	lockout            time.Duration
+	delayBase          time.Duration
+	delayMax           time.Duration
+	sleep              func(ctx context.Context, d time.Duration) error
+}
+
+func NewLoginGuard(store LoginAttemptStore, cfg config.LoginProtectionConfig) *LoginGuard {
+	return &LoginGuard{
+		store:              store,
+		maxAccountFailures: cfg.MaxAccountFailures,
+		maxIPFailures:      cfg.MaxIPFailures,
+		window:             time.Duration(cfg.FailureWindowMinutes) * time.Minute,
+		lockout:            time.Duration(cfg.LockoutMinutes) * time.Minute,
+		delayBase:          time.Duration(cfg.DelayBaseMillis) * time.Millisecond,
+		delayMax:           time.Duration(cfg.DelayMaxMillis) * time.Millisecond,
+		sleep:              sleepContext,
+	}
+}
+
+// Check is called before the password is verified. It returns a *LoginLockedError when the account
+// or IP is locked, and otherwise waits the progressive delay for the account's recent failures.
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	for _, target := range []struct{ scope, key string }{
+		{LockoutAccount, accountKey(email)},
+		{LockoutIP, ipKey(ip)},
+	} {
+		until, err := g.store.LockedUntil(ctx, target.key)
+		if err != nil {
+			logger.SystemLogger.Error().Err(err).Msg("Failed to read login lockout")
+			continue
+		}
+		if !until.IsZero() {
+			return &LoginLockedError{Scope: target.scope, Until: until}
+		}
+	}
+
+	failures, err := g.store.Failures(ctx, accountKey(email))
+	if err != nil {
+		logger.SystemLogger.Error().Err(err).Msg("Failed to read login failures")
+		return nil
+	}
+	return g.sleep(ctx, g.Delay(failures))
+}
+
+// Delay is how long an attempt waits after the given number of recent failures: nothing for the
+// first attempt, then the base delay doubling with every failure, capped at the maximum
+func (g *LoginGuard) Delay(failures int) time.Duration {
+	if failures <= 0 || g.delayBase <= 0 {
+		return 0
+	}
+	delay := g.delayBase
+	for i := 1; i < failures && delay < g.delayMax; i++ {
+		delay *= 2
+	}
+	return min(delay, g.delayMax)
+}
+
+// RecordFailure counts a failed login for the account and the IP and locks whichever reached its
+// limit
+func (g *LoginGuard) RecordFailure(ctx context.Context, email, ip string) (LoginFailure, error) {
+	var result LoginFailure
+	var err error
+
+	result.AccountFailures, err = g.addFailure(ctx, LockoutAccount, accountKey(email), g.maxAccountFailures, &result)
+	if err != nil {
+		return result, err
+	}
+	result.IPFailures, err = g.addFailure(ctx, LockoutIP, ipKey(ip), g.maxIPFailures, &result)
+	return result, err
+}
+
+func (g *LoginGuard) addFailure(ctx context.Context, scope, key string, limit int, result *LoginFailure) (int, error) {
+	failures, err := g.store.AddFailure(ctx, key, g.window)
+	if err != nil {
+		return 0, err
+	}
+	if failures < limit {
+		return failures, nil
+	}
+
+	until := time.Now().Add(g.lockout)
+	if err := g.store.Lock(ctx, key, until); err != nil {
+		return failures, err
+	}
+	result.Lockouts = append(result.Lockouts, LoginLockedError{Scope: scope, Until: until})
+	return failures, nil
+}
+
+// RecordSuccess clears the account's failures. IP failures are kept: one correct password
+// from an IP says nothing about its attempts on other accounts.
+func (g *LoginGuard) RecordSuccess(ctx context.Context, email string) error {
+	failures, err := g.store.Failures(ctx, accountKey(email))
+	if err != nil || failures == 0 {
+		return err
+	}
+	return g.store.Reset(ctx, accountKey(email))
+}
+
+// Unlock lifts an account lockout and clears its failures
+func (g *LoginGuard) Unlock(ctx context.Context, email string) error {
+	return g.store.Reset(ctx, accountKey(email))
+}
+
+func accountKey(email string) string {
+	return LockoutAccount + ":" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return LockoutIP + ":" + ip
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
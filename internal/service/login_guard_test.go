@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLoginGuard() *services.LoginGuard {
+	return services.NewLoginGuard(services.NewMemoryLoginAttemptStore(), config.LoginProtectionConfig{
+		MaxAccountFailures:   3,
+		MaxIPFailures:        5,
+		FailureWindowMinutes: 15,
+		LockoutMinutes:       15,
+	})
+}
+
+func TestLoginGuard_Delay(t *testing.T) {
+	guard := services.NewLoginGuard(services.NewMemoryLoginAttemptStore(), config.LoginProtectionConfig{
+		DelayBaseMillis: 250,
+		DelayMaxMillis:  1500,
+	})
+
+	assert.Equal(t, time.Duration(0), guard.Delay(0))
+	assert.Equal(t, 250*time.Millisecond, guard.Delay(1))
+	assert.Equal(t, 500*time.Millisecond, guard.Delay(2))
+	assert.Equal(t, 1000*time.Millisecond, guard.Delay(3))
+	assert.Equal(t, 1500*time.Millisecond, guard.Delay(4), "capped at the maximum")
+	assert.Equal(t, 1500*time.Millisecond, guard.Delay(60))
+}
+
+func TestLoginGuard_AccountLockout(t *testing.T) {
+	ctx := context.Background()
+	guard := newTestLoginGuard()
+
+	for i := 1; i < 3; i++ {
+		failure, err := guard.RecordFailure(ctx, "budi@mail.com", "10.0.0.1")
+		assert.NoError(t, err)
+		assert.Equal(t, i, failure.AccountFailures)
+		assert.Empty(t, failure.Lockouts)
+	}
+	assert.NoError(t, guard.Check(ctx, "budi@mail.com", "10.0.0.1"))
+
+	failure, err := guard.RecordFailure(ctx, " Budi@Mail.com ", "10.0.0.2")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, failure.AccountFailures)
+	if assert.Len(t, failure.Lockouts, 1) {
+		assert.Equal(t, services.LockoutAccount, failure.Lockouts[0].Scope)
+	}
+
+	err = guard.Check(ctx, "budi@mail.com", "10.0.0.3")
+	var locked *services.LoginLockedError
+	if assert.ErrorAs(t, err, &locked) {
+		assert.Equal(t, services.LockoutAccount, locked.Scope)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), locked.Until, time.Minute)
+	}
+	assert.ErrorIs(t, err, services.ErrLoginLocked)
+	assert.NoError(t, guard.Check(ctx, "annisa@mail.com", "10.0.0.3"), "other accounts are unaffected")
+
+	assert.NoError(t, guard.Unlock(ctx, "budi@mail.com"))
+	assert.NoError(t, guard.Check(ctx, "budi@mail.com", "10.0.0.3"))
+}
+
+func TestLoginGuard_IPLockout(t *testing.T) {
+	ctx := context.Background()
+	guard := newTestLoginGuard()
+
+	// One IP trying a few passwords on many accounts stays under each account's limit
+	emails := []string{"a@mail.com", "b@mail.com", "c@mail.com", "d@mail.com", "e@mail.com"}
+	var failure services.LoginFailure
+	for _, email := range emails {
+		var err error
+		failure, err = guard.RecordFailure(ctx, email, "10.0.0.9")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 5, failure.IPFailures)
+	if assert.Len(t, failure.Lockouts, 1) {
+		assert.Equal(t, services.LockoutIP, failure.Lockouts[0].Scope)
+	}
+
+	var locked *services.LoginLockedError
+	assert.ErrorAs(t, guard.Check(ctx, "f@mail.com", "10.0.0.9"), &locked)
+	assert.Equal(t, services.LockoutIP, locked.Scope)
+	assert.NoError(t, guard.Check(ctx, "f@mail.com", "10.0.0.10"))
+}
+
+func TestLoginGuard_SuccessResetsAccount(t *testing.T) {
+	ctx := context.Background()
+	guard := newTestLoginGuard()
+
+	for i := 0; i < 2; i++ {
+		_, err := guard.RecordFailure(ctx, "budi@mail.com", "10.0.0.1")
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, guard.RecordSuccess(ctx, "budi@mail.com"))
+
+	failure, err := guard.RecordFailure(ctx, "budi@mail.com", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, failure.AccountFailures, "the account count starts over")
+	assert.Equal(t, 3, failure.IPFailures, "the IP count is kept")
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/consumer_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/consumer_service.go -destination=internal/service/mock/consumer_service_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	dto "github.com/hadi-projects/xyz-finance-go/internal/dto"
+	entity "github.com/hadi-projects/xyz-finance-go/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConsumerService is a mock of ConsumerService interface.
+type MockConsumerService struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsumerServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockConsumerServiceMockRecorder is the mock recorder for MockConsumerService.
+type MockConsumerServiceMockRecorder struct {
+	mock *MockConsumerService
+}
+
+// NewMockConsumerService creates a new mock instance.
+func NewMockConsumerService(ctrl *gomock.Controller) *MockConsumerService {
+	mock := &MockConsumerService{ctrl: ctrl}
+	mock.recorder = &MockConsumerServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsumerService) EXPECT() *MockConsumerServiceMockRecorder {
+	return m.recorder
+}
+
+// SubmitConsumer mocks base method.
+func (m *MockConsumerService) SubmitConsumer(userID uint, req dto.SubmitConsumerRequest) (*entity.Consumer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitConsumer", userID, req)
+	ret0, _ := ret[0].(*entity.Consumer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitConsumer indicates an expected call of SubmitConsumer.
+func (mr *MockConsumerServiceMockRecorder) SubmitConsumer(userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitConsumer", reflect.TypeOf((*MockConsumerService)(nil).SubmitConsumer), userID, req)
+}
+
+// UpdateKYCStatus mocks base method.
+func (m *MockConsumerService) UpdateKYCStatus(adminID, userID uint, req dto.UpdateKYCStatusRequest) (*entity.Consumer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKYCStatus", adminID, userID, req)
+	ret0, _ := ret[0].(*entity.Consumer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateKYCStatus indicates an expected call of UpdateKYCStatus.
+func (mr *MockConsumerServiceMockRecorder) UpdateKYCStatus(adminID, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKYCStatus", reflect.TypeOf((*MockConsumerService)(nil).UpdateKYCStatus), adminID, userID, req)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/document_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/document_service.go -destination=internal/service/mock/document_service_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	dto "github.com/hadi-projects/xyz-finance-go/internal/dto"
+	entity "github.com/hadi-projects/xyz-finance-go/internal/entity"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDocumentService is a mock of DocumentService interface.
+type MockDocumentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocumentServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockDocumentServiceMockRecorder is the mock recorder for MockDocumentService.
+type MockDocumentServiceMockRecorder struct {
+	mock *MockDocumentService
+}
+
+// NewMockDocumentService creates a new mock instance.
+func NewMockDocumentService(ctrl *gomock.Controller) *MockDocumentService {
+	mock := &MockDocumentService{ctrl: ctrl}
+	mock.recorder = &MockDocumentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDocumentService) EXPECT() *MockDocumentServiceMockRecorder {
+	return m.recorder
+}
+
+// Fetch mocks base method.
+func (m *MockDocumentService) Fetch(viewerID uint, kind, name string, query dto.DocumentQuery) (*services.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", viewerID, kind, name, query)
+	ret0, _ := ret[0].(*services.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockDocumentServiceMockRecorder) Fetch(viewerID, kind, name, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockDocumentService)(nil).Fetch), viewerID, kind, name, query)
+}
+
+// IssueURLs mocks base method.
+func (m *MockDocumentService) IssueURLs(viewerID, ownerID uint) (*dto.DocumentURLs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueURLs", viewerID, ownerID)
+	ret0, _ := ret[0].(*dto.DocumentURLs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueURLs indicates an expected call of IssueURLs.
+func (mr *MockDocumentServiceMockRecorder) IssueURLs(viewerID, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueURLs", reflect.TypeOf((*MockDocumentService)(nil).IssueURLs), viewerID, ownerID)
+}
+
+// SignURLs mocks base method.
+func (m *MockDocumentService) SignURLs(viewerID uint, consumer *entity.Consumer) dto.DocumentURLs {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignURLs", viewerID, consumer)
+	ret0, _ := ret[0].(dto.DocumentURLs)
+	return ret0
+}
+
+// SignURLs indicates an expected call of SignURLs.
+func (mr *MockDocumentServiceMockRecorder) SignURLs(viewerID, consumer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignURLs", reflect.TypeOf((*MockDocumentService)(nil).SignURLs), viewerID, consumer)
+}
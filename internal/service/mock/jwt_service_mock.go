@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/jwt_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/jwt_service.go -destination=internal/service/mock/jwt_service_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+	time "time"
+
+	middleware "github.com/hadi-projects/xyz-finance-go/internal/middleware"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJWTService is a mock of JWTService interface.
+type MockJWTService struct {
+	ctrl     *gomock.Controller
+	recorder *MockJWTServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockJWTServiceMockRecorder is the mock recorder for MockJWTService.
+type MockJWTServiceMockRecorder struct {
+	mock *MockJWTService
+}
+
+// NewMockJWTService creates a new mock instance.
+func NewMockJWTService(ctrl *gomock.Controller) *MockJWTService {
+	mock := &MockJWTService{ctrl: ctrl}
+	mock.recorder = &MockJWTServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJWTService) EXPECT() *MockJWTServiceMockRecorder {
+	return m.recorder
+}
+
+// GenerateRefreshToken mocks base method.
+func (m *MockJWTService) GenerateRefreshToken(userID uint) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateRefreshToken", userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateRefreshToken indicates an expected call of GenerateRefreshToken.
+func (mr *MockJWTServiceMockRecorder) GenerateRefreshToken(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateRefreshToken", reflect.TypeOf((*MockJWTService)(nil).GenerateRefreshToken), userID)
+}
+
+// GenerateToken mocks base method.
+func (m *MockJWTService) GenerateToken(userID uint, email string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateToken", userID, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateToken indicates an expected call of GenerateToken.
+func (mr *MockJWTServiceMockRecorder) GenerateToken(userID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockJWTService)(nil).GenerateToken), userID, email)
+}
+
+// RefreshAccessToken mocks base method.
+func (m *MockJWTService) RefreshAccessToken(refreshToken string) (*services.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshAccessToken", refreshToken)
+	ret0, _ := ret[0].(*services.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshAccessToken indicates an expected call of RefreshAccessToken.
+func (mr *MockJWTServiceMockRecorder) RefreshAccessToken(refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshAccessToken", reflect.TypeOf((*MockJWTService)(nil).RefreshAccessToken), refreshToken)
+}
+
+// RevokeAllSessions mocks base method.
+func (m *MockJWTService) RevokeAllSessions(userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllSessions", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllSessions indicates an expected call of RevokeAllSessions.
+func (mr *MockJWTServiceMockRecorder) RevokeAllSessions(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllSessions", reflect.TypeOf((*MockJWTService)(nil).RevokeAllSessions), userID)
+}
+
+// RevokeSession mocks base method.
+func (m *MockJWTService) RevokeSession(userID uint, refreshToken, tokenID string, tokenExpiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", userID, refreshToken, tokenID, tokenExpiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockJWTServiceMockRecorder) RevokeSession(userID, refreshToken, tokenID, tokenExpiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockJWTService)(nil).RevokeSession), userID, refreshToken, tokenID, tokenExpiresAt)
+}
+
+// ValidateToken mocks base method.
+func (m *MockJWTService) ValidateToken(tokenString string) (*middleware.JWTClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateToken", tokenString)
+	ret0, _ := ret[0].(*middleware.JWTClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateToken indicates an expected call of ValidateToken.
+func (mr *MockJWTServiceMockRecorder) ValidateToken(tokenString any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockJWTService)(nil).ValidateToken), tokenString)
+}
@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 
 	dto "github.com/hadi-projects/xyz-finance-go/internal/dto"
+	entity "github.com/hadi-projects/xyz-finance-go/internal/entity"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -83,20 +84,6 @@ func (mr *MockLimitServiceMockRecorder) GetLimits(userId any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimits", reflect.TypeOf((*MockLimitService)(nil).GetLimits), userId)
 }
 
-// UpdateLimit mocks base method.
-func (m *MockLimitService) UpdateLimit(id uint, req dto.UpdateLimitRequest) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateLimit", id, req)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// UpdateLimit indicates an expected call of UpdateLimit.
-func (mr *MockLimitServiceMockRecorder) UpdateLimit(id, req any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLimit", reflect.TypeOf((*MockLimitService)(nil).UpdateLimit), id, req)
-}
-
 // GetLimitsPaginated mocks base method.
 func (m *MockLimitService) GetLimitsPaginated(userId uint, page, limit int) ([]dto.LimitResponse, int64, error) {
 	m.ctrl.T.Helper()
@@ -112,3 +99,49 @@ func (mr *MockLimitServiceMockRecorder) GetLimitsPaginated(userId, page, limit a
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitsPaginated", reflect.TypeOf((*MockLimitService)(nil).GetLimitsPaginated), userId, page, limit)
 }
+
+// GetMutationsByCursor mocks base method.
+func (m *MockLimitService) GetMutationsByCursor(userId uint, query dto.LimitMutationQuery) ([]entity.LimitMutation, dto.PaginationMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMutationsByCursor", userId, query)
+	ret0, _ := ret[0].([]entity.LimitMutation)
+	ret1, _ := ret[1].(dto.PaginationMeta)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetMutationsByCursor indicates an expected call of GetMutationsByCursor.
+func (mr *MockLimitServiceMockRecorder) GetMutationsByCursor(userId, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMutationsByCursor", reflect.TypeOf((*MockLimitService)(nil).GetMutationsByCursor), userId, query)
+}
+
+// GetMutationsPaginated mocks base method.
+func (m *MockLimitService) GetMutationsPaginated(userId uint, query dto.LimitMutationQuery) ([]entity.LimitMutation, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMutationsPaginated", userId, query)
+	ret0, _ := ret[0].([]entity.LimitMutation)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetMutationsPaginated indicates an expected call of GetMutationsPaginated.
+func (mr *MockLimitServiceMockRecorder) GetMutationsPaginated(userId, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMutationsPaginated", reflect.TypeOf((*MockLimitService)(nil).GetMutationsPaginated), userId, query)
+}
+
+// UpdateLimit mocks base method.
+func (m *MockLimitService) UpdateLimit(id uint, req dto.UpdateLimitRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLimit", id, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLimit indicates an expected call of UpdateLimit.
+func (mr *MockLimitServiceMockRecorder) UpdateLimit(id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLimit", reflect.TypeOf((*MockLimitService)(nil).UpdateLimit), id, req)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/payment_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/payment_service.go -destination=internal/service/mock/payment_service_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	dto "github.com/hadi-projects/xyz-finance-go/internal/dto"
+	entity "github.com/hadi-projects/xyz-finance-go/internal/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPaymentService is a mock of PaymentService interface.
+type MockPaymentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPaymentServiceMockRecorder is the mock recorder for MockPaymentService.
+type MockPaymentServiceMockRecorder struct {
+	mock *MockPaymentService
+}
+
+// NewMockPaymentService creates a new mock instance.
+func NewMockPaymentService(ctrl *gomock.Controller) *MockPaymentService {
+	mock := &MockPaymentService{ctrl: ctrl}
+	mock.recorder = &MockPaymentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentService) EXPECT() *MockPaymentServiceMockRecorder {
+	return m.recorder
+}
+
+// CreatePayment mocks base method.
+func (m *MockPaymentService) CreatePayment(userID uint, transactionID uint64, req dto.CreatePaymentRequest) (*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayment", userID, transactionID, req)
+	ret0, _ := ret[0].(*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayment indicates an expected call of CreatePayment.
+func (mr *MockPaymentServiceMockRecorder) CreatePayment(userID, transactionID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockPaymentService)(nil).CreatePayment), userID, transactionID, req)
+}
+
+// GetPayments mocks base method.
+func (m *MockPaymentService) GetPayments(userID uint, transactionID uint64) ([]entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayments", userID, transactionID)
+	ret0, _ := ret[0].([]entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayments indicates an expected call of GetPayments.
+func (mr *MockPaymentServiceMockRecorder) GetPayments(userID, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayments", reflect.TypeOf((*MockPaymentService)(nil).GetPayments), userID, transactionID)
+}
+
+// GetPayoffQuote mocks base method.
+func (m *MockPaymentService) GetPayoffQuote(userID uint, transactionID uint64) (*dto.PayoffQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayoffQuote", userID, transactionID)
+	ret0, _ := ret[0].(*dto.PayoffQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayoffQuote indicates an expected call of GetPayoffQuote.
+func (mr *MockPaymentServiceMockRecorder) GetPayoffQuote(userID, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayoffQuote", reflect.TypeOf((*MockPaymentService)(nil).GetPayoffQuote), userID, transactionID)
+}
+
+// SettleTransaction mocks base method.
+func (m *MockPaymentService) SettleTransaction(userID uint, transactionID uint64, req dto.SettleTransactionRequest) (*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleTransaction", userID, transactionID, req)
+	ret0, _ := ret[0].(*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SettleTransaction indicates an expected call of SettleTransaction.
+func (mr *MockPaymentServiceMockRecorder) SettleTransaction(userID, transactionID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleTransaction", reflect.TypeOf((*MockPaymentService)(nil).SettleTransaction), userID, transactionID, req)
+}
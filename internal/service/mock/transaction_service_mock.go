@@ -41,20 +41,93 @@ func (m *MockTransactionService) EXPECT() *MockTransactionServiceMockRecorder {
 	return m.recorder
 }
 
-// CreateTransaction mocks base method.
-func (m *MockTransactionService) CreateTransaction(userId uint, req dto.CreateTransactionRequest) error {
+// ApproveTransaction mocks base method.
+func (m *MockTransactionService) ApproveTransaction(adminID uint, transactionID uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransaction", userId, req)
+	ret := m.ctrl.Call(m, "ApproveTransaction", adminID, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApproveTransaction indicates an expected call of ApproveTransaction.
+func (mr *MockTransactionServiceMockRecorder) ApproveTransaction(adminID, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveTransaction", reflect.TypeOf((*MockTransactionService)(nil).ApproveTransaction), adminID, transactionID)
+}
+
+// CancelTransaction mocks base method.
+func (m *MockTransactionService) CancelTransaction(userID uint, transactionID uint64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelTransaction", userID, transactionID, reason)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// CancelTransaction indicates an expected call of CancelTransaction.
+func (mr *MockTransactionServiceMockRecorder) CancelTransaction(userID, transactionID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTransaction", reflect.TypeOf((*MockTransactionService)(nil).CancelTransaction), userID, transactionID, reason)
+}
+
+// CreateTransaction mocks base method.
+func (m *MockTransactionService) CreateTransaction(userId uint, req dto.CreateTransactionRequest) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransaction", userId, req)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // CreateTransaction indicates an expected call of CreateTransaction.
 func (mr *MockTransactionServiceMockRecorder) CreateTransaction(userId, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransactionService)(nil).CreateTransaction), userId, req)
 }
 
+// DisburseTransaction mocks base method.
+func (m *MockTransactionService) DisburseTransaction(adminID uint, transactionID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisburseTransaction", adminID, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisburseTransaction indicates an expected call of DisburseTransaction.
+func (mr *MockTransactionServiceMockRecorder) DisburseTransaction(adminID, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisburseTransaction", reflect.TypeOf((*MockTransactionService)(nil).DisburseTransaction), adminID, transactionID)
+}
+
+// GetInstallments mocks base method.
+func (m *MockTransactionService) GetInstallments(userID uint, transactionID uint64) ([]entity.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstallments", userID, transactionID)
+	ret0, _ := ret[0].([]entity.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstallments indicates an expected call of GetInstallments.
+func (mr *MockTransactionServiceMockRecorder) GetInstallments(userID, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstallments", reflect.TypeOf((*MockTransactionService)(nil).GetInstallments), userID, transactionID)
+}
+
+// GetTransaction mocks base method.
+func (m *MockTransactionService) GetTransaction(userID uint, transactionID uint64) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransaction", userID, transactionID)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransaction indicates an expected call of GetTransaction.
+func (mr *MockTransactionServiceMockRecorder) GetTransaction(userID, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockTransactionService)(nil).GetTransaction), userID, transactionID)
+}
+
 // GetTransactions mocks base method.
 func (m *MockTransactionService) GetTransactions(userID uint) ([]entity.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -70,10 +143,26 @@ func (mr *MockTransactionServiceMockRecorder) GetTransactions(userID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockTransactionService)(nil).GetTransactions), userID)
 }
 
+// GetTransactionsByCursor mocks base method.
+func (m *MockTransactionService) GetTransactionsByCursor(userID uint, query dto.TransactionQuery) ([]entity.Transaction, dto.PaginationMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByCursor", userID, query)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(dto.PaginationMeta)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTransactionsByCursor indicates an expected call of GetTransactionsByCursor.
+func (mr *MockTransactionServiceMockRecorder) GetTransactionsByCursor(userID, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByCursor", reflect.TypeOf((*MockTransactionService)(nil).GetTransactionsByCursor), userID, query)
+}
+
 // GetTransactionsPaginated mocks base method.
-func (m *MockTransactionService) GetTransactionsPaginated(userID uint, page, limit int) ([]entity.Transaction, int64, error) {
+func (m *MockTransactionService) GetTransactionsPaginated(userID uint, query dto.TransactionQuery) ([]entity.Transaction, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionsPaginated", userID, page, limit)
+	ret := m.ctrl.Call(m, "GetTransactionsPaginated", userID, query)
 	ret0, _ := ret[0].([]entity.Transaction)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -81,7 +170,36 @@ func (m *MockTransactionService) GetTransactionsPaginated(userID uint, page, lim
 }
 
 // GetTransactionsPaginated indicates an expected call of GetTransactionsPaginated.
-func (mr *MockTransactionServiceMockRecorder) GetTransactionsPaginated(userID, page, limit any) *gomock.Call {
+func (mr *MockTransactionServiceMockRecorder) GetTransactionsPaginated(userID, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsPaginated", reflect.TypeOf((*MockTransactionService)(nil).GetTransactionsPaginated), userID, query)
+}
+
+// RejectTransaction mocks base method.
+func (m *MockTransactionService) RejectTransaction(adminID uint, transactionID uint64, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectTransaction", adminID, transactionID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectTransaction indicates an expected call of RejectTransaction.
+func (mr *MockTransactionServiceMockRecorder) RejectTransaction(adminID, transactionID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectTransaction", reflect.TypeOf((*MockTransactionService)(nil).RejectTransaction), adminID, transactionID, reason)
+}
+
+// SimulateTransaction mocks base method.
+func (m *MockTransactionService) SimulateTransaction(req dto.SimulateTransactionRequest) (*dto.TransactionQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulateTransaction", req)
+	ret0, _ := ret[0].(*dto.TransactionQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimulateTransaction indicates an expected call of SimulateTransaction.
+func (mr *MockTransactionServiceMockRecorder) SimulateTransaction(req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsPaginated", reflect.TypeOf((*MockTransactionService)(nil).GetTransactionsPaginated), userID, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateTransaction", reflect.TypeOf((*MockTransactionService)(nil).SimulateTransaction), req)
 }
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	"github.com/hadi-projects/xyz-finance-go/pkg/async"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+	"gorm.io/gorm"
+)
+
+// OverdueRunSummary reports what one run of the overdue job did
+type OverdueRunSummary struct {
+	AsOf          time.Time
+	Contracts     int // contracts processed
+	PastDue       int // contracts past due after the run
+	Penalties     int // daily late fees charged
+	PenaltyAmount money.Money
+	Failed        int
+}
+
+type OverdueService interface {
+	Run(ctx context.Context, asOf time.Time) (OverdueRunSummary, error)
+}
+
+type overdueService struct {
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+	paymentRepo     repository.PaymentRepository
+	penaltyRepo     repository.PenaltyRepository
+	cfg             config.OverdueConfig
+	db              *gorm.DB
+}
+
+func NewOverdueService(transactionRepo repository.TransactionRepository, installmentRepo repository.InstallmentRepository, paymentRepo repository.PaymentRepository, penaltyRepo repository.PenaltyRepository, cfg config.OverdueConfig, db *gorm.DB) OverdueService {
+	return &overdueService{
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+		paymentRepo:     paymentRepo,
+		penaltyRepo:     penaltyRepo,
+		cfg:             cfg,
+		db:              db,
+	}
+}
+
+// contractRun is the outcome of the overdue job for one contract
+type contractRun struct {
+	daysPastDue   int
+	penalties     int
+	penaltyAmount money.Money
+}
+
+// Run brings every active contract with past-due installments up to date as of asOf: open
+// installments due before that day are marked overdue, late fees are charged for each day past the
+// grace period not charged yet (so missed runs catch up), and the contract's days past due and DPD
+// bucket are recalculated. Contracts are processed in parallel on a worker pool, each in its own DB
+// transaction under the transaction row lock; a failing contract is logged and skipped. Running the
+// job twice for the same day, or on several instances, charges nothing twice.
+func (s *overdueService) Run(ctx context.Context, asOf time.Time) (OverdueRunSummary, error) {
+	asOf = dateOnly(asOf)
+	summary := OverdueRunSummary{AsOf: asOf}
+
+	ids, err := s.transactionRepo.FindOverdueCandidateIDs(asOf)
+	if err != nil {
+		return summary, err
+	}
+
+	pool := async.NewWorkerPool(s.cfg.JobWorkers)
+	defer pool.Stop()
+
+	var mu sync.Mutex
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+
+		pool.Submit(func() {
+			result, err := s.processContract(id, asOf)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				summary.Failed++
+				logger.SystemLogger.Error().Err(err).Uint64("transaction_id", id).Msg("Overdue job failed for contract")
+				return
+			}
+			summary.Contracts++
+			if result.daysPastDue > 0 {
+				summary.PastDue++
+			}
+			summary.Penalties += result.penalties
+			summary.PenaltyAmount += result.penaltyAmount
+		})
+	}
+	pool.Wait()
+
+	logger.SystemLogger.Info().
+		Time("as_of", asOf).
+		Int("contracts", summary.Contracts).
+		Int("past_due", summary.PastDue).
+		Int("penalties", summary.Penalties).
+		Str("penalty_amount", summary.PenaltyAmount.String()).
+		Int("failed", summary.Failed).
+		Msg("Overdue job completed")
+
+	return summary, ctx.Err()
+}
+
+func (s *overdueService) processContract(transactionID uint64, asOf time.Time) (contractRun, error) {
+	var result contractRun
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Lock Transaction Row (serializes with payments and settlement on the same contract)
+		if err := tx.Exec("SELECT id FROM transactions WHERE id = ? FOR UPDATE", transactionID).Error; err != nil {
+			return err
+		}
+
+		transactionRepoTx := s.transactionRepo.WithTx(tx)
+		installmentRepoTx := s.installmentRepo.WithTx(tx)
+		penaltyRepoTx := s.penaltyRepo.WithTx(tx)
+
+		transaction, err := transactionRepoTx.FindByID(transactionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if transaction.Status != entity.TransactionActive {
+			return nil // settled or closed since the candidates were listed
+		}
+
+		installments, err := installmentRepoTx.FindByTransactionID(transactionID)
+		if err != nil {
+			return err
+		}
+		payments, err := s.paymentRepo.WithTx(tx).FindByTransactionID(transactionID)
+		if err != nil {
+			return err
+		}
+		paid := installmentPayments(payments)
+
+		for i := range installments {
+			installment := &installments[i]
+			dueDate := dateOnly(installment.DueDate)
+			if !installment.Status.IsOpen() || !dueDate.Before(asOf) {
+				continue
+			}
+
+			installment.Status = entity.InstallmentOverdue
+			installment.DaysPastDue = daysBetween(dueDate, asOf)
+
+			for _, penalty := range s.latePenalties(transaction, installment, paid[installment.ID], asOf) {
+				inserted, err := penaltyRepoTx.CreateIfAbsent(penalty)
+				if err != nil {
+					return err
+				}
+				if !inserted {
+					continue
+				}
+
+				installment.PenaltyAmount += penalty.Amount
+				transaction.PenaltyAmount += penalty.Amount
+				transaction.OutstandingAmount += penalty.Amount
+				result.penalties++
+				result.penaltyAmount += penalty.Amount
+
+				// Log to Audit File
+				logger.AuditLogger.Info().
+					Uint("user_id", transaction.UserID).
+					Uint64("transaction_id", transaction.ID).
+					Str("contract_number", transaction.ContractNumber).
+					Uint64("installment_id", installment.ID).
+					Int("installment_number", installment.InstallmentNumber).
+					Str("accrual_date", penalty.AccrualDate.Format(time.DateOnly)).
+					Int("days_past_due", penalty.DaysPastDue).
+					Str("base_amount", penalty.BaseAmount.String()).
+					Str("amount", penalty.Amount.String()).
+					Msg("Late Fee Accrued")
+			}
+			if installment.DaysPastDue > s.cfg.GracePeriodDays {
+				accruedTo := asOf
+				installment.PenaltyAccruedTo = &accruedTo
+			}
+
+			if err := installmentRepoTx.Update(installment); err != nil {
+				return err
+			}
+		}
+
+		previousBucket := transaction.DPDBucket
+		transaction.DaysPastDue = contractDaysPastDue(installments, asOf)
+		transaction.DPDBucket = entity.DPDBucketFor(transaction.DaysPastDue)
+		result.daysPastDue = transaction.DaysPastDue
+
+		if err := transactionRepoTx.Update(transaction); err != nil {
+			return err
+		}
+
+		if transaction.DPDBucket != previousBucket {
+			logger.AuditLogger.Info().
+				Uint("user_id", transaction.UserID).
+				Uint64("transaction_id", transaction.ID).
+				Str("contract_number", transaction.ContractNumber).
+				Str("from_bucket", string(previousBucket)).
+				Str("to_bucket", string(transaction.DPDBucket)).
+				Int("days_past_due", transaction.DaysPastDue).
+				Msg("DPD Bucket Changed")
+		}
+
+		return nil
+	})
+	return result, err
+}
+
+// installmentPayment is an amount paid on an installment, late fees excluded, on a calendar day
+type installmentPayment struct {
+	day    time.Time
+	amount money.Money
+}
+
+// installmentPayments groups what the payments allocated to each installment by the day they were paid
+func installmentPayments(payments []entity.Payment) map[uint64][]installmentPayment {
+	paid := make(map[uint64][]installmentPayment)
+	for _, payment := range payments {
+		for _, allocation := range payment.Allocations {
+			paid[allocation.InstallmentID] = append(paid[allocation.InstallmentID], installmentPayment{
+				day:    dateOnly(payment.PaidAt),
+				amount: allocation.Amount - allocation.PenaltyAmount,
+			})
+		}
+	}
+	return paid
+}
+
+// latePenalties returns the daily late fees an overdue installment owes up to asOf that have not
+// been charged yet: one per day after the grace period and after PenaltyAccruedTo. Each is
+// LateFeeDailyRate percent of the installment amount unpaid at the start of that day, and together
+// they stop at the cap. When missed days are caught up, payments made since are added back to
+// the base of the days before them, so each day is charged as if the job had run on it.
+func (s *overdueService) latePenalties(transaction *entity.Transaction, installment *entity.Installment, paid []installmentPayment, asOf time.Time) []*entity.Penalty {
+	dueDate := dateOnly(installment.DueDate)
+	from := dueDate.AddDate(0, 0, s.cfg.GracePeriodDays+1)
+	if installment.PenaltyAccruedTo != nil {
+		if next := dateOnly(*installment.PenaltyAccruedTo).AddDate(0, 0, 1); next.After(from) {
+			from = next
+		}
+	}
+
+	unpaid := installment.TotalAmount - installment.PaidAmount
+	capped := s.cfg.LateFeeCapRate > 0
+	capRemaining := installment.TotalAmount.MulRate(s.cfg.LateFeeCapRate/100) - installment.PenaltyAmount
+
+	var penalties []*entity.Penalty
+	for day := from; !day.After(asOf); day = day.AddDate(0, 0, 1) {
+		base := unpaid
+		for _, payment := range paid {
+			if !payment.day.Before(day) {
+				base += payment.amount
+			}
+		}
+
+		amount := base.MulRate(s.cfg.LateFeeDailyRate / 100)
+		if capped {
+			amount = money.Min(amount, capRemaining)
+			capRemaining -= amount
+		}
+		if amount <= 0 {
+			break
+		}
+
+		penalties = append(penalties, &entity.Penalty{
+			TransactionID: transaction.ID,
+			InstallmentID: installment.ID,
+			UserID:        transaction.UserID,
+			AccrualDate:   day,
+			DaysPastDue:   daysBetween(dueDate, day),
+			BaseAmount:    base,
+			Rate:          s.cfg.LateFeeDailyRate,
+			Amount:        amount,
+		})
+	}
+	return penalties
+}
+
+// contractDaysPastDue is the days past due of the oldest open installment due before asOf, 0 if none
+func contractDaysPastDue(installments []entity.Installment, asOf time.Time) int {
+	today := dateOnly(asOf)
+	for _, installment := range installments {
+		if dueDate := dateOnly(installment.DueDate); installment.Status.IsOpen() && dueDate.Before(today) {
+			return daysBetween(dueDate, today) // installments are ordered by number, so by due date
+		}
+	}
+	return 0
+}
+
+// daysBetween counts the calendar days from a to b. Both are taken as their calendar day first,
+// so the time of day of a due date or of the job run never shifts the count.
+func daysBetween(a, b time.Time) int {
+	return int(dateOnly(b).Sub(dateOnly(a)) / (24 * time.Hour))
+}
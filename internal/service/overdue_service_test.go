@@ -0,0 +1,230 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository/mock"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestOverdueService_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
+	mockInstallmentRepo := mock.NewMockInstallmentRepository(ctrl)
+	mockPaymentRepo := mock.NewMockPaymentRepository(ctrl)
+	mockPenaltyRepo := mock.NewMockPenaltyRepository(ctrl)
+
+	db, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open gorm conn: %v", err)
+	}
+
+	service := services.NewOverdueService(mockTxRepo, mockInstallmentRepo, mockPaymentRepo, mockPenaltyRepo, config.OverdueConfig{
+		JobWorkers:       1,
+		GracePeriodDays:  3,
+		LateFeeDailyRate: 0.1,
+		LateFeeCapRate:   1,
+	}, gormDB)
+
+	date := func(day int) time.Time { return time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC) }
+	asOf := date(20)
+	transactionID := uint64(40)
+
+	expectContract := func(transaction *entity.Transaction, installments []entity.Installment, payments ...entity.Payment) {
+		mockTxRepo.EXPECT().FindOverdueCandidateIDs(asOf).Return([]uint64{transactionID}, nil)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		sqlMock.ExpectCommit()
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockInstallmentRepo.EXPECT().WithTx(gomock.Any()).Return(mockInstallmentRepo)
+		mockPenaltyRepo.EXPECT().WithTx(gomock.Any()).Return(mockPenaltyRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(transaction, nil)
+		mockInstallmentRepo.EXPECT().FindByTransactionID(transactionID).Return(installments, nil)
+		mockPaymentRepo.EXPECT().WithTx(gomock.Any()).Return(mockPaymentRepo)
+		mockPaymentRepo.EXPECT().FindByTransactionID(transactionID).Return(payments, nil)
+	}
+
+	t.Run("MarksOverdueAndAccruesLateFees", func(t *testing.T) {
+		accruedTo := date(18)
+		expectContract(
+			&entity.Transaction{ID: transactionID, UserID: 2, Status: entity.TransactionActive, OutstandingAmount: money.FromRupiah(330000), DPDBucket: entity.DPDCurrent},
+			[]entity.Installment{
+				// 10 days past due, fees already charged through the 18th: the 19th and 20th remain
+				{ID: 1, InstallmentNumber: 1, DueDate: date(10), TotalAmount: money.FromRupiah(110000), Status: entity.InstallmentOverdue, PenaltyAmount: money.FromRupiah(550), PenaltyAccruedTo: &accruedTo},
+				// 2 days past due, still within the grace period
+				{ID: 2, InstallmentNumber: 2, DueDate: date(18), TotalAmount: money.FromRupiah(110000), Status: entity.InstallmentUnpaid},
+				{ID: 3, InstallmentNumber: 3, DueDate: time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC), TotalAmount: money.FromRupiah(110000), Status: entity.InstallmentUnpaid},
+			},
+		)
+
+		var charged []time.Time
+		mockPenaltyRepo.EXPECT().CreateIfAbsent(gomock.Any()).DoAndReturn(func(p *entity.Penalty) (bool, error) {
+			assert.Equal(t, uint64(1), p.InstallmentID)
+			assert.Equal(t, money.FromRupiah(110), p.Amount)
+			charged = append(charged, p.AccrualDate)
+			return true, nil
+		}).Times(2)
+
+		mockInstallmentRepo.EXPECT().Update(gomock.Any()).Do(func(i *entity.Installment) {
+			assert.Equal(t, uint64(1), i.ID)
+			assert.Equal(t, entity.InstallmentOverdue, i.Status)
+			assert.Equal(t, 10, i.DaysPastDue)
+			assert.Equal(t, money.FromRupiah(770), i.PenaltyAmount)
+			assert.Equal(t, asOf, *i.PenaltyAccruedTo)
+		}).Return(nil)
+		mockInstallmentRepo.EXPECT().Update(gomock.Any()).Do(func(i *entity.Installment) {
+			assert.Equal(t, uint64(2), i.ID)
+			assert.Equal(t, entity.InstallmentOverdue, i.Status)
+			assert.Equal(t, 2, i.DaysPastDue)
+			assert.Equal(t, money.Money(0), i.PenaltyAmount)
+			assert.Nil(t, i.PenaltyAccruedTo)
+		}).Return(nil)
+
+		mockTxRepo.EXPECT().Update(gomock.Any()).Do(func(tr *entity.Transaction) {
+			assert.Equal(t, 10, tr.DaysPastDue)
+			assert.Equal(t, entity.DPD1To30, tr.DPDBucket)
+			assert.Equal(t, money.FromRupiah(330220), tr.OutstandingAmount)
+			assert.Equal(t, money.FromRupiah(220), tr.PenaltyAmount)
+		}).Return(nil)
+
+		summary, err := service.Run(context.Background(), asOf.Add(30*time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{date(19), date(20)}, charged)
+		assert.Equal(t, 1, summary.Contracts)
+		assert.Equal(t, 1, summary.PastDue)
+		assert.Equal(t, 2, summary.Penalties)
+		assert.Equal(t, money.FromRupiah(220), summary.PenaltyAmount)
+
+		if err := sqlMock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("StopsAtCap", func(t *testing.T) {
+		accruedTo := date(18)
+		expectContract(
+			&entity.Transaction{ID: transactionID, UserID: 2, Status: entity.TransactionActive, DPDBucket: entity.DPD1To30},
+			[]entity.Installment{
+				// cap is 1% of 110.000 = 1.100, 1.050 already charged
+				{ID: 1, InstallmentNumber: 1, DueDate: date(10), TotalAmount: money.FromRupiah(110000), Status: entity.InstallmentOverdue, PenaltyAmount: money.FromRupiah(1050), PenaltyAccruedTo: &accruedTo},
+			},
+		)
+
+		mockPenaltyRepo.EXPECT().CreateIfAbsent(gomock.Any()).DoAndReturn(func(p *entity.Penalty) (bool, error) {
+			assert.Equal(t, date(19), p.AccrualDate)
+			assert.Equal(t, money.FromRupiah(50), p.Amount)
+			return true, nil
+		})
+
+		mockInstallmentRepo.EXPECT().Update(gomock.Any()).Do(func(i *entity.Installment) {
+			assert.Equal(t, money.FromRupiah(1100), i.PenaltyAmount)
+		}).Return(nil)
+		mockTxRepo.EXPECT().Update(gomock.Any()).Return(nil)
+
+		summary, err := service.Run(context.Background(), asOf)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, summary.Penalties)
+		assert.Equal(t, money.FromRupiah(50), summary.PenaltyAmount)
+	})
+
+	t.Run("CatchesUpOnEachDaysBalance", func(t *testing.T) {
+		accruedTo := date(18)
+		expectContract(
+			&entity.Transaction{ID: transactionID, UserID: 2, Status: entity.TransactionActive, DPDBucket: entity.DPD1To30},
+			[]entity.Installment{
+				// half paid on the 19th, after the job last ran on the 18th
+				{ID: 1, InstallmentNumber: 1, DueDate: date(10), TotalAmount: money.FromRupiah(110000), PaidAmount: money.FromRupiah(55000), Status: entity.InstallmentOverdue, PenaltyAmount: money.FromRupiah(550), PenaltyAccruedTo: &accruedTo},
+			},
+			entity.Payment{ID: 7, TransactionID: transactionID, PaidAt: date(19).Add(10 * time.Hour), Allocations: []entity.PaymentAllocation{
+				{InstallmentID: 1, Amount: money.FromRupiah(55000)},
+			}},
+		)
+
+		var charged []money.Money
+		mockPenaltyRepo.EXPECT().CreateIfAbsent(gomock.Any()).DoAndReturn(func(p *entity.Penalty) (bool, error) {
+			charged = append(charged, p.Amount)
+			return true, nil
+		}).Times(2)
+
+		mockInstallmentRepo.EXPECT().Update(gomock.Any()).Do(func(i *entity.Installment) {
+			assert.Equal(t, money.FromRupiah(715), i.PenaltyAmount)
+		}).Return(nil)
+		mockTxRepo.EXPECT().Update(gomock.Any()).Return(nil)
+
+		_, err := service.Run(context.Background(), asOf)
+		assert.NoError(t, err)
+		// the 19th is charged on the full amount unpaid that morning, the 20th on what is left
+		assert.Equal(t, []money.Money{money.FromRupiah(110), money.FromRupiah(55)}, charged)
+	})
+
+	t.Run("SkipsDaysAlreadyCharged", func(t *testing.T) {
+		expectContract(
+			&entity.Transaction{ID: transactionID, UserID: 2, Status: entity.TransactionActive, DPDBucket: entity.DPD1To30},
+			[]entity.Installment{
+				{ID: 1, InstallmentNumber: 1, DueDate: date(15), TotalAmount: money.FromRupiah(110000), Status: entity.InstallmentOverdue},
+			},
+		)
+
+		// the 19th and 20th were charged by a concurrent run
+		mockPenaltyRepo.EXPECT().CreateIfAbsent(gomock.Any()).Return(false, nil).Times(2)
+
+		mockInstallmentRepo.EXPECT().Update(gomock.Any()).Do(func(i *entity.Installment) {
+			assert.Equal(t, money.Money(0), i.PenaltyAmount)
+			assert.Equal(t, asOf, *i.PenaltyAccruedTo)
+		}).Return(nil)
+		mockTxRepo.EXPECT().Update(gomock.Any()).Do(func(tr *entity.Transaction) {
+			assert.Equal(t, 5, tr.DaysPastDue)
+			assert.Equal(t, money.Money(0), tr.PenaltyAmount)
+		}).Return(nil)
+
+		summary, err := service.Run(context.Background(), asOf)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, summary.Penalties)
+	})
+
+	t.Run("CountsCalendarDays", func(t *testing.T) {
+		// Due late on the 18th, run early on the 20th: 26 hours, but 2 days past due
+		expectContract(
+			&entity.Transaction{ID: transactionID, UserID: 2, Status: entity.TransactionActive, DPDBucket: entity.DPDCurrent},
+			[]entity.Installment{
+				{ID: 1, InstallmentNumber: 1, DueDate: date(18).Add(23 * time.Hour), TotalAmount: money.FromRupiah(110000), Status: entity.InstallmentUnpaid},
+			},
+		)
+
+		mockInstallmentRepo.EXPECT().Update(gomock.Any()).Do(func(i *entity.Installment) {
+			assert.Equal(t, 2, i.DaysPastDue)
+		}).Return(nil)
+		mockTxRepo.EXPECT().Update(gomock.Any()).Do(func(tr *entity.Transaction) {
+			assert.Equal(t, 2, tr.DaysPastDue)
+			assert.Equal(t, entity.DPD1To30, tr.DPDBucket)
+		}).Return(nil)
+
+		_, err := service.Run(context.Background(), asOf.Add(time.Hour))
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+)
+
+var ErrInvalidCursor = errors.New("invalid or expired cursor")
+
+// decodeCursor parses the request cursor, nil for the first page. A cursor issued for a different
+// sort is rejected since its position means nothing in the requested order.
+func decodeCursor(raw, sort string) (*repository.Keyset, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	keyset, err := repository.DecodeKeyset(raw)
+	if err != nil || keyset.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+	return &keyset, nil
+}
+
+// cursorMeta builds the next/prev cursors for a page read from cursor. hasMore is whether rows
+// remain beyond the page in the direction read; the opposite direction has rows whenever the page
+// was reached through a cursor.
+func cursorMeta[T any](rows []T, cursor *repository.Keyset, hasMore bool, limit int, keyset func(T) repository.Keyset) dto.PaginationMeta {
+	hasNext, hasPrev := hasMore, cursor != nil
+	if cursor != nil && cursor.Backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	var next, prev string
+	if len(rows) > 0 {
+		if hasNext {
+			next = keyset(rows[len(rows)-1]).Encode()
+		}
+		if hasPrev {
+			first := keyset(rows[0])
+			first.Backward = true
+			prev = first.Encode()
+		}
+	}
+	return dto.NewCursorPaginationMeta(limit, next, prev)
+}
@@ -0,0 +1,411 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTransactionNotPayable     = errors.New("transaction is not active")
+	ErrPaymentExceedsOutstanding = errors.New("payment exceeds outstanding amount")
+	ErrSettlementAmountMismatch  = errors.New("settlement amount does not match the payoff quote")
+)
+
+type PaymentService interface {
+	CreatePayment(userID uint, transactionID uint64, req dto.CreatePaymentRequest) (*entity.Payment, error)
+	GetPayments(userID uint, transactionID uint64) ([]entity.Payment, error)
+	GetPayoffQuote(userID uint, transactionID uint64) (*dto.PayoffQuote, error)
+	SettleTransaction(userID uint, transactionID uint64, req dto.SettleTransactionRequest) (*entity.Payment, error)
+}
+
+type paymentService struct {
+	paymentRepo     repository.PaymentRepository
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+	limitRepo       repository.LimitRepository
+	mutationRepo    repository.LimitMutationRepository
+	userRepo        repository.UserRepository
+	settlement      config.SettlementConfig
+	db              *gorm.DB
+}
+
+func NewPaymentService(paymentRepo repository.PaymentRepository, transactionRepo repository.TransactionRepository, installmentRepo repository.InstallmentRepository, limitRepo repository.LimitRepository, mutationRepo repository.LimitMutationRepository, userRepo repository.UserRepository, settlement config.SettlementConfig, db *gorm.DB) PaymentService {
+	return &paymentService{
+		paymentRepo:     paymentRepo,
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+		limitRepo:       limitRepo,
+		mutationRepo:    mutationRepo,
+		userRepo:        userRepo,
+		settlement:      settlement,
+		db:              db,
+	}
+}
+
+// CreatePayment records a repayment received for an active transaction. The amount is allocated to
+// open installments oldest first; within an installment it covers late fees, then admin fee, then
+// interest, then principal. The principal portion is returned to the tenor limit, and the transaction is
+// settled once nothing is outstanding.
+func (s *paymentService) CreatePayment(userID uint, transactionID uint64, req dto.CreatePaymentRequest) (*entity.Payment, error) {
+	if _, err := findAccessibleTransaction(s.userRepo, s.transactionRepo, userID, transactionID); err != nil {
+		return nil, err
+	}
+
+	var payment *entity.Payment
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 1. Lock Transaction Row (serializes payments on the same contract)
+		if err := tx.Exec("SELECT id FROM transactions WHERE id = ? FOR UPDATE", transactionID).Error; err != nil {
+			return err
+		}
+
+		transactionRepoTx := s.transactionRepo.WithTx(tx)
+		installmentRepoTx := s.installmentRepo.WithTx(tx)
+
+		transaction, err := transactionRepoTx.FindByID(transactionID)
+		if err != nil {
+			return err
+		}
+		if transaction.Status != entity.TransactionActive {
+			return ErrTransactionNotPayable
+		}
+
+		installments, err := installmentRepoTx.FindByTransactionID(transactionID)
+		if err != nil {
+			return err
+		}
+
+		// Everything paid must be allocated: reject amounts above the contract's outstanding
+		// balance or above what its open installments still owe
+		amount := req.Amount
+		if amount > transaction.OutstandingAmount || amount > openBalance(installments) {
+			return ErrPaymentExceedsOutstanding
+		}
+
+		// 2. Allocate to installments
+		now := time.Now()
+		payment = &entity.Payment{
+			TransactionID: transaction.ID,
+			UserID:        transaction.UserID,
+			RecordedBy:    userID,
+			Amount:        amount,
+			Reference:     req.Reference,
+			PaidAt:        now,
+		}
+
+		remaining := amount
+		for i := range installments {
+			if remaining <= 0 {
+				break
+			}
+			installment := &installments[i]
+			if !installment.Status.IsOpen() {
+				continue
+			}
+
+			penalty := money.Min(remaining, installment.PenaltyDue())
+			installment.PenaltyPaid += penalty
+			remaining -= penalty
+
+			allocation := allocateToInstallment(installment, remaining)
+			remaining -= allocation.Amount
+
+			installment.PaidAmount += allocation.Amount
+			allocation.Amount += penalty
+			allocation.PenaltyAmount = penalty
+			if installment.PaidAmount >= installment.TotalAmount && installment.PenaltyDue() <= 0 {
+				installment.Status = entity.InstallmentPaid
+				installment.PaidAt = &now
+			}
+			if err := installmentRepoTx.Update(installment); err != nil {
+				return err
+			}
+
+			payment.PrincipalAmount += allocation.PrincipalAmount
+			payment.InterestAmount += allocation.InterestAmount
+			payment.AdminFeeAmount += allocation.AdminFeeAmount
+			payment.PenaltyAmount += allocation.PenaltyAmount
+			payment.Allocations = append(payment.Allocations, allocation)
+		}
+
+		if err := s.paymentRepo.WithTx(tx).Create(payment); err != nil {
+			return err
+		}
+
+		// 3. Update Outstanding Balance
+		transaction.OutstandingAmount -= amount
+		transaction.PrincipalPaid += payment.PrincipalAmount
+		if transaction.OutstandingAmount <= 0 && transaction.Status.CanTransitionTo(entity.TransactionSettled) {
+			transaction.Status = entity.TransactionSettled
+		}
+		transaction.DaysPastDue = contractDaysPastDue(installments, now)
+		transaction.DPDBucket = entity.DPDBucketFor(transaction.DaysPastDue)
+		if err := transactionRepoTx.Update(transaction); err != nil {
+			return err
+		}
+
+		// 4. Log Repayment Mutation
+		if payment.PrincipalAmount > 0 {
+			if err := s.logLimitReturn(tx, transaction, payment.PrincipalAmount, entity.MutationRepayment, "Repayment: "); err != nil {
+				return err
+			}
+		}
+
+		// Log to Audit File
+		logger.AuditLogger.Info().
+			Uint("user_id", transaction.UserID).
+			Uint("recorded_by", userID).
+			Uint64("transaction_id", transaction.ID).
+			Str("contract_number", transaction.ContractNumber).
+			Str("amount", amount.String()).
+			Str("principal_amount", payment.PrincipalAmount.String()).
+			Str("outstanding_amount", transaction.OutstandingAmount.String()).
+			Str("status", string(transaction.Status)).
+			Msg("Payment Recorded")
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (s *paymentService) GetPayments(userID uint, transactionID uint64) ([]entity.Payment, error) {
+	if _, err := findAccessibleTransaction(s.userRepo, s.transactionRepo, userID, transactionID); err != nil {
+		return nil, err
+	}
+
+	return s.paymentRepo.FindByTransactionID(transactionID)
+}
+
+// GetPayoffQuote returns what it takes to settle an active contract early today
+func (s *paymentService) GetPayoffQuote(userID uint, transactionID uint64) (*dto.PayoffQuote, error) {
+	transaction, err := findAccessibleTransaction(s.userRepo, s.transactionRepo, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Status != entity.TransactionActive {
+		return nil, ErrTransactionNotPayable
+	}
+
+	installments, err := s.installmentRepo.FindByTransactionID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	p := calculatePayoff(installments, now, s.settlement.EarlyTerminationFeeRate)
+	year, month, day := now.Date()
+
+	return &dto.PayoffQuote{
+		TransactionID:       transaction.ID,
+		ContractNumber:      transaction.ContractNumber,
+		AsOf:                now,
+		ValidUntil:          time.Date(year, month, day+1, 0, 0, 0, 0, now.Location()),
+		PrincipalAmount:     p.principal,
+		InterestAmount:      p.interest,
+		AdminFeeAmount:      p.adminFee,
+		PenaltyAmount:       p.penalty,
+		EarlyTerminationFee: p.fee,
+		TotalAmount:         p.total(),
+		InterestWaived:      p.interestWaived,
+	}, nil
+}
+
+// SettleTransaction pays off an active contract early. The payoff is recalculated under the
+// transaction row lock and must match req.Amount exactly. Every open installment is closed as
+// settled, the payment is recorded with its allocations, the contract is marked settled and the
+// remaining principal is returned to the tenor limit.
+func (s *paymentService) SettleTransaction(userID uint, transactionID uint64, req dto.SettleTransactionRequest) (*entity.Payment, error) {
+	if _, err := findAccessibleTransaction(s.userRepo, s.transactionRepo, userID, transactionID); err != nil {
+		return nil, err
+	}
+
+	var payment *entity.Payment
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 1. Lock Transaction Row (serializes settlement with payments on the same contract)
+		if err := tx.Exec("SELECT id FROM transactions WHERE id = ? FOR UPDATE", transactionID).Error; err != nil {
+			return err
+		}
+
+		transactionRepoTx := s.transactionRepo.WithTx(tx)
+		installmentRepoTx := s.installmentRepo.WithTx(tx)
+
+		transaction, err := transactionRepoTx.FindByID(transactionID)
+		if err != nil {
+			return err
+		}
+		if transaction.Status != entity.TransactionActive {
+			return ErrTransactionNotPayable
+		}
+
+		installments, err := installmentRepoTx.FindByTransactionID(transactionID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		p := calculatePayoff(installments, now, s.settlement.EarlyTerminationFeeRate)
+		if req.Amount != p.total() {
+			return fmt.Errorf("%w: payoff is %s", ErrSettlementAmountMismatch, p.total())
+		}
+
+		// 2. Close open installments
+		payment = &entity.Payment{
+			TransactionID:   transaction.ID,
+			UserID:          transaction.UserID,
+			RecordedBy:      userID,
+			Amount:          p.total(),
+			PrincipalAmount: p.principal,
+			InterestAmount:  p.interest,
+			AdminFeeAmount:  p.adminFee,
+			PenaltyAmount:   p.penalty,
+			FeeAmount:       p.fee,
+			Reference:       req.Reference,
+			PaidAt:          now,
+		}
+
+		for _, line := range p.lines {
+			installment := line.installment
+			installment.PaidAmount += line.principal + line.interest + line.adminFee
+			installment.PenaltyPaid += line.penalty
+			installment.Status = entity.InstallmentSettled
+			installment.PaidAt = &now
+			if err := installmentRepoTx.Update(installment); err != nil {
+				return err
+			}
+
+			if line.amount() > 0 {
+				payment.Allocations = append(payment.Allocations, entity.PaymentAllocation{
+					InstallmentID:   installment.ID,
+					Amount:          line.amount(),
+					PrincipalAmount: line.principal,
+					InterestAmount:  line.interest,
+					AdminFeeAmount:  line.adminFee,
+					PenaltyAmount:   line.penalty,
+				})
+			}
+		}
+
+		if err := s.paymentRepo.WithTx(tx).Create(payment); err != nil {
+			return err
+		}
+
+		// 3. Settle the contract
+		transaction.Status = entity.TransactionSettled
+		transaction.OutstandingAmount = 0
+		transaction.PrincipalPaid += p.principal
+		transaction.DaysPastDue = 0
+		transaction.DPDBucket = entity.DPDCurrent
+		if err := transactionRepoTx.Update(transaction); err != nil {
+			return err
+		}
+
+		// 4. Log Settlement Mutation
+		if p.principal > 0 {
+			if err := s.logLimitReturn(tx, transaction, p.principal, entity.MutationSettlement, "Early Settlement: "); err != nil {
+				return err
+			}
+		}
+
+		// Log to Audit File
+		logger.AuditLogger.Info().
+			Uint("user_id", transaction.UserID).
+			Uint("recorded_by", userID).
+			Uint64("transaction_id", transaction.ID).
+			Str("contract_number", transaction.ContractNumber).
+			Str("amount", payment.Amount.String()).
+			Str("principal_amount", p.principal.String()).
+			Str("interest_amount", p.interest.String()).
+			Str("early_termination_fee", p.fee.String()).
+			Str("interest_waived", p.interestWaived.String()).
+			Msg("Transaction Settled Early")
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// logLimitReturn writes the limit mutation for principal returned to the transaction's tenor limit
+func (s *paymentService) logLimitReturn(tx *gorm.DB, transaction *entity.Transaction, principal money.Money, action entity.MutationAction, reason string) error {
+	limits, err := s.limitRepo.WithTx(tx).FindByUserID(transaction.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, limit := range limits {
+		if int(limit.TenorMonth) != transaction.Tenor {
+			continue
+		}
+
+		mutation := &entity.LimitMutation{
+			UserID:       transaction.UserID,
+			TenorLimitID: uint(limit.ID),
+			OldAmount:    limit.LimitAmount, // Current Limit Ceiling
+			NewAmount:    limit.LimitAmount, // Current Limit Ceiling (Unchanged)
+			Amount:       principal,
+			Reason:       reason + transaction.ContractNumber,
+			Action:       action,
+		}
+		return s.mutationRepo.WithTx(tx).Create(mutation)
+	}
+
+	return nil
+}
+
+// openBalance is what the open installments still owe, late fees included
+func openBalance(installments []entity.Installment) money.Money {
+	var balance money.Money
+	for _, installment := range installments {
+		if installment.Status.IsOpen() {
+			balance += installment.TotalAmount - installment.PaidAmount + installment.PenaltyDue()
+		}
+	}
+	return balance
+}
+
+// allocateToInstallment applies up to amount to the unpaid part of an installment and splits it
+// into components in the order admin fee, interest, principal.
+func allocateToInstallment(installment *entity.Installment, amount money.Money) entity.PaymentAllocation {
+	applied := money.Min(amount, installment.TotalAmount-installment.PaidAmount)
+
+	before := installment.PaidAmount
+	after := before + applied
+
+	adminFee := portionPaid(before, after, 0, installment.AdminFeeAmount)
+	interest := portionPaid(before, after, installment.AdminFeeAmount, installment.InterestAmount)
+	principal := applied - adminFee - interest
+
+	return entity.PaymentAllocation{
+		InstallmentID:   installment.ID,
+		Amount:          applied,
+		PrincipalAmount: principal,
+		InterestAmount:  interest,
+		AdminFeeAmount:  adminFee,
+	}
+}
+
+// portionPaid returns how much of the component occupying [offset, offset+size) of an installment
+// is covered when its paid amount moves from before to after.
+func portionPaid(before, after, offset, size money.Money) money.Money {
+	start := money.Max(before-offset, 0)
+	end := money.Min(after-offset, size)
+	if end <= start {
+		return 0
+	}
+	return end - start
+}
@@ -0,0 +1,374 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository/mock"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestPaymentService_CreatePayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPaymentRepo := mock.NewMockPaymentRepository(ctrl)
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
+	mockInstallmentRepo := mock.NewMockInstallmentRepository(ctrl)
+	mockLimitRepo := mock.NewMockLimitRepository(ctrl)
+	mockMutationRepo := mock.NewMockLimitMutationRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+
+	db, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open gorm conn: %v", err)
+	}
+
+	service := services.NewPaymentService(mockPaymentRepo, mockTxRepo, mockInstallmentRepo, mockLimitRepo, mockMutationRepo, mockUserRepo, config.SettlementConfig{}, gormDB)
+	userID := uint(2)
+	transactionID := uint64(20)
+
+	newTransaction := func() *entity.Transaction {
+		return &entity.Transaction{
+			ID: transactionID, UserID: userID, ContractNumber: "CTR-020", Status: entity.TransactionActive,
+			Tenor: 2, OTR: money.FromRupiah(200000), OutstandingAmount: money.FromRupiah(221000),
+		}
+	}
+	newInstallments := func() []entity.Installment {
+		return []entity.Installment{
+			{ID: 1, InstallmentNumber: 1, PrincipalAmount: money.FromRupiah(100000), InterestAmount: money.FromRupiah(10000), AdminFeeAmount: money.FromRupiah(500), TotalAmount: money.FromRupiah(110500), Status: entity.InstallmentUnpaid},
+			{ID: 2, InstallmentNumber: 2, PrincipalAmount: money.FromRupiah(100000), InterestAmount: money.FromRupiah(10000), AdminFeeAmount: money.FromRupiah(500), TotalAmount: money.FromRupiah(110500), Status: entity.InstallmentUnpaid},
+		}
+	}
+
+	t.Run("AllocatesOldestFirst", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(newTransaction(), nil)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockInstallmentRepo.EXPECT().WithTx(gomock.Any()).Return(mockInstallmentRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(newTransaction(), nil)
+		mockInstallmentRepo.EXPECT().FindByTransactionID(transactionID).Return(newInstallments(), nil)
+
+		// First installment fully paid, second receives 9,500 (admin fee + part of interest)
+		mockInstallmentRepo.EXPECT().Update(gomock.Any()).Do(func(i *entity.Installment) {
+			assert.Equal(t, entity.InstallmentPaid, i.Status)
+		}).Return(nil)
+		mockInstallmentRepo.EXPECT().Update(gomock.Any()).Do(func(i *entity.Installment) {
+			assert.Equal(t, entity.InstallmentUnpaid, i.Status)
+			assert.Equal(t, money.FromRupiah(9500), i.PaidAmount)
+		}).Return(nil)
+
+		mockPaymentRepo.EXPECT().WithTx(gomock.Any()).Return(mockPaymentRepo)
+		mockPaymentRepo.EXPECT().Create(gomock.Any()).Do(func(p *entity.Payment) {
+			assert.Len(t, p.Allocations, 2)
+			assert.Equal(t, money.FromRupiah(100000), p.PrincipalAmount)
+			assert.Equal(t, money.FromRupiah(19000), p.InterestAmount)
+			assert.Equal(t, money.FromRupiah(1000), p.AdminFeeAmount)
+		}).Return(nil)
+
+		mockTxRepo.EXPECT().Update(gomock.Any()).Do(func(tr *entity.Transaction) {
+			assert.Equal(t, money.FromRupiah(101000), tr.OutstandingAmount)
+			assert.Equal(t, money.FromRupiah(100000), tr.PrincipalPaid)
+			assert.Equal(t, entity.TransactionActive, tr.Status)
+		}).Return(nil)
+
+		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
+		mockLimitRepo.EXPECT().FindByUserID(userID).Return([]entity.TenorLimit{{ID: 9, TenorMonth: 2, LimitAmount: money.FromRupiah(1200000)}}, nil)
+		mockMutationRepo.EXPECT().WithTx(gomock.Any()).Return(mockMutationRepo)
+		mockMutationRepo.EXPECT().Create(gomock.Any()).Do(func(m *entity.LimitMutation) {
+			assert.Equal(t, entity.MutationRepayment, m.Action)
+			assert.Equal(t, uint(9), m.TenorLimitID)
+			assert.Equal(t, money.FromRupiah(100000), m.Amount)
+		}).Return(nil)
+
+		sqlMock.ExpectCommit()
+
+		payment, err := service.CreatePayment(userID, transactionID, dto.CreatePaymentRequest{Amount: money.FromRupiah(120000)})
+		assert.NoError(t, err)
+		assert.Equal(t, money.FromRupiah(120000), payment.Amount)
+
+		if err := sqlMock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("PaysLateFeesFirst", func(t *testing.T) {
+		overdue := newInstallments()
+		overdue[0].Status = entity.InstallmentOverdue
+		overdue[0].DueDate = time.Now().AddDate(0, 0, -10)
+		overdue[0].PenaltyAmount = money.FromRupiah(1000)
+		overdue[1].DueDate = time.Now().AddDate(0, 0, 20)
+		transaction := newTransaction()
+		transaction.OutstandingAmount += money.FromRupiah(1000)
+		transaction.DaysPastDue = 10
+
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(transaction, nil)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockInstallmentRepo.EXPECT().WithTx(gomock.Any()).Return(mockInstallmentRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(transaction, nil)
+		mockInstallmentRepo.EXPECT().FindByTransactionID(transactionID).Return(overdue, nil)
+
+		// 1.000 late fee, then the full 110.500 installment
+		mockInstallmentRepo.EXPECT().Update(gomock.Any()).Do(func(i *entity.Installment) {
+			assert.Equal(t, entity.InstallmentPaid, i.Status)
+			assert.Equal(t, money.FromRupiah(1000), i.PenaltyPaid)
+		}).Return(nil)
+
+		mockPaymentRepo.EXPECT().WithTx(gomock.Any()).Return(mockPaymentRepo)
+		mockPaymentRepo.EXPECT().Create(gomock.Any()).Do(func(p *entity.Payment) {
+			assert.Equal(t, money.FromRupiah(1000), p.PenaltyAmount)
+			assert.Equal(t, money.FromRupiah(111500), p.Allocations[0].Amount)
+		}).Return(nil)
+
+		mockTxRepo.EXPECT().Update(gomock.Any()).Do(func(tr *entity.Transaction) {
+			assert.Equal(t, money.FromRupiah(110500), tr.OutstandingAmount)
+			assert.Equal(t, 0, tr.DaysPastDue)
+			assert.Equal(t, entity.DPDCurrent, tr.DPDBucket)
+		}).Return(nil)
+
+		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
+		mockLimitRepo.EXPECT().FindByUserID(userID).Return([]entity.TenorLimit{{ID: 9, TenorMonth: 2, LimitAmount: money.FromRupiah(1200000)}}, nil)
+		mockMutationRepo.EXPECT().WithTx(gomock.Any()).Return(mockMutationRepo)
+		mockMutationRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+		sqlMock.ExpectCommit()
+
+		_, err := service.CreatePayment(userID, transactionID, dto.CreatePaymentRequest{Amount: money.FromRupiah(111500)})
+		assert.NoError(t, err)
+
+		if err := sqlMock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("ExceedsOutstanding", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(newTransaction(), nil)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockInstallmentRepo.EXPECT().WithTx(gomock.Any()).Return(mockInstallmentRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(newTransaction(), nil)
+		mockInstallmentRepo.EXPECT().FindByTransactionID(transactionID).Return(newInstallments(), nil)
+
+		sqlMock.ExpectRollback()
+
+		_, err := service.CreatePayment(userID, transactionID, dto.CreatePaymentRequest{Amount: money.FromRupiah(300000)})
+		assert.ErrorIs(t, err, services.ErrPaymentExceedsOutstanding)
+	})
+
+	t.Run("ExceedsOpenInstallments", func(t *testing.T) {
+		// The outstanding balance allows it, but the installments owe only 221.000: the excess
+		// would not be allocated anywhere
+		transaction := newTransaction()
+		transaction.OutstandingAmount = money.FromRupiah(300000)
+
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "admin"}}, nil)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(transaction, nil)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockInstallmentRepo.EXPECT().WithTx(gomock.Any()).Return(mockInstallmentRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(transaction, nil)
+		mockInstallmentRepo.EXPECT().FindByTransactionID(transactionID).Return(newInstallments(), nil)
+
+		sqlMock.ExpectRollback()
+
+		_, err := service.CreatePayment(userID, transactionID, dto.CreatePaymentRequest{Amount: money.FromRupiah(250000)})
+		assert.ErrorIs(t, err, services.ErrPaymentExceedsOutstanding)
+
+		if err := sqlMock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestPaymentService_EarlySettlement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPaymentRepo := mock.NewMockPaymentRepository(ctrl)
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
+	mockInstallmentRepo := mock.NewMockInstallmentRepository(ctrl)
+	mockLimitRepo := mock.NewMockLimitRepository(ctrl)
+	mockMutationRepo := mock.NewMockLimitMutationRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+
+	db, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open gorm conn: %v", err)
+	}
+
+	service := services.NewPaymentService(mockPaymentRepo, mockTxRepo, mockInstallmentRepo, mockLimitRepo, mockMutationRepo, mockUserRepo, config.SettlementConfig{EarlyTerminationFeeRate: 2}, gormDB)
+	userID := uint(2)
+	transactionID := uint64(30)
+
+	year, month, day := time.Now().Date()
+	daysFromToday := func(days int) time.Time {
+		return time.Date(year, month, day+days, 0, 0, 0, 0, time.UTC)
+	}
+
+	newTransaction := func() *entity.Transaction {
+		return &entity.Transaction{
+			ID: transactionID, UserID: userID, ContractNumber: "CTR-030", Status: entity.TransactionActive,
+			Tenor: 3, OTR: money.FromRupiah(300000), OutstandingAmount: money.FromRupiah(328500),
+		}
+	}
+	// One installment overdue, one running (a third of its period elapsed), one in the future
+	newInstallments := func() []entity.Installment {
+		installments := make([]entity.Installment, 3)
+		for i, due := range []int{-10, 20, 50} {
+			installments[i] = entity.Installment{
+				ID: uint64(i + 1), InstallmentNumber: i + 1, DueDate: daysFromToday(due),
+				PrincipalAmount: money.FromRupiah(100000), InterestAmount: money.FromRupiah(9000), AdminFeeAmount: money.FromRupiah(500),
+				TotalAmount: money.FromRupiah(109500), Status: entity.InstallmentUnpaid,
+			}
+		}
+		return installments
+	}
+
+	t.Run("Quote", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(newTransaction(), nil)
+		mockInstallmentRepo.EXPECT().FindByTransactionID(transactionID).Return(newInstallments(), nil)
+
+		quote, err := service.GetPayoffQuote(userID, transactionID)
+		assert.NoError(t, err)
+		assert.Equal(t, money.FromRupiah(300000), quote.PrincipalAmount)
+		assert.Equal(t, money.FromRupiah(12000), quote.InterestAmount) // 9.000 overdue + 3.000 accrued
+		assert.Equal(t, money.FromRupiah(1500), quote.AdminFeeAmount)
+		assert.Equal(t, money.FromRupiah(6000), quote.EarlyTerminationFee)
+		assert.Equal(t, money.FromRupiah(319500), quote.TotalAmount)
+		assert.Equal(t, money.FromRupiah(15000), quote.InterestWaived)
+	})
+
+	t.Run("QuoteNotActive", func(t *testing.T) {
+		settled := newTransaction()
+		settled.Status = entity.TransactionSettled
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(settled, nil)
+
+		_, err := service.GetPayoffQuote(userID, transactionID)
+		assert.ErrorIs(t, err, services.ErrTransactionNotPayable)
+	})
+
+	t.Run("Settle", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(newTransaction(), nil)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockInstallmentRepo.EXPECT().WithTx(gomock.Any()).Return(mockInstallmentRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(newTransaction(), nil)
+		mockInstallmentRepo.EXPECT().FindByTransactionID(transactionID).Return(newInstallments(), nil)
+
+		mockInstallmentRepo.EXPECT().Update(gomock.Any()).Do(func(i *entity.Installment) {
+			assert.Equal(t, entity.InstallmentSettled, i.Status)
+		}).Return(nil).Times(3)
+
+		mockPaymentRepo.EXPECT().WithTx(gomock.Any()).Return(mockPaymentRepo)
+		mockPaymentRepo.EXPECT().Create(gomock.Any()).Do(func(p *entity.Payment) {
+			assert.Len(t, p.Allocations, 3)
+			assert.Equal(t, money.FromRupiah(319500), p.Amount)
+			assert.Equal(t, money.FromRupiah(6000), p.FeeAmount)
+			assert.Equal(t, money.FromRupiah(100500), p.Allocations[2].Amount) // principal + admin fee, interest waived
+		}).Return(nil)
+
+		mockTxRepo.EXPECT().Update(gomock.Any()).Do(func(tr *entity.Transaction) {
+			assert.Equal(t, entity.TransactionSettled, tr.Status)
+			assert.Equal(t, money.Money(0), tr.OutstandingAmount)
+			assert.Equal(t, money.FromRupiah(300000), tr.PrincipalPaid)
+		}).Return(nil)
+
+		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
+		mockLimitRepo.EXPECT().FindByUserID(userID).Return([]entity.TenorLimit{{ID: 9, TenorMonth: 3, LimitAmount: money.FromRupiah(1500000)}}, nil)
+		mockMutationRepo.EXPECT().WithTx(gomock.Any()).Return(mockMutationRepo)
+		mockMutationRepo.EXPECT().Create(gomock.Any()).Do(func(m *entity.LimitMutation) {
+			assert.Equal(t, entity.MutationSettlement, m.Action)
+			assert.Equal(t, money.FromRupiah(300000), m.Amount)
+			assert.Contains(t, m.Reason, "CTR-030")
+		}).Return(nil)
+
+		sqlMock.ExpectCommit()
+
+		payment, err := service.SettleTransaction(userID, transactionID, dto.SettleTransactionRequest{Amount: money.FromRupiah(319500)})
+		assert.NoError(t, err)
+		assert.Equal(t, money.FromRupiah(319500), payment.Amount)
+
+		if err := sqlMock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("SettleAmountMismatch", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(newTransaction(), nil)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockInstallmentRepo.EXPECT().WithTx(gomock.Any()).Return(mockInstallmentRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(newTransaction(), nil)
+		mockInstallmentRepo.EXPECT().FindByTransactionID(transactionID).Return(newInstallments(), nil)
+
+		sqlMock.ExpectRollback()
+
+		_, err := service.SettleTransaction(userID, transactionID, dto.SettleTransactionRequest{Amount: money.FromRupiah(328500)})
+		assert.ErrorIs(t, err, services.ErrSettlementAmountMismatch)
+	})
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
+// payoffLine is what early settlement charges against one open installment
+type payoffLine struct {
+	installment    *entity.Installment
+	principal      money.Money
+	interest       money.Money
+	adminFee       money.Money
+	penalty        money.Money
+	interestWaived money.Money
+}
+
+func (l payoffLine) amount() money.Money {
+	return l.principal + l.interest + l.adminFee + l.penalty
+}
+
+// payoff is the amount needed to close a contract on a given day
+type payoff struct {
+	lines          []payoffLine
+	principal      money.Money
+	interest       money.Money
+	adminFee       money.Money
+	penalty        money.Money
+	fee            money.Money
+	interestWaived money.Money
+}
+
+func (p payoff) total() money.Money {
+	return p.principal + p.interest + p.adminFee + p.penalty + p.fee
+}
+
+// calculatePayoff works out the early settlement of a contract as of asOf. The remaining principal,
+// admin fee and unpaid late fees are due in full. Interest is due in full for installments already past their due
+// date, pro rata by day for the installment period asOf falls in, and waived for later periods.
+// The early termination fee is feeRate percent of the remaining principal. Amounts already paid
+// on an installment are taken off each component in allocation order (admin fee, interest, principal).
+func calculatePayoff(installments []entity.Installment, asOf time.Time, feeRate float64) payoff {
+	var p payoff
+	today := dateOnly(asOf)
+
+	for i := range installments {
+		installment := &installments[i]
+		if !installment.Status.IsOpen() {
+			continue
+		}
+
+		paid := installment.PaidAmount
+		adminFeePaid := portionPaid(0, paid, 0, installment.AdminFeeAmount)
+		interestPaid := portionPaid(0, paid, installment.AdminFeeAmount, installment.InterestAmount)
+		principalPaid := portionPaid(0, paid, installment.AdminFeeAmount+installment.InterestAmount, installment.PrincipalAmount)
+
+		line := payoffLine{
+			installment: installment,
+			principal:   installment.PrincipalAmount - principalPaid,
+			adminFee:    installment.AdminFeeAmount - adminFeePaid,
+			penalty:     installment.PenaltyDue(),
+		}
+
+		dueDate := dateOnly(installment.DueDate)
+		periodStart := addMonths(dueDate, -1)
+		if i > 0 {
+			periodStart = dateOnly(installments[i-1].DueDate)
+		}
+
+		unpaidInterest := installment.InterestAmount - interestPaid
+		switch {
+		case !today.Before(dueDate):
+			line.interest = unpaidInterest
+		case today.After(periodStart):
+			elapsed := today.Sub(periodStart).Hours() / 24
+			length := dueDate.Sub(periodStart).Hours() / 24
+			accrued := installment.InterestAmount.MulRate(elapsed / length)
+			line.interest = money.Max(accrued-interestPaid, 0)
+		}
+		line.interestWaived = unpaidInterest - line.interest
+
+		p.lines = append(p.lines, line)
+		p.principal += line.principal
+		p.interest += line.interest
+		p.adminFee += line.adminFee
+		p.penalty += line.penalty
+		p.interestWaived += line.interestWaived
+	}
+
+	p.fee = p.principal.MulRate(feeRate / 100)
+	return p
+}
+
+// dateOnly drops the time of day, keeping the calendar date
+func dateOnly(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
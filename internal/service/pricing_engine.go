@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/dto"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+)
+
+var (
+	ErrUnknownProduct  = errors.New("unknown product")
+	ErrPricingMismatch = errors.New("submitted pricing does not match the calculated pricing")
+)
+
+// pricingTolerance is how far a client-submitted amount may be off the calculated one,
+// so clients that round to whole rupiah are not rejected
+var pricingTolerance = money.FromRupiah(1)
+
+// PricingEngine computes admin fee, interest and installment amounts from the product configuration
+type PricingEngine struct {
+	cfg config.PricingConfig
+}
+
+func NewPricingEngine(cfg config.PricingConfig) *PricingEngine {
+	return &PricingEngine{cfg: cfg}
+}
+
+// Price returns an unsaved transaction with product, interest method and rate, admin fee,
+// total interest and the regular monthly installment filled in. An empty product means the default product.
+func (e *PricingEngine) Price(product string, otr money.Money, tenor int) (*entity.Transaction, error) {
+	if product == "" {
+		product = e.cfg.DefaultProduct
+	}
+	pricing, ok := e.cfg.Products[product]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProduct, product)
+	}
+
+	transaction := &entity.Transaction{
+		OTR:            otr,
+		Tenor:          tenor,
+		Product:        product,
+		InterestMethod: entity.InterestMethod(pricing.Method),
+		InterestRate:   pricing.AnnualRate,
+		AdminFee:       pricing.AdminFeeFlat + otr.MulRate(pricing.AdminFeeRate/100),
+	}
+
+	switch transaction.InterestMethod {
+	case entity.InterestFlat:
+		transaction.InterestAmount = otr.MulRate(pricing.AnnualRate / 100 * float64(tenor) / 12)
+	case entity.InterestEffective:
+		_, interests := annuitySplit(otr, pricing.AnnualRate/100/12, tenor)
+		for _, interest := range interests {
+			transaction.InterestAmount += interest
+		}
+	default:
+		return nil, fmt.Errorf("product %s has unsupported interest method %q", product, pricing.Method)
+	}
+
+	// The regular installment is the first one; only the last may differ because of rounding
+	if schedule := buildInstallmentSchedule(transaction, time.Now()); len(schedule) > 0 {
+		transaction.InstallmentAmount = schedule[0].TotalAmount
+	}
+
+	return transaction, nil
+}
+
+// Quote prices a financing and includes the installment schedule it would get if approved at start
+func (e *PricingEngine) Quote(product string, otr money.Money, tenor int, start time.Time) (*dto.TransactionQuote, error) {
+	transaction, err := e.Price(product, otr, tenor)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &dto.TransactionQuote{
+		Product:           transaction.Product,
+		InterestMethod:    string(transaction.InterestMethod),
+		InterestRate:      transaction.InterestRate,
+		OTR:               transaction.OTR,
+		Tenor:             transaction.Tenor,
+		AdminFee:          transaction.AdminFee,
+		InterestAmount:    transaction.InterestAmount,
+		InstallmentAmount: transaction.InstallmentAmount,
+		TotalPayable:      transaction.OTR + transaction.InterestAmount + transaction.AdminFee,
+	}
+	for _, installment := range buildInstallmentSchedule(transaction, start) {
+		quote.Installments = append(quote.Installments, dto.QuoteInstallment{
+			InstallmentNumber: installment.InstallmentNumber,
+			DueDate:           installment.DueDate,
+			PrincipalAmount:   installment.PrincipalAmount,
+			InterestAmount:    installment.InterestAmount,
+			AdminFeeAmount:    installment.AdminFeeAmount,
+			TotalAmount:       installment.TotalAmount,
+		})
+	}
+
+	return quote, nil
+}
+
+// checkSubmittedPricing rejects client-submitted amounts that differ from the calculated ones.
+// Amounts the client left out (zero) are not checked.
+func checkSubmittedPricing(req dto.CreateTransactionRequest, priced *entity.Transaction) error {
+	fields := []struct {
+		name                string
+		submitted, expected money.Money
+	}{
+		{"admin_fee", req.AdminFee, priced.AdminFee},
+		{"interest_amount", req.InterestAmount, priced.InterestAmount},
+		{"installment_amount", req.InstallmentAmount, priced.InstallmentAmount},
+	}
+
+	for _, field := range fields {
+		if field.submitted == 0 {
+			continue
+		}
+		if diff := field.submitted - field.expected; diff > pricingTolerance || diff < -pricingTolerance {
+			return fmt.Errorf("%w: %s is %s, expected %s", ErrPricingMismatch, field.name, field.submitted, field.expected)
+		}
+	}
+	return nil
+}
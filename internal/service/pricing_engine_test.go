@@ -0,0 +1,64 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPricingEngine(t *testing.T) {
+	engine := services.NewPricingEngine(config.PricingConfig{
+		DefaultProduct: "standard",
+		Products: map[string]config.ProductPricing{
+			"standard":  {Method: "flat", AnnualRate: 12, AdminFeeFlat: money.FromRupiah(10000)},
+			"effective": {Method: "effective", AnnualRate: 18, AdminFeeRate: 1},
+		},
+	})
+
+	t.Run("Flat", func(t *testing.T) {
+		transaction, err := engine.Price("", money.FromRupiah(600000), 6)
+		assert.NoError(t, err)
+		assert.Equal(t, "standard", transaction.Product)
+		assert.Equal(t, entity.InterestFlat, transaction.InterestMethod)
+		assert.Equal(t, money.FromRupiah(10000), transaction.AdminFee)
+		assert.Equal(t, money.FromRupiah(36000), transaction.InterestAmount) // 600.000 x 12% x 6/12
+		assert.Equal(t, money.MustParse("107666.66"), transaction.InstallmentAmount)
+	})
+
+	t.Run("Effective", func(t *testing.T) {
+		transaction, err := engine.Price("effective", money.FromRupiah(1000000), 12)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.InterestEffective, transaction.InterestMethod)
+		assert.Equal(t, money.FromRupiah(10000), transaction.AdminFee)
+		assert.Equal(t, money.MustParse("100159.91"), transaction.InterestAmount)
+		assert.Equal(t, money.MustParse("92513.32"), transaction.InstallmentAmount) // 91.679,99 annuity + 833,33 admin fee
+	})
+
+	t.Run("QuoteScheduleAddsUp", func(t *testing.T) {
+		start := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+		quote, err := engine.Quote("effective", money.FromRupiah(1000000), 12, start)
+		assert.NoError(t, err)
+		assert.Len(t, quote.Installments, 12)
+		assert.Equal(t, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), quote.Installments[0].DueDate)
+
+		var principal, interest, total money.Money
+		for _, installment := range quote.Installments {
+			principal += installment.PrincipalAmount
+			interest += installment.InterestAmount
+			total += installment.TotalAmount
+		}
+		assert.Equal(t, money.FromRupiah(1000000), principal)
+		assert.Equal(t, quote.InterestAmount, interest)
+		assert.Equal(t, quote.TotalPayable, total)
+	})
+
+	t.Run("UnknownProduct", func(t *testing.T) {
+		_, err := engine.Price("gold", money.FromRupiah(1000000), 12)
+		assert.ErrorIs(t, err, services.ErrUnknownProduct)
+	})
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/hadi-projects/xyz-finance-go/internal/dto"
 	"github.com/hadi-projects/xyz-finance-go/internal/entity"
@@ -10,10 +12,25 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrTransactionNotFound     = errors.New("transaction not found")
+	ErrInvalidStatusTransition = errors.New("invalid transaction status transition")
+	ErrDuplicateContractNumber = errors.New("contract number already exists")
+	ErrCancellationNotAllowed  = errors.New("only pending transactions can be cancelled by the owner")
+)
+
 type TransactionService interface {
-	CreateTransaction(userId uint, req dto.CreateTransactionRequest) error
+	CreateTransaction(userId uint, req dto.CreateTransactionRequest) (*entity.Transaction, error)
+	SimulateTransaction(req dto.SimulateTransactionRequest) (*dto.TransactionQuote, error)
 	GetTransactions(userID uint) ([]entity.Transaction, error)
-	GetTransactionsPaginated(userID uint, page, limit int) ([]entity.Transaction, int64, error)
+	GetTransactionsPaginated(userID uint, query dto.TransactionQuery) ([]entity.Transaction, int64, error)
+	GetTransactionsByCursor(userID uint, query dto.TransactionQuery) ([]entity.Transaction, dto.PaginationMeta, error)
+	GetTransaction(userID uint, transactionID uint64) (*entity.Transaction, error)
+	ApproveTransaction(adminID uint, transactionID uint64) error
+	RejectTransaction(adminID uint, transactionID uint64, reason string) error
+	DisburseTransaction(adminID uint, transactionID uint64) error
+	CancelTransaction(userID uint, transactionID uint64, reason string) error
+	GetInstallments(userID uint, transactionID uint64) ([]entity.Installment, error)
 }
 
 type transactionService struct {
@@ -21,21 +38,38 @@ type transactionService struct {
 	limitRepo       repository.LimitRepository
 	mutationRepo    repository.LimitMutationRepository
 	userRepo        repository.UserRepository
+	installmentRepo repository.InstallmentRepository
+	contractNumbers *ContractNumberGenerator
+	pricing         *PricingEngine
+	collectibility  *CollectibilityClassifier
 	db              *gorm.DB
 }
 
-func NewTransactionService(transactionRepo repository.TransactionRepository, limitRepo repository.LimitRepository, mutationRepo repository.LimitMutationRepository, userRepo repository.UserRepository, db *gorm.DB) TransactionService {
+func NewTransactionService(transactionRepo repository.TransactionRepository, limitRepo repository.LimitRepository, mutationRepo repository.LimitMutationRepository, userRepo repository.UserRepository, installmentRepo repository.InstallmentRepository, contractNumbers *ContractNumberGenerator, pricing *PricingEngine, collectibility *CollectibilityClassifier, db *gorm.DB) TransactionService {
 	return &transactionService{
 		transactionRepo: transactionRepo,
 		limitRepo:       limitRepo,
 		mutationRepo:    mutationRepo,
 		userRepo:        userRepo,
+		installmentRepo: installmentRepo,
+		contractNumbers: contractNumbers,
+		pricing:         pricing,
+		collectibility:  collectibility,
 		db:              db,
 	}
 }
 
-func (s *transactionService) CreateTransaction(userId uint, req dto.CreateTransactionRequest) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
+func (s *transactionService) CreateTransaction(userId uint, req dto.CreateTransactionRequest) (*entity.Transaction, error) {
+	// Amounts are always calculated server-side; client-sent ones are only checked
+	transaction, err := s.pricing.Price(req.Product, req.OTR, req.Tenor)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSubmittedPricing(req, transaction); err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
 		// 1. Lock User Row (prevents race condition for this user)
 		if err := tx.Exec("SELECT id FROM users WHERE id = ? FOR UPDATE", userId).Error; err != nil {
 			return err
@@ -45,71 +79,60 @@ func (s *transactionService) CreateTransaction(userId uint, req dto.CreateTransa
 		limitRepoTx := s.limitRepo.WithTx(tx)
 		transactionRepoTx := s.transactionRepo.WithTx(tx)
 
-		limits, err := limitRepoTx.FindByUserID(userId)
-		if err != nil {
+		// Consumers with a poor collectibility grade get no new financing
+		if err := s.collectibility.CheckEligible(transactionRepoTx, userId); err != nil {
 			return err
 		}
-		var limitAmount float64
-		found := false
-		for _, limit := range limits {
-			if int(limit.TenorMonth) == req.Tenor {
-				limitAmount = limit.LimitAmount
-				found = true
-				break
-			}
-		}
 
-		if !found {
-			return errors.New("limit not found for the requested tenor")
-		}
-
-		existingTransactions, err := transactionRepoTx.FindByUserID(userId)
+		// 3. Check Available Limit (shared calculation with the limit endpoint)
+		usage, err := NewLimitCalculator(limitRepoTx, transactionRepoTx).CalculateTenor(userId, req.Tenor)
 		if err != nil {
 			return err
 		}
-
-		var usedAmount float64
-		for _, t := range existingTransactions {
-			if t.Tenor == req.Tenor {
-				usedAmount += t.OTR
-			}
-		}
-		if usedAmount+req.OTR > limitAmount {
+		if transaction.OTR > usage.AvailableAmount {
 			return errors.New("insufficient limit")
 		}
 
-		transaction := &entity.Transaction{
-			UserID:            userId,
-			ContractNumber:    req.ContractNumber,
-			OTR:               req.OTR,
-			AdminFee:          req.AdminFee,
-			InstallmentAmount: req.InstallmentAmount,
-			InterestAmount:    req.InterestAmount,
-			AssetName:         req.AssetName,
-			Status:            "pending",
-			Tenor:             req.Tenor,
+		// 4. Contract number: generated unless a legacy client supplied its own. The lookup is only
+		// a fast path; the unique index decides when two requests race for the same number.
+		contractNumber := req.ContractNumber
+		if contractNumber == "" {
+			contractNumber, err = s.contractNumbers.Generate(tx, time.Now())
+			if err != nil {
+				return err
+			}
+		} else {
+			exists, err := transactionRepoTx.ExistsByContractNumber(contractNumber)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return ErrDuplicateContractNumber
+			}
 		}
 
+		transaction.UserID = userId
+		transaction.ContractNumber = contractNumber
+		transaction.AssetName = req.AssetName
+		transaction.Status = entity.TransactionPending
+
 		if err := transactionRepoTx.Create(transaction); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrDuplicateContractNumber
+			}
 			return err
 		}
 
-		// Finds the Tenor Limit ID for the usage entry
-		var limitID uint
-		for _, limit := range limits {
-			if int(limit.TenorMonth) == req.Tenor {
-				limitID = uint(limit.ID)
-				break
-			}
-		}
+		limitID := uint(usage.LimitID)
 
 		// Log Usage Mutation
 		mutation := &entity.LimitMutation{
 			UserID:       userId,
 			TenorLimitID: limitID,
-			OldAmount:    limitAmount, // Current Limit Ceiling
-			NewAmount:    limitAmount, // Current Limit Ceiling (Unchanged)
-			Reason:       "Transaction Usage: " + req.ContractNumber,
+			OldAmount:    usage.LimitAmount, // Current Limit Ceiling
+			NewAmount:    usage.LimitAmount, // Current Limit Ceiling (Unchanged)
+			Amount:       transaction.OTR,
+			Reason:       "Transaction Usage: " + contractNumber,
 			Action:       entity.MutationUsage,
 		}
 
@@ -121,12 +144,21 @@ func (s *transactionService) CreateTransaction(userId uint, req dto.CreateTransa
 		logger.AuditLogger.Info().
 			Uint("user_id", userId).
 			Uint("limit_id", limitID).
-			Float64("amount", req.OTR).
-			Str("contract_number", req.ContractNumber).
+			Str("amount", transaction.OTR.String()).
+			Str("contract_number", contractNumber).
 			Msg("Transaction Created (Limit Usage)")
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func (s *transactionService) SimulateTransaction(req dto.SimulateTransactionRequest) (*dto.TransactionQuote, error) {
+	return s.pricing.Quote(req.Product, req.OTR, req.Tenor, time.Now())
 }
 
 func (s *transactionService) GetTransactions(userID uint) ([]entity.Transaction, error) {
@@ -142,17 +174,307 @@ func (s *transactionService) GetTransactions(userID uint) ([]entity.Transaction,
 	return s.transactionRepo.FindByUserID(userID)
 }
 
-func (s *transactionService) GetTransactionsPaginated(userID uint, page, limit int) ([]entity.Transaction, int64, error) {
-	user, err := s.userRepo.FindByID(userID)
+func (s *transactionService) GetTransactionsPaginated(userID uint, query dto.TransactionQuery) ([]entity.Transaction, int64, error) {
+	filter, isAdmin, err := s.transactionFilter(userID, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	transactions, total, err := s.transactionRepo.FindPaginated(filter, query.GetOffset(), query.Limit)
 	if err != nil {
 		return nil, 0, err
 	}
+	if isAdmin {
+		s.collectibility.ClassifyAll(transactions)
+	}
+	return transactions, total, nil
+}
+
+// GetTransactionsByCursor is the keyset-paginated listing: it seeks from the cursor instead of
+// counting and skipping rows, so its cost does not grow with the page depth.
+func (s *transactionService) GetTransactionsByCursor(userID uint, query dto.TransactionQuery) ([]entity.Transaction, dto.PaginationMeta, error) {
+	filter, isAdmin, err := s.transactionFilter(userID, query)
+	if err != nil {
+		return nil, dto.PaginationMeta{}, err
+	}
+
+	sort := filter.SortOrDefault()
+	cursor, err := decodeCursor(query.Cursor, string(sort))
+	if err != nil {
+		return nil, dto.PaginationMeta{}, err
+	}
+
+	transactions, hasMore, err := s.transactionRepo.FindByCursor(filter, cursor, query.Limit)
+	if err != nil {
+		return nil, dto.PaginationMeta{}, err
+	}
+	if isAdmin {
+		s.collectibility.ClassifyAll(transactions)
+	}
+
+	meta := cursorMeta(transactions, cursor, hasMore, query.Limit, func(t entity.Transaction) repository.Keyset {
+		return repository.TransactionKeyset(t, sort)
+	})
+	return transactions, meta, nil
+}
+
+// transactionFilter maps the listing query to a repository filter, scoped to the caller's own
+// transactions unless they are an admin. The bool reports whether the caller is an admin.
+func (s *transactionService) transactionFilter(userID uint, query dto.TransactionQuery) (repository.TransactionFilter, bool, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return repository.TransactionFilter{}, false, err
+	}
 
-	offset := (page - 1) * limit
+	filter := repository.TransactionFilter{
+		UserID:         query.UserID,
+		Status:         entity.TransactionStatus(query.Status),
+		Tenor:          query.Tenor,
+		AssetName:      strings.TrimSpace(query.AssetName),
+		ContractNumber: strings.TrimSpace(query.ContractNumber),
+		MinOTR:         query.MinOTR,
+		MaxOTR:         query.MaxOTR,
+		From:           query.StartDate,
+		Sort:           repository.TransactionSort(query.Sort),
+	}
+	if !query.EndDate.IsZero() {
+		filter.To = query.EndDate.AddDate(0, 0, 1)
+	}
 
+	// Non-admin users may only see their own transactions
+	isAdmin := user.Role.Name == "admin"
+	if !isAdmin {
+		filter.UserID = userID
+	}
+
+	return filter, isAdmin, nil
+}
+
+func (s *transactionService) GetTransaction(userID uint, transactionID uint64) (*entity.Transaction, error) {
+	transaction, user, err := loadAccessibleTransaction(s.userRepo, s.transactionRepo, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The collectibility grade is credit information for staff only
 	if user.Role.Name == "admin" {
-		return s.transactionRepo.FindAllPaginated(offset, limit)
+		transaction.Collectibility = s.collectibility.Classify(transaction)
+	}
+	return transaction, nil
+}
+
+func (s *transactionService) ApproveTransaction(adminID uint, transactionID uint64) error {
+	return s.changeStatus(adminID, transactionID, entity.TransactionApproved, "", s.generateInstallments)
+}
+
+func (s *transactionService) RejectTransaction(adminID uint, transactionID uint64, reason string) error {
+	return s.changeStatus(adminID, transactionID, entity.TransactionRejected, reason, s.releaseLimit)
+}
+
+func (s *transactionService) DisburseTransaction(adminID uint, transactionID uint64) error {
+	return s.changeStatus(adminID, transactionID, entity.TransactionActive, "", nil)
+}
+
+// changeStatus moves a transaction to the given status inside a DB transaction.
+// See transitionStatus for the locking and the onChange hook.
+func (s *transactionService) changeStatus(actorID uint, transactionID uint64, to entity.TransactionStatus, reason string, onChange func(tx *gorm.DB, t *entity.Transaction) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.transitionStatus(tx, actorID, transactionID, to, reason, nil, onChange)
+	})
+}
+
+// transitionStatus moves a transaction to the given status within tx.
+// The transaction row is locked so concurrent status changes are serialized. guard (optional) runs
+// against the locked row before the transition and may refuse it. onChange (optional) runs after the
+// new status is applied and before the row is saved, so it may adjust the transaction further.
+func (s *transactionService) transitionStatus(tx *gorm.DB, actorID uint, transactionID uint64, to entity.TransactionStatus, reason string, guard func(t *entity.Transaction) error, onChange func(tx *gorm.DB, t *entity.Transaction) error) error {
+	if err := tx.Exec("SELECT id FROM transactions WHERE id = ? FOR UPDATE", transactionID).Error; err != nil {
+		return err
+	}
+
+	transactionRepoTx := s.transactionRepo.WithTx(tx)
+
+	transaction, err := transactionRepoTx.FindByID(transactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTransactionNotFound
+		}
+		return err
+	}
+
+	if guard != nil {
+		if err := guard(transaction); err != nil {
+			return err
+		}
+	}
+
+	from := transaction.Status
+	if !from.CanTransitionTo(to) {
+		return ErrInvalidStatusTransition
+	}
+
+	now := time.Now()
+	transaction.Status = to
+	transaction.ReviewedBy = &actorID
+	transaction.ReviewedAt = &now
+	switch to {
+	case entity.TransactionRejected:
+		transaction.RejectionReason = reason
+	case entity.TransactionCancelled:
+		transaction.CancellationReason = reason
+	}
+
+	if onChange != nil {
+		if err := onChange(tx, transaction); err != nil {
+			return err
+		}
+	}
+
+	if err := transactionRepoTx.Update(transaction); err != nil {
+		return err
+	}
+
+	// Log to Audit File
+	logger.AuditLogger.Info().
+		Uint("actor_id", actorID).
+		Uint("user_id", transaction.UserID).
+		Uint64("transaction_id", transaction.ID).
+		Str("contract_number", transaction.ContractNumber).
+		Str("from_status", string(from)).
+		Str("to_status", string(to)).
+		Str("reason", reason).
+		Msg("Transaction Status Changed")
+
+	return nil
+}
+
+// CancelTransaction cancels a transaction before disbursement. Owners may cancel while it is still
+// pending; admins also after approval. The owner's users row is locked first, as in CreateTransaction,
+// then the transaction row, so a cancel cannot interleave with a concurrent approve or a new
+// transaction's limit check.
+func (s *transactionService) CancelTransaction(userID uint, transactionID uint64, reason string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	isAdmin := user.Role.Name == "admin"
+
+	transaction, err := s.transactionRepo.FindByID(transactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTransactionNotFound
+		}
+		return err
+	}
+	if !isAdmin && transaction.UserID != userID {
+		return ErrTransactionNotFound
+	}
+
+	guard := func(t *entity.Transaction) error {
+		if !isAdmin && t.Status != entity.TransactionPending {
+			return ErrCancellationNotAllowed
+		}
+		return nil
+	}
+
+	onChange := func(tx *gorm.DB, t *entity.Transaction) error {
+		if err := s.installmentRepo.WithTx(tx).CancelUnpaidByTransactionID(t.ID); err != nil {
+			return err
+		}
+		t.OutstandingAmount = 0
+		return s.releaseLimit(tx, t)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT id FROM users WHERE id = ? FOR UPDATE", transaction.UserID).Error; err != nil {
+			return err
+		}
+		return s.transitionStatus(tx, userID, transactionID, entity.TransactionCancelled, reason, guard, onChange)
+	})
+}
+
+// generateInstallments creates the monthly installment schedule for a freshly approved transaction
+func (s *transactionService) generateInstallments(tx *gorm.DB, transaction *entity.Transaction) error {
+	installments := buildInstallmentSchedule(transaction, *transaction.ReviewedAt)
+	if err := s.installmentRepo.WithTx(tx).CreateBatch(installments); err != nil {
+		return err
+	}
+
+	transaction.OutstandingAmount = 0
+	for _, installment := range installments {
+		transaction.OutstandingAmount += installment.TotalAmount
+	}
+
+	logger.AuditLogger.Info().
+		Uint64("transaction_id", transaction.ID).
+		Str("contract_number", transaction.ContractNumber).
+		Int("installments", len(installments)).
+		Msg("Installment Schedule Generated")
+
+	return nil
+}
+
+// releaseLimit records the compensating mutation for a transaction that no longer counts against its tenor limit.
+func (s *transactionService) releaseLimit(tx *gorm.DB, transaction *entity.Transaction) error {
+	limits, err := s.limitRepo.WithTx(tx).FindByUserID(transaction.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, limit := range limits {
+		if int(limit.TenorMonth) != transaction.Tenor {
+			continue
+		}
+
+		mutation := &entity.LimitMutation{
+			UserID:       transaction.UserID,
+			TenorLimitID: uint(limit.ID),
+			OldAmount:    limit.LimitAmount, // Current Limit Ceiling
+			NewAmount:    limit.LimitAmount, // Current Limit Ceiling (Unchanged)
+			Amount:       transaction.OTR - transaction.PrincipalPaid,
+			Reason:       "Transaction Release (" + string(transaction.Status) + "): " + transaction.ContractNumber,
+			Action:       entity.MutationRelease,
+		}
+		return s.mutationRepo.WithTx(tx).Create(mutation)
+	}
+
+	// The tenor limit may have been deleted since the transaction was created; nothing to release against.
+	return nil
+}
+
+func (s *transactionService) GetInstallments(userID uint, transactionID uint64) ([]entity.Installment, error) {
+	if _, err := findAccessibleTransaction(s.userRepo, s.transactionRepo, userID, transactionID); err != nil {
+		return nil, err
+	}
+
+	return s.installmentRepo.FindByTransactionID(transactionID)
+}
+
+// findAccessibleTransaction loads a transaction the user may see: admins see every transaction,
+// other users only their own. Transactions owned by someone else are reported as not found.
+func findAccessibleTransaction(userRepo repository.UserRepository, transactionRepo repository.TransactionRepository, userID uint, transactionID uint64) (*entity.Transaction, error) {
+	transaction, _, err := loadAccessibleTransaction(userRepo, transactionRepo, userID, transactionID)
+	return transaction, err
+}
+
+// loadAccessibleTransaction is findAccessibleTransaction that also returns the calling user
+func loadAccessibleTransaction(userRepo repository.UserRepository, transactionRepo repository.TransactionRepository, userID uint, transactionID uint64) (*entity.Transaction, *entity.User, error) {
+	user, err := userRepo.FindByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transaction, err := transactionRepo.FindByID(transactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrTransactionNotFound
+		}
+		return nil, nil, err
+	}
+
+	if user.Role.Name != "admin" && transaction.UserID != userID {
+		return nil, nil, ErrTransactionNotFound
 	}
 
-	return s.transactionRepo.FindByUserIDPaginated(userID, offset, limit)
+	return transaction, user, nil
 }
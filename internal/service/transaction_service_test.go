@@ -2,12 +2,16 @@ package services_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hadi-projects/xyz-finance-go/config"
 	"github.com/hadi-projects/xyz-finance-go/internal/dto"
 	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
 	"github.com/hadi-projects/xyz-finance-go/internal/repository/mock"
 	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"gorm.io/driver/mysql"
@@ -22,6 +26,8 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
 	mockMutationRepo := mock.NewMockLimitMutationRepository(ctrl)
 	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockInstallmentRepo := mock.NewMockInstallmentRepository(ctrl)
+	mockSequenceRepo := mock.NewMockContractSequenceRepository(ctrl)
 
 	db, sqlMock, err := sqlmock.New()
 	if err != nil {
@@ -37,15 +43,29 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 		t.Fatalf("failed to open gorm conn: %v", err)
 	}
 
-	service := services.NewTransactionService(mockTxRepo, mockLimitRepo, mockMutationRepo, mockUserRepo, gormDB)
+	contractNumbers := services.NewContractNumberGenerator(config.ContractNumberConfig{
+		Prefix:         "XYZ",
+		Branch:         "001",
+		DateFormat:     "200601",
+		SequenceDigits: 6,
+		Format:         "{PREFIX}-{BRANCH}-{DATE}-{SEQ}{CHECK}",
+	}, mockSequenceRepo)
+	pricing := services.NewPricingEngine(config.PricingConfig{
+		DefaultProduct: "standard",
+		Products: map[string]config.ProductPricing{
+			"standard": {Method: "flat", AnnualRate: 12, AdminFeeFlat: money.FromRupiah(500)},
+		},
+	})
+	collectibility := services.NewCollectibilityClassifier(config.CollectibilityConfig{MaxGradeForNewTransaction: 2})
+	service := services.NewTransactionService(mockTxRepo, mockLimitRepo, mockMutationRepo, mockUserRepo, mockInstallmentRepo, contractNumbers, pricing, collectibility, gormDB)
 
 	t.Run("Success", func(t *testing.T) {
 		req := dto.CreateTransactionRequest{
 			ContractNumber:    "CTR-001",
-			OTR:               10000,
-			AdminFee:          500,
-			InstallmentAmount: 1100,
-			InterestAmount:    100,
+			OTR:               money.FromRupiah(10000),
+			AdminFee:          money.FromRupiah(500),
+			InstallmentAmount: money.FromRupiah(10600),
+			InterestAmount:    money.FromRupiah(100),
 			AssetName:         "Item1",
 			Tenor:             1,
 		}
@@ -59,29 +79,38 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 
 		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
 		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockTxRepo.EXPECT().MaxDaysPastDueByUserID(userId).Return(0, nil)
 		mockMutationRepo.EXPECT().WithTx(gomock.Any()).Return(mockMutationRepo)
 
 		mockLimitRepo.EXPECT().FindByUserID(userId).Return([]entity.TenorLimit{
-			{ID: 123, TenorMonth: 1, LimitAmount: 20000},
+			{ID: 123, TenorMonth: 1, LimitAmount: money.FromRupiah(20000)},
 		}, nil)
 
-		mockTxRepo.EXPECT().FindByUserID(userId).Return([]entity.Transaction{}, nil)
+		mockTxRepo.EXPECT().GetLimitUsage([]uint{userId}).Return(nil, nil)
+		mockTxRepo.EXPECT().ExistsByContractNumber("CTR-001").Return(false, nil)
 
-		mockTxRepo.EXPECT().Create(gomock.Any()).Return(nil)
+		mockTxRepo.EXPECT().Create(gomock.Any()).Do(func(tx *entity.Transaction) {
+			assert.Equal(t, "standard", tx.Product)
+			assert.Equal(t, entity.InterestFlat, tx.InterestMethod)
+			assert.Equal(t, money.FromRupiah(100), tx.InterestAmount)
+			assert.Equal(t, money.FromRupiah(500), tx.AdminFee)
+			assert.Equal(t, money.FromRupiah(10600), tx.InstallmentAmount)
+		}).Return(nil)
 
 		// Expect Mutation Logging
 		mockMutationRepo.EXPECT().Create(gomock.Any()).Do(func(m *entity.LimitMutation) {
 			assert.Equal(t, entity.MutationUsage, m.Action)
 			assert.Equal(t, uint(123), m.TenorLimitID)
-			assert.Equal(t, 20000.0, m.OldAmount)
-			assert.Equal(t, 20000.0, m.NewAmount)
+			assert.Equal(t, money.FromRupiah(20000), m.OldAmount)
+			assert.Equal(t, money.FromRupiah(20000), m.NewAmount)
 			assert.Contains(t, m.Reason, "Transaction Usage")
 		}).Return(nil)
 
 		sqlMock.ExpectCommit()
 
-		err := service.CreateTransaction(userId, req)
+		transaction, err := service.CreateTransaction(userId, req)
 		assert.NoError(t, err)
+		assert.Equal(t, "CTR-001", transaction.ContractNumber)
 
 		if err := sqlMock.ExpectationsWereMet(); err != nil {
 			t.Errorf("there were unfulfilled expectations: %s", err)
@@ -90,7 +119,7 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 
 	t.Run("InsufficientLimit", func(t *testing.T) {
 		req := dto.CreateTransactionRequest{
-			OTR:   30000,
+			OTR:   money.FromRupiah(30000),
 			Tenor: 1,
 		}
 		userId := uint(1)
@@ -103,6 +132,7 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 
 		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
 		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockTxRepo.EXPECT().MaxDaysPastDueByUserID(userId).Return(0, nil)
 		// Note: Mutation repo might not be called if error happens early, or WithTx might be called.
 		// Depending on implementation order. In current impl, WithTx is called early.
 		// mockMutationRepo.EXPECT().WithTx(gomock.Any()).Return(mockMutationRepo) // Not called because WithTx is called inside transaction block, and limit check fails inside. Wait.
@@ -118,18 +148,135 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 		// So we need WithTx expectation, but NO Create expectation.
 
 		mockLimitRepo.EXPECT().FindByUserID(userId).Return([]entity.TenorLimit{
-			{TenorMonth: 1, LimitAmount: 20000},
+			{TenorMonth: 1, LimitAmount: money.FromRupiah(20000)},
 		}, nil)
 
-		mockTxRepo.EXPECT().FindByUserID(userId).Return([]entity.Transaction{}, nil)
+		mockTxRepo.EXPECT().GetLimitUsage([]uint{userId}).Return(nil, nil)
 
 		sqlMock.ExpectRollback()
 
-		err := service.CreateTransaction(userId, req)
+		_, err := service.CreateTransaction(userId, req)
 		assert.Error(t, err)
 		assert.Equal(t, "insufficient limit", err.Error())
 	})
 
+	t.Run("GeneratesContractNumber", func(t *testing.T) {
+		req := dto.CreateTransactionRequest{OTR: money.FromRupiah(10000), AssetName: "Item1", Tenor: 1}
+		userId := uint(1)
+		period := time.Now().Format("200601")
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM users WHERE id = \\? FOR UPDATE").
+			WithArgs(userId).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockTxRepo.EXPECT().MaxDaysPastDueByUserID(userId).Return(0, nil)
+		mockMutationRepo.EXPECT().WithTx(gomock.Any()).Return(mockMutationRepo)
+		mockSequenceRepo.EXPECT().WithTx(gomock.Any()).Return(mockSequenceRepo)
+
+		mockLimitRepo.EXPECT().FindByUserID(userId).Return([]entity.TenorLimit{
+			{ID: 123, TenorMonth: 1, LimitAmount: money.FromRupiah(20000)},
+		}, nil)
+		mockTxRepo.EXPECT().GetLimitUsage([]uint{userId}).Return(nil, nil)
+		mockSequenceRepo.EXPECT().Next("XYZ|001|"+period).Return(uint64(1), nil)
+		mockTxRepo.EXPECT().Create(gomock.Any()).Return(nil)
+		mockMutationRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+		sqlMock.ExpectCommit()
+
+		transaction, err := service.CreateTransaction(userId, req)
+		assert.NoError(t, err)
+		assert.Regexp(t, "^XYZ-001-"+period+"-000001[0-9]$", transaction.ContractNumber)
+	})
+
+	t.Run("PricingMismatch", func(t *testing.T) {
+		req := dto.CreateTransactionRequest{OTR: money.FromRupiah(10000), InstallmentAmount: money.FromRupiah(1000), AssetName: "Item1", Tenor: 1}
+
+		_, err := service.CreateTransaction(uint(1), req)
+		assert.ErrorIs(t, err, services.ErrPricingMismatch)
+	})
+
+	t.Run("UnknownProduct", func(t *testing.T) {
+		req := dto.CreateTransactionRequest{Product: "gold", OTR: money.FromRupiah(10000), AssetName: "Item1", Tenor: 1}
+
+		_, err := service.CreateTransaction(uint(1), req)
+		assert.ErrorIs(t, err, services.ErrUnknownProduct)
+	})
+
+	t.Run("DuplicateContractNumber", func(t *testing.T) {
+		req := dto.CreateTransactionRequest{ContractNumber: "CTR-001", OTR: money.FromRupiah(10000), Tenor: 1}
+		userId := uint(1)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM users WHERE id = \\? FOR UPDATE").
+			WithArgs(userId).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockTxRepo.EXPECT().MaxDaysPastDueByUserID(userId).Return(0, nil)
+
+		mockLimitRepo.EXPECT().FindByUserID(userId).Return([]entity.TenorLimit{
+			{ID: 123, TenorMonth: 1, LimitAmount: money.FromRupiah(20000)},
+		}, nil)
+		mockTxRepo.EXPECT().GetLimitUsage([]uint{userId}).Return(nil, nil)
+		mockTxRepo.EXPECT().ExistsByContractNumber("CTR-001").Return(true, nil)
+
+		sqlMock.ExpectRollback()
+
+		_, err := service.CreateTransaction(userId, req)
+		assert.ErrorIs(t, err, services.ErrDuplicateContractNumber)
+	})
+
+	t.Run("DuplicateContractNumberOnInsert", func(t *testing.T) {
+		// A concurrent request took the number between the lookup and the insert
+		req := dto.CreateTransactionRequest{ContractNumber: "CTR-002", OTR: money.FromRupiah(10000), Tenor: 1}
+		userId := uint(1)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM users WHERE id = \\? FOR UPDATE").
+			WithArgs(userId).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockTxRepo.EXPECT().MaxDaysPastDueByUserID(userId).Return(0, nil)
+
+		mockLimitRepo.EXPECT().FindByUserID(userId).Return([]entity.TenorLimit{
+			{ID: 123, TenorMonth: 1, LimitAmount: money.FromRupiah(20000)},
+		}, nil)
+		mockTxRepo.EXPECT().GetLimitUsage([]uint{userId}).Return(nil, nil)
+		mockTxRepo.EXPECT().ExistsByContractNumber("CTR-002").Return(false, nil)
+		mockTxRepo.EXPECT().Create(gomock.Any()).Return(gorm.ErrDuplicatedKey)
+
+		sqlMock.ExpectRollback()
+
+		_, err := service.CreateTransaction(userId, req)
+		assert.ErrorIs(t, err, services.ErrDuplicateContractNumber)
+	})
+
+	t.Run("CollectibilityAboveMaxGrade", func(t *testing.T) {
+		req := dto.CreateTransactionRequest{OTR: money.FromRupiah(10000), AssetName: "Item1", Tenor: 1}
+		userId := uint(1)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM users WHERE id = \\? FOR UPDATE").
+			WithArgs(userId).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		// 95 days past due on another contract: kurang lancar (grade 3)
+		mockTxRepo.EXPECT().MaxDaysPastDueByUserID(userId).Return(95, nil)
+		sqlMock.ExpectRollback()
+
+		_, err := service.CreateTransaction(userId, req)
+		assert.ErrorIs(t, err, services.ErrCollectibilityNotEligible)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
 	t.Run("GetTransactions_Admin", func(t *testing.T) {
 		userID := uint(1)
 		adminRole := entity.Role{Name: "admin"}
@@ -164,4 +311,449 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedTransactions, result)
 	})
+
+	t.Run("GetTransactionsPaginated_UserScopedToOwnTransactions", func(t *testing.T) {
+		userID := uint(2)
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindPaginated(repository.TransactionFilter{
+			UserID:    userID,
+			Status:    entity.TransactionActive,
+			AssetName: "honda",
+			MinOTR:    money.FromRupiah(1000000),
+			From:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			To:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			Sort:      repository.SortOTRDesc,
+		}, 20, 20).Return(nil, int64(0), nil)
+
+		_, _, err := service.GetTransactionsPaginated(userID, dto.TransactionQuery{
+			PaginationRequest: dto.PaginationRequest{Page: 2, Limit: 20},
+			UserID:            99, // ignored for non-admins
+			Status:            "active",
+			AssetName:         " honda ",
+			MinOTR:            money.FromRupiah(1000000),
+			StartDate:         time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:           time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+			Sort:              "otr_desc",
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("GetTransactionsPaginated_AdminFiltersByUser", func(t *testing.T) {
+		adminID := uint(1)
+		mockUserRepo.EXPECT().FindByID(adminID).Return(&entity.User{ID: adminID, Role: entity.Role{Name: "admin"}}, nil)
+		mockTxRepo.EXPECT().FindPaginated(repository.TransactionFilter{UserID: 3}, 0, 20).Return(nil, int64(0), nil)
+
+		_, _, err := service.GetTransactionsPaginated(adminID, dto.TransactionQuery{
+			PaginationRequest: dto.PaginationRequest{Page: 1, Limit: 20},
+			UserID:            3,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("GetTransactionsPaginated_CollectibilityOnlyForAdmins", func(t *testing.T) {
+		query := dto.TransactionQuery{PaginationRequest: dto.PaginationRequest{Page: 1, Limit: 20}}
+		pastDue := func() []entity.Transaction {
+			return []entity.Transaction{{ID: 1, DaysPastDue: 0}, {ID: 2, DaysPastDue: 45}, {ID: 3, DaysPastDue: 200}}
+		}
+
+		adminID := uint(1)
+		mockUserRepo.EXPECT().FindByID(adminID).Return(&entity.User{ID: adminID, Role: entity.Role{Name: "admin"}}, nil)
+		mockTxRepo.EXPECT().FindPaginated(repository.TransactionFilter{}, 0, 20).Return(pastDue(), int64(3), nil)
+
+		transactions, _, err := service.GetTransactionsPaginated(adminID, query)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.CollectibilityCurrent, transactions[0].Collectibility)
+		assert.Equal(t, entity.CollectibilitySpecialMention, transactions[1].Collectibility)
+		assert.Equal(t, entity.CollectibilityLoss, transactions[2].Collectibility)
+
+		userID := uint(2)
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindPaginated(repository.TransactionFilter{UserID: userID}, 0, 20).Return(pastDue(), int64(3), nil)
+
+		transactions, _, err = service.GetTransactionsPaginated(userID, query)
+		assert.NoError(t, err)
+		for _, transaction := range transactions {
+			assert.Zero(t, transaction.Collectibility)
+		}
+	})
+}
+
+func TestTransactionService_ChangeStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLimitRepo := mock.NewMockLimitRepository(ctrl)
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
+	mockMutationRepo := mock.NewMockLimitMutationRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockInstallmentRepo := mock.NewMockInstallmentRepository(ctrl)
+
+	db, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open gorm conn: %v", err)
+	}
+
+	service := services.NewTransactionService(mockTxRepo, mockLimitRepo, mockMutationRepo, mockUserRepo, mockInstallmentRepo, nil, nil, nil, gormDB)
+	adminID := uint(1)
+
+	t.Run("Approve_Success", func(t *testing.T) {
+		transactionID := uint64(10)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(&entity.Transaction{
+			ID: transactionID, UserID: 2, Status: entity.TransactionPending, Tenor: 3,
+			OTR: money.FromRupiah(100000), InterestAmount: money.FromRupiah(10000), AdminFee: money.FromRupiah(500),
+		}, nil)
+		mockTxRepo.EXPECT().Update(gomock.Any()).Do(func(tr *entity.Transaction) {
+			assert.Equal(t, entity.TransactionApproved, tr.Status)
+			assert.Equal(t, adminID, *tr.ReviewedBy)
+		}).Return(nil)
+
+		mockInstallmentRepo.EXPECT().WithTx(gomock.Any()).Return(mockInstallmentRepo)
+		mockInstallmentRepo.EXPECT().CreateBatch(gomock.Any()).Do(func(installments []entity.Installment) {
+			assert.Len(t, installments, 3)
+			var principal, interest, adminFee money.Money
+			for i, inst := range installments {
+				assert.Equal(t, i+1, inst.InstallmentNumber)
+				principal += inst.PrincipalAmount
+				interest += inst.InterestAmount
+				adminFee += inst.AdminFeeAmount
+			}
+			assert.Equal(t, money.FromRupiah(100000), principal)
+			assert.Equal(t, money.FromRupiah(10000), interest)
+			assert.Equal(t, money.FromRupiah(500), adminFee)
+			assert.True(t, installments[0].DueDate.Before(installments[1].DueDate))
+		}).Return(nil)
+
+		sqlMock.ExpectCommit()
+
+		err := service.ApproveTransaction(adminID, transactionID)
+		assert.NoError(t, err)
+
+		if err := sqlMock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Reject_ReleasesLimit", func(t *testing.T) {
+		transactionID := uint64(11)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(&entity.Transaction{
+			ID: transactionID, UserID: 2, ContractNumber: "CTR-011", Status: entity.TransactionPending, Tenor: 3,
+		}, nil)
+		mockTxRepo.EXPECT().Update(gomock.Any()).Do(func(tr *entity.Transaction) {
+			assert.Equal(t, entity.TransactionRejected, tr.Status)
+			assert.Equal(t, "incomplete documents", tr.RejectionReason)
+		}).Return(nil)
+
+		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
+		mockLimitRepo.EXPECT().FindByUserID(uint(2)).Return([]entity.TenorLimit{
+			{ID: 7, TenorMonth: 3, LimitAmount: money.FromRupiah(500000)},
+		}, nil)
+
+		mockMutationRepo.EXPECT().WithTx(gomock.Any()).Return(mockMutationRepo)
+		mockMutationRepo.EXPECT().Create(gomock.Any()).Do(func(m *entity.LimitMutation) {
+			assert.Equal(t, entity.MutationRelease, m.Action)
+			assert.Equal(t, uint(7), m.TenorLimitID)
+			assert.Contains(t, m.Reason, "CTR-011")
+		}).Return(nil)
+
+		sqlMock.ExpectCommit()
+
+		err := service.RejectTransaction(adminID, transactionID, "incomplete documents")
+		assert.NoError(t, err)
+
+		if err := sqlMock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("InvalidTransition", func(t *testing.T) {
+		transactionID := uint64(12)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(&entity.Transaction{
+			ID: transactionID, Status: entity.TransactionRejected,
+		}, nil)
+
+		sqlMock.ExpectRollback()
+
+		err := service.ApproveTransaction(adminID, transactionID)
+		assert.ErrorIs(t, err, services.ErrInvalidStatusTransition)
+	})
+}
+
+func TestTransactionService_GetInstallments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockInstallmentRepo := mock.NewMockInstallmentRepository(ctrl)
+
+	service := services.NewTransactionService(mockTxRepo, nil, nil, mockUserRepo, mockInstallmentRepo, nil, nil, nil, nil)
+
+	t.Run("Owner", func(t *testing.T) {
+		userID := uint(2)
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(uint64(5)).Return(&entity.Transaction{ID: 5, UserID: userID}, nil)
+		mockInstallmentRepo.EXPECT().FindByTransactionID(uint64(5)).Return([]entity.Installment{{InstallmentNumber: 1}}, nil)
+
+		installments, err := service.GetInstallments(userID, 5)
+		assert.NoError(t, err)
+		assert.Len(t, installments, 1)
+	})
+
+	t.Run("OtherUsersTransaction", func(t *testing.T) {
+		userID := uint(3)
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(uint64(5)).Return(&entity.Transaction{ID: 5, UserID: 2}, nil)
+
+		_, err := service.GetInstallments(userID, 5)
+		assert.ErrorIs(t, err, services.ErrTransactionNotFound)
+	})
+}
+
+func TestTransactionService_CancelTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLimitRepo := mock.NewMockLimitRepository(ctrl)
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
+	mockMutationRepo := mock.NewMockLimitMutationRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockInstallmentRepo := mock.NewMockInstallmentRepository(ctrl)
+
+	db, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open gorm conn: %v", err)
+	}
+
+	service := services.NewTransactionService(mockTxRepo, mockLimitRepo, mockMutationRepo, mockUserRepo, mockInstallmentRepo, nil, nil, nil, gormDB)
+	ownerID := uint(2)
+
+	t.Run("Owner_Pending_ReleasesLimit", func(t *testing.T) {
+		transactionID := uint64(20)
+		pending := &entity.Transaction{
+			ID: transactionID, UserID: ownerID, ContractNumber: "CTR-020", Status: entity.TransactionPending, Tenor: 3,
+			OTR: money.FromRupiah(100000),
+		}
+
+		mockUserRepo.EXPECT().FindByID(ownerID).Return(&entity.User{ID: ownerID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(pending, nil)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM users WHERE id = \\? FOR UPDATE").
+			WithArgs(ownerID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(pending, nil)
+
+		mockInstallmentRepo.EXPECT().WithTx(gomock.Any()).Return(mockInstallmentRepo)
+		mockInstallmentRepo.EXPECT().CancelUnpaidByTransactionID(transactionID).Return(nil)
+
+		mockLimitRepo.EXPECT().WithTx(gomock.Any()).Return(mockLimitRepo)
+		mockLimitRepo.EXPECT().FindByUserID(ownerID).Return([]entity.TenorLimit{
+			{ID: 7, TenorMonth: 3, LimitAmount: money.FromRupiah(500000)},
+		}, nil)
+
+		mockMutationRepo.EXPECT().WithTx(gomock.Any()).Return(mockMutationRepo)
+		mockMutationRepo.EXPECT().Create(gomock.Any()).Do(func(m *entity.LimitMutation) {
+			assert.Equal(t, entity.MutationRelease, m.Action)
+			assert.Equal(t, money.FromRupiah(100000), m.Amount)
+			assert.Contains(t, m.Reason, "CTR-020")
+		}).Return(nil)
+
+		mockTxRepo.EXPECT().Update(gomock.Any()).Do(func(tr *entity.Transaction) {
+			assert.Equal(t, entity.TransactionCancelled, tr.Status)
+			assert.Equal(t, "changed my mind", tr.CancellationReason)
+			assert.Equal(t, ownerID, *tr.ReviewedBy)
+		}).Return(nil)
+
+		sqlMock.ExpectCommit()
+
+		err := service.CancelTransaction(ownerID, transactionID, "changed my mind")
+		assert.NoError(t, err)
+
+		if err := sqlMock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Owner_Approved_NotAllowed", func(t *testing.T) {
+		transactionID := uint64(21)
+		approved := &entity.Transaction{ID: transactionID, UserID: ownerID, Status: entity.TransactionApproved, Tenor: 3}
+
+		mockUserRepo.EXPECT().FindByID(ownerID).Return(&entity.User{ID: ownerID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(approved, nil)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM users WHERE id = \\? FOR UPDATE").
+			WithArgs(ownerID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(approved, nil)
+
+		sqlMock.ExpectRollback()
+
+		err := service.CancelTransaction(ownerID, transactionID, "")
+		assert.ErrorIs(t, err, services.ErrCancellationNotAllowed)
+	})
+
+	t.Run("Admin_Active_InvalidTransition", func(t *testing.T) {
+		adminID := uint(1)
+		transactionID := uint64(22)
+		active := &entity.Transaction{ID: transactionID, UserID: ownerID, Status: entity.TransactionActive, Tenor: 3}
+
+		mockUserRepo.EXPECT().FindByID(adminID).Return(&entity.User{ID: adminID, Role: entity.Role{Name: "admin"}}, nil)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(active, nil)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("SELECT id FROM users WHERE id = \\? FOR UPDATE").
+			WithArgs(ownerID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		sqlMock.ExpectExec("SELECT id FROM transactions WHERE id = \\? FOR UPDATE").
+			WithArgs(transactionID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mockTxRepo.EXPECT().WithTx(gomock.Any()).Return(mockTxRepo)
+		mockTxRepo.EXPECT().FindByID(transactionID).Return(active, nil)
+
+		sqlMock.ExpectRollback()
+
+		err := service.CancelTransaction(adminID, transactionID, "")
+		assert.ErrorIs(t, err, services.ErrInvalidStatusTransition)
+	})
+
+	t.Run("OtherUsersTransaction", func(t *testing.T) {
+		userID := uint(3)
+		mockUserRepo.EXPECT().FindByID(userID).Return(&entity.User{ID: userID, Role: entity.Role{Name: "user"}}, nil)
+		mockTxRepo.EXPECT().FindByID(uint64(23)).Return(&entity.Transaction{ID: 23, UserID: ownerID}, nil)
+
+		err := service.CancelTransaction(userID, 23, "")
+		assert.ErrorIs(t, err, services.ErrTransactionNotFound)
+	})
+}
+
+func TestTransactionService_GetTransactionsByCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxRepo := mock.NewMockTransactionRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+
+	service := services.NewTransactionService(mockTxRepo, nil, nil, mockUserRepo, nil, nil, nil, nil, nil)
+	adminID := uint(1)
+	admin := &entity.User{ID: adminID, Role: entity.Role{Name: "admin"}}
+	createdAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	query := dto.TransactionQuery{PaginationRequest: dto.PaginationRequest{Limit: 2, Pagination: "cursor"}}
+
+	var next string
+
+	t.Run("FirstPage", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByID(adminID).Return(admin, nil)
+		mockTxRepo.EXPECT().FindByCursor(repository.TransactionFilter{}, nil, 2).Return([]entity.Transaction{
+			{ID: 9, CreatedAt: createdAt},
+			{ID: 8, CreatedAt: createdAt},
+		}, true, nil)
+
+		transactions, meta, err := service.GetTransactionsByCursor(adminID, query)
+		assert.NoError(t, err)
+		assert.Len(t, transactions, 2)
+		assert.True(t, meta.HasNext)
+		assert.False(t, meta.HasPrev)
+		assert.Empty(t, meta.PrevCursor)
+
+		next = meta.NextCursor
+		keyset, err := repository.DecodeKeyset(next)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(8), keyset.ID)
+		assert.True(t, createdAt.Equal(keyset.CreatedAt))
+		assert.False(t, keyset.Backward)
+	})
+
+	t.Run("LastPage", func(t *testing.T) {
+		query := query
+		query.Cursor = next
+
+		mockUserRepo.EXPECT().FindByID(adminID).Return(admin, nil)
+		mockTxRepo.EXPECT().FindByCursor(repository.TransactionFilter{}, gomock.Any(), 2).
+			DoAndReturn(func(_ repository.TransactionFilter, cursor *repository.Keyset, _ int) ([]entity.Transaction, bool, error) {
+				assert.Equal(t, uint64(8), cursor.ID)
+				return []entity.Transaction{{ID: 7, CreatedAt: createdAt}}, false, nil
+			})
+
+		_, meta, err := service.GetTransactionsByCursor(adminID, query)
+		assert.NoError(t, err)
+		assert.False(t, meta.HasNext)
+		assert.True(t, meta.HasPrev)
+
+		keyset, err := repository.DecodeKeyset(meta.PrevCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(7), keyset.ID)
+		assert.True(t, keyset.Backward)
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		query := query
+		query.Cursor = "not-a-cursor"
+
+		mockUserRepo.EXPECT().FindByID(adminID).Return(admin, nil)
+
+		_, _, err := service.GetTransactionsByCursor(adminID, query)
+		assert.ErrorIs(t, err, services.ErrInvalidCursor)
+	})
+
+	t.Run("CursorFromAnotherSort", func(t *testing.T) {
+		query := query
+		query.Cursor = next
+		query.Sort = "otr_desc"
+
+		mockUserRepo.EXPECT().FindByID(adminID).Return(admin, nil)
+
+		_, _, err := service.GetTransactionsByCursor(adminID, query)
+		assert.ErrorIs(t, err, services.ErrInvalidCursor)
+	})
 }
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	"github.com/hadi-projects/xyz-finance-go/pkg/cache"
+	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound         = errors.New("user not found")
+	ErrRoleNotFound         = errors.New("role not found")
+	ErrCannotModifySelf     = errors.New("admins cannot change their own role or suspension")
+	ErrUserAlreadySuspended = errors.New("user is already suspended")
+	ErrUserNotSuspended     = errors.New("user is not suspended")
+)
+
+// UserService holds the admin operations on user accounts. Callers revoke the user's sessions
+// after a successful change so existing tokens stop carrying the old role or access.
+type UserService interface {
+	ChangeRole(adminID, userID uint, roleName string) (*entity.User, error)
+	Suspend(adminID, userID uint) error
+	Unsuspend(adminID, userID uint) error
+	UnlockLogin(userID uint) (*entity.User, error)
+}
+
+type userService struct {
+	userRepo   repository.UserRepository
+	roleRepo   repository.RoleRepository
+	permCache  *cache.PermissionCache
+	loginGuard *LoginGuard
+}
+
+func NewUserService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, permCache *cache.PermissionCache, loginGuard *LoginGuard) UserService {
+	return &userService{
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		permCache:  permCache,
+		loginGuard: loginGuard,
+	}
+}
+
+func (s *userService) ChangeRole(adminID, userID uint, roleName string) (*entity.User, error) {
+	user, err := s.findOther(adminID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.roleRepo.FindByName(roleName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+
+	if err := s.userRepo.UpdateRole(user.ID, role.ID); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+	s.invalidatePermissions(user.ID)
+
+	user.RoleID = role.ID
+	user.Role = *role
+	return user, nil
+}
+
+func (s *userService) Suspend(adminID, userID uint) error {
+	user, err := s.findOther(adminID, userID)
+	if err != nil {
+		return err
+	}
+	if user.SuspendedAt != nil {
+		return ErrUserAlreadySuspended
+	}
+
+	now := time.Now()
+	if err := s.userRepo.SetSuspendedAt(user.ID, &now); err != nil {
+		return fmt.Errorf("failed to suspend user: %w", err)
+	}
+	return nil
+}
+
+func (s *userService) Unsuspend(adminID, userID uint) error {
+	user, err := s.findOther(adminID, userID)
+	if err != nil {
+		return err
+	}
+	if user.SuspendedAt == nil {
+		return ErrUserNotSuspended
+	}
+
+	if err := s.userRepo.SetSuspendedAt(user.ID, nil); err != nil {
+		return fmt.Errorf("failed to unsuspend user: %w", err)
+	}
+	return nil
+}
+
+// UnlockLogin lifts a lockout caused by failed logins on the user's account. Lockouts of IPs
+// expire on their own.
+func (s *userService) UnlockLogin(userID uint) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if err := s.loginGuard.Unlock(context.Background(), user.Email); err != nil {
+		return nil, fmt.Errorf("failed to unlock login: %w", err)
+	}
+	return user, nil
+}
+
+// findOther loads the user an admin is acting on; admins may not act on their own account so
+// they cannot lock themselves out
+func (s *userService) findOther(adminID, userID uint) (*entity.User, error) {
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user, nil
+}
+
+func (s *userService) invalidatePermissions(userID uint) {
+	if s.permCache == nil {
+		return
+	}
+	if err := s.permCache.InvalidateUserPermissions(context.Background(), userID); err != nil {
+		logger.SystemLogger.Warn().Err(err).Uint("user_id", userID).Msg("Failed to invalidate cached permissions")
+	}
+}
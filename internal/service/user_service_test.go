@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/internal/entity"
+	"github.com/hadi-projects/xyz-finance-go/internal/repository/mock"
+	services "github.com/hadi-projects/xyz-finance-go/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestUserService_ChangeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockRoleRepo := mock.NewMockRoleRepository(ctrl)
+	service := services.NewUserService(mockUserRepo, mockRoleRepo, nil, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByID(uint(2)).Return(&entity.User{ID: 2, RoleID: 2}, nil)
+		mockRoleRepo.EXPECT().FindByName("admin").Return(&entity.Role{ID: 1, Name: "admin"}, nil)
+		mockUserRepo.EXPECT().UpdateRole(uint(2), uint(1)).Return(nil)
+
+		user, err := service.ChangeRole(1, 2, "admin")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), user.RoleID)
+		assert.Equal(t, "admin", user.Role.Name)
+	})
+
+	t.Run("UnknownRole", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByID(uint(2)).Return(&entity.User{ID: 2, RoleID: 2}, nil)
+		mockRoleRepo.EXPECT().FindByName("owner").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.ChangeRole(1, 2, "owner")
+		assert.ErrorIs(t, err, services.ErrRoleNotFound)
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		_, err := service.ChangeRole(1, 1, "user")
+		assert.ErrorIs(t, err, services.ErrCannotModifySelf)
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByID(uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.ChangeRole(1, 9, "user")
+		assert.ErrorIs(t, err, services.ErrUserNotFound)
+	})
+}
+
+func TestUserService_Suspend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	service := services.NewUserService(mockUserRepo, nil, nil, nil)
+
+	t.Run("Suspend", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByID(uint(2)).Return(&entity.User{ID: 2}, nil)
+		mockUserRepo.EXPECT().SetSuspendedAt(uint(2), gomock.Not(gomock.Nil())).Return(nil)
+
+		assert.NoError(t, service.Suspend(1, 2))
+	})
+
+	t.Run("AlreadySuspended", func(t *testing.T) {
+		suspendedAt := time.Now()
+		mockUserRepo.EXPECT().FindByID(uint(2)).Return(&entity.User{ID: 2, SuspendedAt: &suspendedAt}, nil)
+
+		assert.ErrorIs(t, service.Suspend(1, 2), services.ErrUserAlreadySuspended)
+	})
+
+	t.Run("Unsuspend", func(t *testing.T) {
+		suspendedAt := time.Now()
+		mockUserRepo.EXPECT().FindByID(uint(2)).Return(&entity.User{ID: 2, SuspendedAt: &suspendedAt}, nil)
+		mockUserRepo.EXPECT().SetSuspendedAt(uint(2), gomock.Nil()).Return(nil)
+
+		assert.NoError(t, service.Unsuspend(1, 2))
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		assert.ErrorIs(t, service.Suspend(1, 1), services.ErrCannotModifySelf)
+	})
+}
+
+func TestUserService_UnlockLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	guard := newTestLoginGuard()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	service := services.NewUserService(mockUserRepo, nil, nil, guard)
+
+	for i := 0; i < 3; i++ {
+		_, err := guard.RecordFailure(ctx, "budi@mail.com", "10.0.0.1")
+		assert.NoError(t, err)
+	}
+	assert.ErrorIs(t, guard.Check(ctx, "budi@mail.com", "10.0.0.2"), services.ErrLoginLocked)
+
+	mockUserRepo.EXPECT().FindByID(uint(2)).Return(&entity.User{ID: 2, Email: "budi@mail.com"}, nil)
+
+	user, err := service.UnlockLogin(2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), user.ID)
+	assert.NoError(t, guard.Check(ctx, "budi@mail.com", "10.0.0.2"))
+}
@@ -203,9 +203,6 @@ export default function (data) {
         const payload = JSON.stringify({
             contract_number: `CTR-K6-${__VU}-${__ITER}-${Date.now()}`,
             otr: randomOTR,
-            admin_fee: 10000,
-            installment_amount: Math.floor(randomOTR / randomTenor) + 5000,
-            interest_amount: 10000,
             asset_name: 'K6 Performance Test Asset',
             tenor: randomTenor,
         });
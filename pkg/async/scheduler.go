@@ -0,0 +1,32 @@
+package async
+
+import (
+	"context"
+	"time"
+)
+
+// RunDaily calls job every day at hour:minute (server time) until ctx is cancelled.
+// Runs never overlap: a run that takes longer than a day delays the next one.
+func RunDaily(ctx context.Context, hour, minute int, job func(ctx context.Context, now time.Time)) {
+	for {
+		next := NextDailyRun(time.Now(), hour, minute)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now := <-timer.C:
+			job(ctx, now)
+		}
+	}
+}
+
+// NextDailyRun returns the first hour:minute strictly after now
+func NextDailyRun(now time.Time, hour, minute int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -48,6 +48,23 @@ func (r *RedisClient) Set(ctx context.Context, key string, value interface{}, tt
 	return r.client.Set(ctx, key, value, ttl).Err()
 }
 
+// SetNX stores a value with TTL only if the key does not exist yet and reports whether it was stored
+func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// IncrWithTTL increments a counter and returns its new value. The TTL is only set when the
+// counter has none, so it runs from the first increment (a fixed window).
+func (r *RedisClient) IncrWithTTL(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
 // Delete removes a key from cache
 func (r *RedisClient) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
@@ -32,6 +32,8 @@ func NewMySQLConnection(cfg *config.AppConfig) (*gorm.DB, error) {
 
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
 		Logger: dbLogger,
+		// Unique index violations surface as gorm.ErrDuplicatedKey
+		TranslateError: true,
 	})
 
 	if err != nil {
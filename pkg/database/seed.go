@@ -5,7 +5,9 @@ import (
 
 	"github.com/hadi-projects/xyz-finance-go/internal/entity"
 	"github.com/hadi-projects/xyz-finance-go/internal/repository"
+	"github.com/hadi-projects/xyz-finance-go/pkg/encryption"
 	"github.com/hadi-projects/xyz-finance-go/pkg/logger"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -17,8 +19,18 @@ func SeedRBAC(db *gorm.DB) {
 		{Name: "edit-limit"},
 		{Name: "get-audit-log"},
 		{Name: "get-auth-log"},
+		{Name: "get-transactions"},
+		{Name: "approve-transaction"},
+		{Name: "reject-transaction"},
+		{Name: "disburse-transaction"},
+		{Name: "cancel-transaction"},
+		{Name: "create-payment"},
+		{Name: "settle-transaction"},
+		{Name: "get-limit-mutations"},
+		{Name: "verify-kyc"},
+		{Name: "manage-users"},
 	})
-	seedRole(db, "user", []entity.Permission{{Name: "get-limit"}, {Name: "create-transaction"}, {Name: "get-transactions"}})
+	seedRole(db, "user", []entity.Permission{{Name: "get-limit"}, {Name: "create-transaction"}, {Name: "get-transactions"}, {Name: "get-limit-mutations"}, {Name: "cancel-transaction"}, {Name: "submit-kyc"}})
 
 	logger.SystemLogger.Info().Msg("RBAC Seeding Completed!")
 }
@@ -86,21 +98,21 @@ func seedUser(db *gorm.DB, email string, password []byte, roleId uint) {
 
 func SeedConsumerLimit(db *gorm.DB) {
 	// budi
-	seedLimit(db, 2, 1, 100000)
-	seedLimit(db, 2, 2, 200000)
-	seedLimit(db, 2, 3, 500000)
-	seedLimit(db, 2, 6, 700000)
+	seedLimit(db, 2, 1, money.FromRupiah(100000))
+	seedLimit(db, 2, 2, money.FromRupiah(200000))
+	seedLimit(db, 2, 3, money.FromRupiah(500000))
+	seedLimit(db, 2, 6, money.FromRupiah(700000))
 
 	// annisa
-	seedLimit(db, 3, 1, 1000000)
-	seedLimit(db, 3, 2, 1200000)
-	seedLimit(db, 3, 3, 1500000)
-	seedLimit(db, 3, 6, 2000000)
+	seedLimit(db, 3, 1, money.FromRupiah(1000000))
+	seedLimit(db, 3, 2, money.FromRupiah(1200000))
+	seedLimit(db, 3, 3, money.FromRupiah(1500000))
+	seedLimit(db, 3, 6, money.FromRupiah(2000000))
 
 	logger.SystemLogger.Info().Msg("Consumer Limit Seeding Completed!")
 }
 
-func seedLimit(db *gorm.DB, userId uint, tenor int, limitAmount float64) {
+func seedLimit(db *gorm.DB, userId uint, tenor int, limitAmount money.Money) {
 	// Check if user already has this limit
 	var count int64
 	db.Table("tenor_limits").
@@ -123,14 +135,15 @@ func seedLimit(db *gorm.DB, userId uint, tenor int, limitAmount float64) {
 	}
 }
 
-func SeedConsumer(db *gorm.DB) {
-	seedConsumerData(db, 2, "1234567890123456", "Budi Santoso", "Budi Santoso", "Jakarta", "1990-01-01", 10000000, "budi.webp", "budi.jpeg")
-	seedConsumerData(db, 3, "6543210987654321", "Annisa Putri", "Annisa Putri", "Bandung", "1992-05-15", 15000000, "annisa.jpeg", "annisa.jpeg")
+func SeedConsumer(db *gorm.DB, keys *encryption.KeyRing) {
+	consumerRepo := repository.NewConsumerRepository(db, keys)
+	seedConsumerData(consumerRepo, 2, "3171010101900001", "Budi Santoso", "Budi Santoso", "Jakarta", "1990-01-01", money.FromRupiah(10000000), "budi.webp", "budi.jpeg")
+	seedConsumerData(consumerRepo, 3, "3273015505920002", "Annisa Putri", "Annisa Putri", "Bandung", "1992-05-15", money.FromRupiah(15000000), "annisa.jpeg", "annisa.jpeg")
 
 	logger.SystemLogger.Info().Msg("Consumer Seeding Completed!")
 }
 
-func seedConsumerData(db *gorm.DB, userId uint, nik, fullName, legalName, pob, dob string, salary float64, ktpImage, selfieImage string) {
+func seedConsumerData(consumerRepo repository.ConsumerRepository, userId uint, nik, fullName, legalName, pob, dob string, salary money.Money, ktpImage, selfieImage string) {
 	consumer := entity.Consumer{
 		UserID:       userId,
 		NIK:          nik,
@@ -141,18 +154,22 @@ func seedConsumerData(db *gorm.DB, userId uint, nik, fullName, legalName, pob, d
 		Salary:       salary,
 		KTPImage:     ktpImage,
 		SelfieImage:  selfieImage,
+		KYCStatus:    entity.KYCVerified,
 	}
 
-	var existing entity.Consumer
-	err := db.Where("user_id = ?", userId).First(&existing).Error
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		if err := db.Create(&consumer).Error; err != nil {
+	existing, err := consumerRepo.FindByUserID(userId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.SystemLogger.Error().Err(err).Msgf("Failed to load consumer for user %d", userId)
+		return
+	}
+	if existing == nil {
+		if err := consumerRepo.Create(&consumer); err != nil {
 			logger.SystemLogger.Error().Err(err).Msgf("Failed to create consumer for user %d", userId)
 		}
 	} else {
 		consumer.ID = existing.ID
 		consumer.CreatedAt = existing.CreatedAt
-		if err := db.Save(&consumer).Error; err != nil {
+		if err := consumerRepo.Update(&consumer); err != nil {
 			logger.SystemLogger.Error().Err(err).Msgf("Failed to update consumer for user %d", userId)
 		}
 	}
@@ -0,0 +1,163 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+)
+
+var (
+	ErrUnknownKey        = errors.New("unknown encryption key")
+	ErrMalformedCipher   = errors.New("malformed encrypted value")
+	ErrDecryptionFailure = errors.New("encrypted value cannot be decrypted")
+)
+
+// envelopePrefix marks encrypted column values; anything without it is legacy plaintext
+const envelopePrefix = "enc:v1:"
+
+const dataKeySize = 32
+
+// KeyRing encrypts values with envelope encryption: every value gets a random AES-256-GCM data
+// key, which is stored next to the ciphertext wrapped by a key-encryption key (KEK) of the ring.
+// New values use the active KEK; older KEKs stay in the ring so existing rows remain readable
+// until they are rotated. The ring also computes the blind index used to look up encrypted values.
+type KeyRing struct {
+	keks     map[string]cipher.AEAD
+	active   string
+	indexKey []byte
+}
+
+func NewKeyRing(cfg config.EncryptionConfig) (*KeyRing, error) {
+	if _, ok := cfg.Keys[cfg.ActiveKeyID]; !ok {
+		return nil, fmt.Errorf("%w: active key %q", ErrUnknownKey, cfg.ActiveKeyID)
+	}
+	if len(cfg.BlindIndexKey) < 32 {
+		return nil, errors.New("blind index key must be at least 32 bytes")
+	}
+
+	ring := &KeyRing{keks: make(map[string]cipher.AEAD, len(cfg.Keys)), active: cfg.ActiveKeyID, indexKey: cfg.BlindIndexKey}
+	for id, key := range cfg.Keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid encryption key id %q", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %q must be 32 bytes, got %d", id, len(key))
+		}
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		ring.keks[id] = aead
+	}
+	return ring, nil
+}
+
+// ActiveKeyID is the ID of the KEK new values are encrypted with
+func (k *KeyRing) ActiveKeyID() string {
+	return k.active
+}
+
+// Encrypt seals plaintext under a fresh data key. The associated data (for columns, the column
+// name) must be given again to decrypt, so a value cannot be moved to another column.
+func (k *KeyRing) Encrypt(plaintext, associatedData []byte) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	wrappedKey, err := seal(k.keks[k.active], dataKey, []byte(k.active))
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, plaintext, associatedData)
+	if err != nil {
+		return "", err
+	}
+
+	return envelopePrefix + k.active + ":" + base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt with any KEK still in the ring
+func (k *KeyRing) Decrypt(value string, associatedData []byte) ([]byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, envelopePrefix), ":")
+	if !IsEncrypted(value) || len(parts) != 3 {
+		return nil, ErrMalformedCipher
+	}
+	kek, ok := k.keks[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, parts[0])
+	}
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformedCipher
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedCipher
+	}
+
+	dataKey, err := open(kek, wrappedKey, []byte(parts[0]))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, sealed, associatedData)
+}
+
+// BlindIndex is a keyed hash of value (HMAC-SHA256, hex). Equal values have equal indexes, so
+// it supports unique constraints and exact lookups without revealing the value.
+func (k *KeyRing) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether a column value was written by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns nonce || ciphertext
+func seal(aead cipher.AEAD, plaintext, associatedData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+func open(aead cipher.AEAD, sealed, associatedData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformedCipher
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], associatedData)
+	if err != nil {
+		return nil, ErrDecryptionFailure
+	}
+	return plaintext, nil
+}
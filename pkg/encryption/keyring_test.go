@@ -0,0 +1,108 @@
+package encryption_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/pkg/encryption"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	oldKey   = bytes.Repeat([]byte{1}, 32)
+	newKey   = bytes.Repeat([]byte{2}, 32)
+	indexKey = bytes.Repeat([]byte{3}, 32)
+)
+
+func newKeyRing(t *testing.T, active string, keys map[string][]byte) *encryption.KeyRing {
+	t.Helper()
+	ring, err := encryption.NewKeyRing(config.EncryptionConfig{Keys: keys, ActiveKeyID: active, BlindIndexKey: indexKey})
+	if err != nil {
+		t.Fatalf("failed to create key ring: %v", err)
+	}
+	return ring
+}
+
+func TestKeyRing_EncryptDecrypt(t *testing.T) {
+	ring := newKeyRing(t, "2026-01", map[string][]byte{"2026-01": newKey})
+
+	first, err := ring.Encrypt([]byte("3171010101900001"), []byte("nik"))
+	assert.NoError(t, err)
+	second, err := ring.Encrypt([]byte("3171010101900001"), []byte("nik"))
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, "enc:v1:2026-01:"))
+	assert.NotContains(t, first, "3171010101900001")
+	assert.NotEqual(t, first, second, "every value gets its own data key and nonce")
+
+	plaintext, err := ring.Decrypt(first, []byte("nik"))
+	assert.NoError(t, err)
+	assert.Equal(t, "3171010101900001", string(plaintext))
+
+	t.Run("OtherColumn", func(t *testing.T) {
+		_, err := ring.Decrypt(first, []byte("full_name"))
+		assert.ErrorIs(t, err, encryption.ErrDecryptionFailure)
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		tampered := first[:len(first)-2] + "AA"
+		if tampered == first {
+			tampered = first[:len(first)-2] + "BB"
+		}
+		_, err := ring.Decrypt(tampered, []byte("nik"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := ring.Decrypt("enc:v1:2026-01:abc", []byte("nik"))
+		assert.ErrorIs(t, err, encryption.ErrMalformedCipher)
+		_, err = ring.Decrypt("3171010101900001", []byte("nik"))
+		assert.ErrorIs(t, err, encryption.ErrMalformedCipher)
+	})
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	before := newKeyRing(t, "2025-06", map[string][]byte{"2025-06": oldKey})
+	ciphertext, err := before.Encrypt([]byte("Budi Santoso"), []byte("full_name"))
+	assert.NoError(t, err)
+
+	during := newKeyRing(t, "2026-01", map[string][]byte{"2025-06": oldKey, "2026-01": newKey})
+	plaintext, err := during.Decrypt(ciphertext, []byte("full_name"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Budi Santoso", string(plaintext), "values under the old key stay readable")
+
+	rotated, err := during.Encrypt(plaintext, []byte("full_name"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rotated, "enc:v1:2026-01:"))
+
+	after := newKeyRing(t, "2026-01", map[string][]byte{"2026-01": newKey})
+	_, err = after.Decrypt(ciphertext, []byte("full_name"))
+	assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+	plaintext, err = after.Decrypt(rotated, []byte("full_name"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Budi Santoso", string(plaintext))
+}
+
+func TestKeyRing_BlindIndex(t *testing.T) {
+	ring := newKeyRing(t, "2026-01", map[string][]byte{"2026-01": newKey})
+	rotated := newKeyRing(t, "2025-06", map[string][]byte{"2025-06": oldKey})
+
+	index := ring.BlindIndex("3171010101900001")
+	assert.Len(t, index, 64)
+	assert.Equal(t, index, ring.BlindIndex("3171010101900001"))
+	assert.Equal(t, index, rotated.BlindIndex("3171010101900001"), "the index does not depend on the encryption key")
+	assert.NotEqual(t, index, ring.BlindIndex("3171010101900002"))
+}
+
+func TestNewKeyRing_Invalid(t *testing.T) {
+	_, err := encryption.NewKeyRing(config.EncryptionConfig{Keys: map[string][]byte{"a": newKey}, ActiveKeyID: "b", BlindIndexKey: indexKey})
+	assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+
+	_, err = encryption.NewKeyRing(config.EncryptionConfig{Keys: map[string][]byte{"a": newKey[:16]}, ActiveKeyID: "a", BlindIndexKey: indexKey})
+	assert.Error(t, err)
+
+	_, err = encryption.NewKeyRing(config.EncryptionConfig{Keys: map[string][]byte{"a": newKey}, ActiveKeyID: "a"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package encryption
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the GORM serializer that encrypts a column: `gorm:"serializer:encrypted"`.
+// It supports string fields and types implementing sql.Scanner and driver.Valuer (money.Money).
+const SerializerName = "encrypted"
+
+var ErrKeyRingNotSet = errors.New("encryption key ring is not configured")
+
+// GORM keeps serializers in a global registry and copies them into each parsed schema, so the
+// serializer looks the key ring up when it runs rather than holding it
+var keyRing atomic.Pointer[KeyRing]
+
+func init() {
+	schema.RegisterSerializer(SerializerName, serializer{})
+}
+
+// SetKeyRing configures the key ring encrypted columns are read and written with
+func SetKeyRing(ring *KeyRing) {
+	keyRing.Store(ring)
+}
+
+type serializer struct{}
+
+// Scan decrypts the column into the field. Values that are not encrypted yet (rows written
+// before encryption was enabled) are read as plaintext until the key rotation rewrites them.
+func (serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	value := reflect.New(field.FieldType)
+	if dbValue != nil {
+		var raw string
+		switch v := dbValue.(type) {
+		case []byte:
+			raw = string(v)
+		case string:
+			raw = v
+		default:
+			raw = fmt.Sprint(v)
+		}
+
+		if IsEncrypted(raw) {
+			ring := keyRing.Load()
+			if ring == nil {
+				return ErrKeyRingNotSet
+			}
+			plaintext, err := ring.Decrypt(raw, []byte(field.DBName))
+			if err != nil {
+				return fmt.Errorf("decrypt %s: %w", field.DBName, err)
+			}
+			raw = string(plaintext)
+		}
+
+		switch target := value.Interface().(type) {
+		case sql.Scanner:
+			if err := target.Scan(raw); err != nil {
+				return err
+			}
+		case *string:
+			*target = raw
+		default:
+			return fmt.Errorf("encrypted column %s: unsupported type %s", field.DBName, field.FieldType)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(value.Elem())
+	return nil
+}
+
+// Value encrypts the field's value with the active key
+func (serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	ring := keyRing.Load()
+	if ring == nil {
+		return nil, ErrKeyRingNotSet
+	}
+
+	var plaintext string
+	switch v := fieldValue.(type) {
+	case string:
+		plaintext = v
+	case driver.Valuer:
+		value, err := v.Value()
+		if err != nil {
+			return nil, err
+		}
+		plaintext = fmt.Sprint(value)
+	default:
+		return nil, fmt.Errorf("encrypted column %s: unsupported type %T", field.DBName, fieldValue)
+	}
+
+	return ring.Encrypt([]byte(plaintext), []byte(field.DBName))
+}
@@ -0,0 +1,99 @@
+package encryption_test
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hadi-projects/xyz-finance-go/pkg/encryption"
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type encryptedRecord struct {
+	ID     uint
+	Name   string      `gorm:"serializer:encrypted"`
+	Salary money.Money `gorm:"serializer:encrypted"`
+}
+
+// capture is a sqlmock argument that records the value written
+type capture struct {
+	value string
+}
+
+func (c *capture) Match(v driver.Value) bool {
+	c.value, _ = v.(string)
+	return true
+}
+
+func TestSerializer(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open gorm conn: %v", err)
+	}
+
+	ring := newKeyRing(t, "2026-01", map[string][]byte{"2026-01": newKey})
+	encryption.SetKeyRing(ring)
+	defer encryption.SetKeyRing(nil)
+
+	t.Run("Write", func(t *testing.T) {
+		name, salary := &capture{}, &capture{}
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("INSERT INTO `encrypted_records`").WithArgs(name, salary).WillReturnResult(sqlmock.NewResult(1, 1))
+		sqlMock.ExpectCommit()
+
+		err := gormDB.Create(&encryptedRecord{Name: "Budi Santoso", Salary: money.FromRupiah(10000000)}).Error
+		assert.NoError(t, err)
+
+		plaintext, err := ring.Decrypt(name.value, []byte("name"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Budi Santoso", string(plaintext))
+		plaintext, err = ring.Decrypt(salary.value, []byte("salary"))
+		assert.NoError(t, err)
+		assert.Equal(t, "10000000.00", string(plaintext))
+	})
+
+	t.Run("Read", func(t *testing.T) {
+		name, _ := ring.Encrypt([]byte("Annisa Putri"), []byte("name"))
+		salary, _ := ring.Encrypt([]byte("15000000.00"), []byte("salary"))
+		sqlMock.ExpectQuery("SELECT \\* FROM `encrypted_records`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "salary"}).AddRow(1, name, salary))
+
+		var record encryptedRecord
+		assert.NoError(t, gormDB.First(&record).Error)
+		assert.Equal(t, "Annisa Putri", record.Name)
+		assert.Equal(t, money.FromRupiah(15000000), record.Salary)
+	})
+
+	t.Run("LegacyPlaintext", func(t *testing.T) {
+		sqlMock.ExpectQuery("SELECT \\* FROM `encrypted_records`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "salary"}).AddRow(1, "Budi Santoso", "10000000.00"))
+
+		var record encryptedRecord
+		assert.NoError(t, gormDB.First(&record).Error)
+		assert.Equal(t, "Budi Santoso", record.Name)
+		assert.Equal(t, money.FromRupiah(10000000), record.Salary)
+	})
+
+	t.Run("MovedBetweenColumns", func(t *testing.T) {
+		name, _ := ring.Encrypt([]byte("Annisa Putri"), []byte("name"))
+		sqlMock.ExpectQuery("SELECT \\* FROM `encrypted_records`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "salary"}).AddRow(1, name, name))
+
+		var record encryptedRecord
+		assert.ErrorIs(t, gormDB.First(&record).Error, encryption.ErrDecryptionFailure)
+	})
+
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,164 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hadi-projects/xyz-finance-go/config"
+)
+
+var (
+	ErrUnknownKey          = errors.New("unknown signing key")
+	ErrUnexpectedAlgorithm = errors.New("unexpected signing algorithm")
+)
+
+// minRSABits is the smallest RSA modulus accepted for RS256
+const minRSABits = 2048
+
+func init() {
+	// iat, exp and nbf carry milliseconds, so a token issued right after a revocation cutoff can
+	// be told apart from the tokens issued before it within the same second
+	jwt.TimePrecision = time.Millisecond
+}
+
+// Algorithms are the only signing algorithms tokens are accepted with
+var Algorithms = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+
+// Key is one signing key of a KeySet. The algorithm follows from the key type: RS256 for RSA
+// keys, EdDSA for Ed25519 keys. Keys loaded from a public key can only verify.
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// CanSign reports whether the key holds private key material
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+// KeySet signs access tokens with its active key and verifies them with any of its keys, picked
+// by the token's kid header. Rotating keys: add the new key and make it active; keep the old key
+// (its public half is enough) until every token signed with it has expired, then remove it.
+type KeySet struct {
+	keys   map[string]*Key
+	order  []string
+	active *Key
+}
+
+func NewKeySet(cfg config.JWTConfig) (*KeySet, error) {
+	if len(cfg.SigningKeys) == 0 {
+		return nil, errors.New("at least one JWT signing key is required")
+	}
+
+	set := &KeySet{keys: make(map[string]*Key, len(cfg.SigningKeys))}
+	for _, signingKey := range cfg.SigningKeys {
+		if _, ok := set.keys[signingKey.ID]; ok {
+			return nil, fmt.Errorf("duplicate JWT key id %q", signingKey.ID)
+		}
+		key, err := ParseKey(signingKey.ID, signingKey.PEM)
+		if err != nil {
+			return nil, err
+		}
+		set.keys[key.ID] = key
+		set.order = append(set.order, key.ID)
+	}
+
+	active, ok := set.keys[cfg.ActiveKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: active key %q", ErrUnknownKey, cfg.ActiveKeyID)
+	}
+	if !active.CanSign() {
+		return nil, fmt.Errorf("active JWT key %q is a public key and cannot sign", cfg.ActiveKeyID)
+	}
+	set.active = active
+	return set, nil
+}
+
+// ParseKey reads a PEM encoded private key (PKCS#8, or PKCS#1 for RSA) or public key (PKIX)
+func ParseKey(id string, data []byte) (*Key, error) {
+	if id == "" {
+		return nil, errors.New("JWT key id must not be empty")
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("JWT key %q is not PEM encoded", id)
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("JWT key %q has unsupported PEM type %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("JWT key %q: %w", id, err)
+	}
+
+	key := &Key{ID: id}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("JWT key %q must be an RSA or Ed25519 key, got %T", id, parsed)
+	}
+
+	if rsaKey, ok := key.public.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("JWT key %q: RSA keys must be at least %d bits", id, minRSABits)
+	}
+	return key, nil
+}
+
+// ActiveKeyID is the kid new tokens are signed with
+func (s *KeySet) ActiveKeyID() string {
+	return s.active.ID
+}
+
+// Sign signs claims with the active key, setting the kid header
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.active.Method, claims)
+	token.Header["kid"] = s.active.ID
+	return token.SignedString(s.active.private)
+}
+
+// Parse verifies a token and fills claims. Only RS256 and EdDSA are accepted, the kid must name
+// a key of the set and the token's algorithm must be that key's algorithm; tokens without an
+// expiry are rejected.
+func (s *KeySet) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, s.keyFunc,
+		jwt.WithValidMethods(Algorithms),
+		jwt.WithExpirationRequired(),
+	)
+}
+
+func (s *KeySet) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("%w: %s for key %q, expected %s", ErrUnexpectedAlgorithm, token.Method.Alg(), kid, key.Method.Alg())
+	}
+	return key.public, nil
+}
@@ -0,0 +1,206 @@
+package jwtkeys_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hadi-projects/xyz-finance-go/config"
+	"github.com/hadi-projects/xyz-finance-go/pkg/jwtkeys"
+	"github.com/stretchr/testify/assert"
+)
+
+func privatePEM(t *testing.T, key any) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to encode private key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func publicPEM(t *testing.T, key any) []byte {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("failed to encode public key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func newKeySet(t *testing.T, active string, keys ...config.JWTSigningKey) *jwtkeys.KeySet {
+	set, err := jwtkeys.NewKeySet(config.JWTConfig{SigningKeys: keys, ActiveKeyID: active})
+	if err != nil {
+		t.Fatalf("failed to create key set: %v", err)
+	}
+	return set
+}
+
+func testClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   "2",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+func TestKeySet_SignAndParse(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		key  any
+		alg  string
+	}{
+		{"RS256", rsaKey, "RS256"},
+		{"EdDSA", edKey, "EdDSA"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			set := newKeySet(t, "k1", config.JWTSigningKey{ID: "k1", PEM: privatePEM(t, tc.key)})
+
+			signed, err := set.Sign(testClaims())
+			assert.NoError(t, err)
+
+			var claims jwt.RegisteredClaims
+			token, err := set.Parse(signed, &claims)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.alg, token.Method.Alg())
+			assert.Equal(t, "k1", token.Header["kid"])
+			assert.Equal(t, "2", claims.Subject)
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	_, oldKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	before := newKeySet(t, "2026-01", config.JWTSigningKey{ID: "2026-01", PEM: privatePEM(t, oldKey)})
+	inFlight, err := before.Sign(testClaims())
+	assert.NoError(t, err)
+
+	// The new key is active; the old one is kept as a public key until its tokens expire
+	after := newKeySet(t, "2026-07",
+		config.JWTSigningKey{ID: "2026-01", PEM: publicPEM(t, oldKey.Public())},
+		config.JWTSigningKey{ID: "2026-07", PEM: privatePEM(t, newKey)},
+	)
+	assert.Equal(t, "2026-07", after.ActiveKeyID())
+
+	_, err = after.Parse(inFlight, &jwt.RegisteredClaims{})
+	assert.NoError(t, err, "tokens signed with the previous key stay valid")
+
+	signed, err := after.Sign(testClaims())
+	assert.NoError(t, err)
+	token, err := after.Parse(signed, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-07", token.Header["kid"])
+
+	retired := newKeySet(t, "2026-07", config.JWTSigningKey{ID: "2026-07", PEM: privatePEM(t, newKey)})
+	_, err = retired.Parse(inFlight, &jwt.RegisteredClaims{})
+	assert.ErrorIs(t, err, jwtkeys.ErrUnknownKey, "tokens of a removed key are rejected")
+}
+
+func TestKeySet_StrictAlgorithm(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	set := newKeySet(t, "ed", config.JWTSigningKey{ID: "ed", PEM: privatePEM(t, edKey)})
+
+	t.Run("AlgorithmOfAnotherKeyType", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims())
+		token.Header["kid"] = "ed"
+		signed, err := token.SignedString(rsaKey)
+		assert.NoError(t, err)
+
+		_, err = set.Parse(signed, &jwt.RegisteredClaims{})
+		assert.ErrorIs(t, err, jwtkeys.ErrUnexpectedAlgorithm)
+	})
+
+	t.Run("HS256", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+		token.Header["kid"] = "ed"
+		signed, err := token.SignedString([]byte("secret"))
+		assert.NoError(t, err)
+
+		_, err = set.Parse(signed, &jwt.RegisteredClaims{})
+		assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	})
+
+	t.Run("MissingKid", func(t *testing.T) {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, testClaims()).SignedString(edKey)
+		assert.NoError(t, err)
+
+		_, err = set.Parse(signed, &jwt.RegisteredClaims{})
+		assert.ErrorIs(t, err, jwtkeys.ErrUnknownKey)
+	})
+
+	t.Run("MissingExpiry", func(t *testing.T) {
+		signed, err := set.Sign(jwt.RegisteredClaims{Subject: "2"})
+		assert.NoError(t, err)
+
+		_, err = set.Parse(signed, &jwt.RegisteredClaims{})
+		assert.ErrorIs(t, err, jwt.ErrTokenRequiredClaimMissing)
+	})
+}
+
+func TestNewKeySet_Invalid(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	smallRSA, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		cfg  config.JWTConfig
+	}{
+		{"NoKeys", config.JWTConfig{}},
+		{"UnknownActive", config.JWTConfig{ActiveKeyID: "k2", SigningKeys: []config.JWTSigningKey{{ID: "k1", PEM: privatePEM(t, edKey)}}}},
+		{"PublicActive", config.JWTConfig{ActiveKeyID: "k1", SigningKeys: []config.JWTSigningKey{{ID: "k1", PEM: publicPEM(t, edKey.Public())}}}},
+		{"DuplicateID", config.JWTConfig{ActiveKeyID: "k1", SigningKeys: []config.JWTSigningKey{{ID: "k1", PEM: privatePEM(t, edKey)}, {ID: "k1", PEM: privatePEM(t, edKey)}}}},
+		{"SmallRSA", config.JWTConfig{ActiveKeyID: "k1", SigningKeys: []config.JWTSigningKey{{ID: "k1", PEM: privatePEM(t, smallRSA)}}}},
+		{"NotPEM", config.JWTConfig{ActiveKeyID: "k1", SigningKeys: []config.JWTSigningKey{{ID: "k1", PEM: []byte("secret")}}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := jwtkeys.NewKeySet(tc.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	set := newKeySet(t, "ed",
+		config.JWTSigningKey{ID: "rsa", PEM: publicPEM(t, &rsaKey.PublicKey)},
+		config.JWTSigningKey{ID: "ed", PEM: privatePEM(t, edKey)},
+	)
+
+	jwks := set.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+
+	rsaJWK := jwks.Keys[0]
+	assert.Equal(t, "RSA", rsaJWK.KeyType)
+	assert.Equal(t, "rsa", rsaJWK.KeyID)
+	assert.Equal(t, "RS256", rsaJWK.Algorithm)
+	assert.Equal(t, "sig", rsaJWK.Use)
+	assert.Equal(t, "AQAB", rsaJWK.E)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()), rsaJWK.N)
+
+	edJWK := jwks.Keys[1]
+	assert.Equal(t, "OKP", edJWK.KeyType)
+	assert.Equal(t, "Ed25519", edJWK.Curve)
+	assert.Equal(t, "EdDSA", edJWK.Algorithm)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(edPublic), edJWK.X)
+}
@@ -0,0 +1,246 @@
+// Package money provides an exact decimal type for rupiah amounts.
+//
+// Amounts are held as an integer number of sen (1/100 rupiah), so sums, differences and
+// comparisons are exact. Rounding only happens when an amount is multiplied by a rate or divided,
+// and always rounds half away from zero to the nearest sen. Parsing never rounds: input with more
+// than two decimal places is rejected.
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in sen. The zero value is Rp0.
+type Money int64
+
+// Scale is the number of sen in one rupiah
+const Scale = 100
+
+// maxDigits keeps parsed amounts within int64 and within decimal(15,2) columns with room to spare
+const maxDigits = 17
+
+var ErrInvalidAmount = errors.New("invalid money amount")
+
+// FromRupiah returns a whole rupiah amount
+func FromRupiah(rupiah int64) Money {
+	return Money(rupiah * Scale)
+}
+
+// FromSen returns an amount in sen
+func FromSen(sen int64) Money {
+	return Money(sen)
+}
+
+// FromFloat converts a float, rounding half away from zero to the sen.
+// Only for values that are floats by nature (e.g. results of rate formulas); use Parse for input.
+func FromFloat(f float64) Money {
+	return Money(math.Round(f * Scale))
+}
+
+// Parse reads a decimal string such as "1500000", "-12.5" or "99.99"
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" && (!hasFrac || frac == "") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("%w: %q has more than 2 decimal places", ErrInvalidAmount, s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	digits := whole + frac + strings.Repeat("0", 2-len(frac))
+	if len(digits) > maxDigits {
+		return 0, fmt.Errorf("%w: %q is too large", ErrInvalidAmount, s)
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+	}
+
+	sen, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if negative {
+		sen = -sen
+	}
+	return Money(sen), nil
+}
+
+// MustParse is Parse for constants; it panics on invalid input
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Sen returns the amount in sen
+func (m Money) Sen() int64 {
+	return int64(m)
+}
+
+// Float64 returns the amount in rupiah as a float, for logging and rate formulas only
+func (m Money) Float64() float64 {
+	return float64(m) / Scale
+}
+
+// MulRate multiplies the amount by rate (e.g. 0.12 for 12%) and rounds to the sen
+func (m Money) MulRate(rate float64) Money {
+	return Money(math.Round(float64(m) * rate))
+}
+
+// Split divides the amount into n parts of whole sen. Parts are equal except the last one,
+// which takes the remainder so the parts always add up to m.
+func (m Money) Split(n int) []Money {
+	if n <= 0 {
+		return nil
+	}
+	base := m / Money(n)
+	parts := make([]Money, n)
+	for i := 0; i < n-1; i++ {
+		parts[i] = base
+	}
+	parts[n-1] = m - base*Money(n-1)
+	return parts
+}
+
+// Min returns the smaller of a and b
+func Min(a, b Money) Money {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Max returns the larger of a and b
+func Max(a, b Money) Money {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// String formats the amount with exactly two decimals, e.g. "1500000.00"
+func (m Money) String() string {
+	sign := ""
+	sen := int64(m)
+	if sen < 0 {
+		sign = "-"
+		sen = -sen
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, sen/Scale, sen%Scale)
+}
+
+// MarshalJSON encodes the amount as a JSON number without trailing zeros, e.g. 1500000 or 99.5
+func (m Money) MarshalJSON() ([]byte, error) {
+	s := m.String()
+	s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-" {
+		s = "0"
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidAmount, s)
+		}
+		s = unquoted
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// UnmarshalParam lets gin bind query and form parameters such as ?min_otr=1500000 as rupiah
+func (m *Money) UnmarshalParam(param string) error {
+	parsed, err := Parse(param)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		*m = FromRupiah(v)
+		return nil
+	case float64:
+		*m = FromFloat(v)
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidAmount, value)
+	}
+}
+
+// scanString parses a database decimal. SUMs and other expressions may come back with more than
+// two decimals; those are rounded half away from zero to the sen instead of rejected.
+func (m *Money) scanString(s string) error {
+	whole, frac, ok := strings.Cut(s, ".")
+	if !ok || len(frac) <= 2 {
+		parsed, err := Parse(s)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	parsed, err := Parse(whole + "." + frac[:2])
+	if err != nil {
+		return err
+	}
+	if frac[2] >= '5' {
+		if strings.HasPrefix(whole, "-") {
+			parsed--
+		} else {
+			parsed++
+		}
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, writing the amount as an exact decimal string
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// GormDataType makes money columns DECIMAL(15,2) unless the field tag says otherwise
+func (Money) GormDataType() string {
+	return "decimal(15,2)"
+}
@@ -0,0 +1,84 @@
+package money_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]money.Money{
+		"1500000":    money.FromRupiah(1500000),
+		"99.5":       money.FromSen(9950),
+		"99.99":      money.FromSen(9999),
+		"-12.05":     money.FromSen(-1205),
+		".5":         money.FromSen(50),
+		" 10000.00 ": money.FromRupiah(10000),
+	}
+	for input, expected := range cases {
+		got, err := money.Parse(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, got, input)
+	}
+
+	for _, input := range []string{"", "abc", "1.005", "1e6", "1,5", "-", "."} {
+		_, err := money.Parse(input)
+		assert.ErrorIs(t, err, money.ErrInvalidAmount, input)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var payload struct {
+		Amount money.Money `json:"amount"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount": 107666.66}`), &payload))
+	assert.Equal(t, money.FromSen(10766666), payload.Amount)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount": "2500.5"}`), &payload))
+	assert.Equal(t, money.FromSen(250050), payload.Amount)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": 0.001}`), &payload))
+
+	for amount, expected := range map[money.Money]string{
+		money.FromRupiah(1500000): `{"amount":1500000}`,
+		money.FromSen(9950):       `{"amount":99.5}`,
+		money.FromSen(-1205):      `{"amount":-12.05}`,
+		0:                         `{"amount":0}`,
+	} {
+		payload.Amount = amount
+		data, err := json.Marshal(payload)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(data))
+	}
+}
+
+func TestScanAndValue(t *testing.T) {
+	var m money.Money
+
+	assert.NoError(t, m.Scan([]byte("1500000.50")))
+	assert.Equal(t, money.FromSen(150000050), m)
+
+	// Aggregates may carry extra decimals; they are rounded half away from zero
+	assert.NoError(t, m.Scan([]byte("10.125")))
+	assert.Equal(t, money.FromSen(1013), m)
+	assert.NoError(t, m.Scan([]byte("-10.125")))
+	assert.Equal(t, money.FromSen(-1013), m)
+
+	assert.NoError(t, m.Scan(nil))
+	assert.Equal(t, money.Money(0), m)
+
+	value, err := money.FromSen(150000050).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "1500000.50", value)
+}
+
+func TestArithmetic(t *testing.T) {
+	assert.Equal(t, []money.Money{3333, 3333, 3334}, money.FromRupiah(100).Split(3))
+	assert.Equal(t, money.FromRupiah(120), money.FromRupiah(1000).MulRate(0.12))
+	assert.Equal(t, money.FromSen(2), money.FromSen(3).MulRate(0.5)) // 1.5 sen rounds away from zero
+	assert.Equal(t, money.FromSen(-2), money.FromSen(-3).MulRate(0.5))
+	assert.Equal(t, "0.01", (money.MustParse("0.1") + money.MustParse("0.2") - money.MustParse("0.29")).String())
+}
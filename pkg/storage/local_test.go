@@ -0,0 +1,50 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewLocal(t.TempDir())
+
+	t.Run("PutGetDelete", func(t *testing.T) {
+		err := store.Put(ctx, "ktp/budi.png", strings.NewReader("image"), 5, "image/png")
+		assert.NoError(t, err)
+
+		object, err := store.Get(ctx, "ktp/budi.png")
+		if !assert.NoError(t, err) {
+			return
+		}
+		content, _ := io.ReadAll(object.Body)
+		object.Body.Close()
+		assert.Equal(t, "image", string(content))
+		assert.Equal(t, int64(5), object.Size)
+		assert.Equal(t, "image/png", object.ContentType)
+
+		assert.NoError(t, store.Delete(ctx, "ktp/budi.png"))
+		_, err = store.Get(ctx, "ktp/budi.png")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		assert.NoError(t, store.Delete(ctx, "ktp/budi.png"), "deleting a missing object is not an error")
+	})
+
+	t.Run("InvalidKeys", func(t *testing.T) {
+		for _, key := range []string{"", "/etc/passwd", "../config.env", "ktp/../../config.env", "ktp//budi.png"} {
+			_, err := store.Get(ctx, key)
+			assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+			assert.ErrorIs(t, store.Put(ctx, key, strings.NewReader("x"), 1, ""), storage.ErrInvalidKey, key)
+		}
+	})
+
+	t.Run("NoSignedURLs", func(t *testing.T) {
+		_, err := store.SignedURL(ctx, "ktp/budi.png", time.Minute)
+		assert.ErrorIs(t, err, storage.ErrSignedURLUnsupported)
+	})
+}
@@ -0,0 +1,275 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	amzDateFormat   = "20060102T150405Z"
+	maxPresignTTL   = 7 * 24 * time.Hour
+)
+
+// S3 stores objects in a bucket of an S3-compatible object store (AWS S3, MinIO, ...), signing
+// requests with AWS Signature Version 4. Payloads are sent unsigned so uploads can be streamed.
+type S3 struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	client    *http.Client
+	now       func() time.Time
+}
+
+func NewS3(cfg config.StorageConfig) (*S3, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.S3Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.S3Endpoint)
+	}
+	if cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+		return nil, errors.New("S3 bucket, access key and secret key are required")
+	}
+	region := cfg.S3Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &S3{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    cfg.S3Bucket,
+		accessKey: cfg.S3AccessKey,
+		secretKey: cfg.S3SecretKey,
+		pathStyle: cfg.S3PathStyle,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		now:       time.Now,
+	}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	req, canonicalURI, err := s.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req, canonicalURI)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error("put", key, resp)
+	}
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (*Object, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	req, canonicalURI, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, canonicalURI)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return &Object{Body: resp.Body, Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, s3Error("get", key, resp)
+	}
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	req, canonicalURI, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, canonicalURI)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s3Error("delete", key, resp)
+	}
+	return nil
+}
+
+// SignedURL returns a presigned GET URL (query string authentication)
+func (s *S3) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	if ttl < time.Second || ttl > maxPresignTTL {
+		return "", fmt.Errorf("signed URL lifetime must be between 1s and %s", maxPresignTTL)
+	}
+	return s.presign(key, ttl, s.now()), nil
+}
+
+func (s *S3) presign(key string, ttl time.Duration, t time.Time) string {
+	u, canonicalURI := s.location(key)
+	amzDate := t.UTC().Format(amzDateFormat)
+	scope := s.scope(t)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", s.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(ttl/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+	canonicalQuery := canonicalQueryString(query)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet, canonicalURI, canonicalQuery, "host:" + u.Host + "\n", "host", unsignedPayload,
+	}, "\n")
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + s.signature(t, canonicalRequest)
+	return u.String()
+}
+
+func (s *S3) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, string, error) {
+	u, canonicalURI := s.location(key)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	return req, canonicalURI, err
+}
+
+func (s *S3) do(req *http.Request, canonicalURI string) (*http.Response, error) {
+	s.sign(req, canonicalURI, unsignedPayload, s.now())
+	return s.client.Do(req)
+}
+
+// sign adds the SigV4 Authorization header, signing the host and every header already set
+func (s *S3) sign(req *http.Request, canonicalURI, payloadHash string, t time.Time) {
+	req.Header.Set("X-Amz-Date", t.UTC().Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method, canonicalURI, canonicalQueryString(req.URL.Query()), canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.accessKey, s.scope(t), signedHeaders, s.signature(t, canonicalRequest)))
+}
+
+func (s *S3) signature(t time.Time, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm, t.UTC().Format(amzDateFormat), s.scope(t), hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), t.UTC().Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func (s *S3) scope(t time.Time) string {
+	return t.UTC().Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// location returns the object URL and its canonical (URI-encoded) path. Path-style addressing
+// (endpoint/bucket/key) is what MinIO and most self-hosted stores expect; AWS prefers the
+// virtual-hosted style (bucket.endpoint/key).
+func (s *S3) location(key string) (*url.URL, string) {
+	host := s.endpoint.Host
+	canonicalURI := s.endpoint.Path + "/" + uriEncode(key, false)
+	if s.pathStyle {
+		canonicalURI = s.endpoint.Path + "/" + uriEncode(s.bucket, true) + "/" + uriEncode(key, false)
+	} else {
+		host = s.bucket + "." + host
+	}
+
+	u, _ := url.Parse(s.endpoint.Scheme + "://" + host + canonicalURI)
+	return u, canonicalURI
+}
+
+func canonicalQueryString(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything except the unreserved characters, as SigV4 requires
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3Error(op, key string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 %s %s: %s: %s", op, key, resp.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/config"
+)
+
+var (
+	ErrNotFound             = errors.New("object not found")
+	ErrInvalidKey           = errors.New("invalid object key")
+	ErrSignedURLUnsupported = errors.New("storage backend does not issue signed URLs")
+)
+
+// Object is an open stored file; the caller must close Body
+type Object struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+}
+
+// Storage keeps uploaded files under slash-separated keys such as "ktp/<name>.jpg"
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (*Object, error)
+	// Delete removes the object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that downloads the object without credentials until ttl passes,
+	// or ErrSignedURLUnsupported when the backend cannot serve files itself
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// New returns the backend selected by cfg.Driver
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocal(cfg.LocalDir), nil
+	case "s3":
+		return NewS3(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// checkKey rejects keys that are empty, absolute or not in clean form, so no key can point
+// outside the storage root
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
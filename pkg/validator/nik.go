@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidNIK = errors.New("invalid NIK")
+
+// nikProvinces are the province codes (kode provinsi Kemendagri) a NIK may start with
+var nikProvinces = map[string]string{
+	"11": "Aceh", "12": "Sumatera Utara", "13": "Sumatera Barat", "14": "Riau", "15": "Jambi",
+	"16": "Sumatera Selatan", "17": "Bengkulu", "18": "Lampung", "19": "Kepulauan Bangka Belitung",
+	"21": "Kepulauan Riau", "31": "DKI Jakarta", "32": "Jawa Barat", "33": "Jawa Tengah",
+	"34": "DI Yogyakarta", "35": "Jawa Timur", "36": "Banten", "51": "Bali", "52": "Nusa Tenggara Barat",
+	"53": "Nusa Tenggara Timur", "61": "Kalimantan Barat", "62": "Kalimantan Tengah",
+	"63": "Kalimantan Selatan", "64": "Kalimantan Timur", "65": "Kalimantan Utara", "71": "Sulawesi Utara",
+	"72": "Sulawesi Tengah", "73": "Sulawesi Selatan", "74": "Sulawesi Tenggara", "75": "Gorontalo",
+	"76": "Sulawesi Barat", "81": "Maluku", "82": "Maluku Utara", "91": "Papua", "92": "Papua Barat",
+	"93": "Papua Selatan", "94": "Papua Tengah", "95": "Papua Pegunungan", "96": "Papua Barat Daya",
+}
+
+// NIK is a parsed Nomor Induk Kependudukan: PPRRDD DDMMYY SSSS, where PP is the province,
+// RR the regency (kabupaten/kota), DD the district (kecamatan), DDMMYY the birth date (day + 40
+// for women) and SSSS a serial number.
+type NIK struct {
+	Number       string
+	ProvinceCode string // 2 digits
+	Province     string
+	RegencyCode  string // 4 digits, including the province
+	DistrictCode string // 6 digits, including the province and regency
+	BirthDate    time.Time
+	Female       bool
+	Serial       string
+}
+
+// ParseNIK parses and structurally validates a NIK. The birth year is encoded with two digits;
+// years not after the current year are read as 20YY, others as 19YY.
+func ParseNIK(nik string) (NIK, error) {
+	if len(nik) != 16 {
+		return NIK{}, fmt.Errorf("%w: must be 16 digits", ErrInvalidNIK)
+	}
+	for _, c := range nik {
+		if c < '0' || c > '9' {
+			return NIK{}, fmt.Errorf("%w: must be 16 digits", ErrInvalidNIK)
+		}
+	}
+
+	province, ok := nikProvinces[nik[0:2]]
+	if !ok {
+		return NIK{}, fmt.Errorf("%w: unknown province code %s", ErrInvalidNIK, nik[0:2])
+	}
+	if nik[2:4] == "00" {
+		return NIK{}, fmt.Errorf("%w: invalid regency code %s", ErrInvalidNIK, nik[2:4])
+	}
+	if nik[4:6] == "00" {
+		return NIK{}, fmt.Errorf("%w: invalid district code %s", ErrInvalidNIK, nik[4:6])
+	}
+	if nik[12:16] == "0000" {
+		return NIK{}, fmt.Errorf("%w: invalid serial number", ErrInvalidNIK)
+	}
+
+	day, _ := strconv.Atoi(nik[6:8])
+	month, _ := strconv.Atoi(nik[8:10])
+	year, _ := strconv.Atoi(nik[10:12])
+
+	female := day > 40
+	if female {
+		day -= 40
+	}
+	if year += 2000; year > time.Now().Year() {
+		year -= 100
+	}
+
+	birthDate := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	// time.Date normalises out-of-range values, so 31-02 would silently become 03-03
+	if day < 1 || month < 1 || month > 12 || birthDate.Day() != day || birthDate.Month() != time.Month(month) {
+		return NIK{}, fmt.Errorf("%w: invalid birth date %s", ErrInvalidNIK, nik[6:12])
+	}
+
+	return NIK{
+		Number:       nik,
+		ProvinceCode: nik[0:2],
+		Province:     province,
+		RegencyCode:  nik[0:4],
+		DistrictCode: nik[0:6],
+		BirthDate:    birthDate,
+		Female:       female,
+		Serial:       nik[12:16],
+	}, nil
+}
+
+// MatchesBirthDate reports whether the NIK encodes dateOfBirth. Only the last two digits of the
+// year are compared, as the NIK does not carry the century.
+func (n NIK) MatchesBirthDate(dateOfBirth time.Time) bool {
+	return n.BirthDate.Day() == dateOfBirth.Day() &&
+		n.BirthDate.Month() == dateOfBirth.Month() &&
+		n.BirthDate.Year()%100 == dateOfBirth.Year()%100
+}
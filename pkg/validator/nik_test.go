@@ -0,0 +1,64 @@
+package validator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hadi-projects/xyz-finance-go/pkg/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNIK(t *testing.T) {
+	nik, err := validator.ParseNIK("3171010101900001")
+	assert.NoError(t, err)
+	assert.Equal(t, "31", nik.ProvinceCode)
+	assert.Equal(t, "DKI Jakarta", nik.Province)
+	assert.Equal(t, "3171", nik.RegencyCode)
+	assert.Equal(t, "317101", nik.DistrictCode)
+	assert.Equal(t, time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), nik.BirthDate)
+	assert.False(t, nik.Female)
+	assert.Equal(t, "0001", nik.Serial)
+
+	// Women have 40 added to the day of birth
+	nik, err = validator.ParseNIK("3273015505920002")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(1992, 5, 15, 0, 0, 0, 0, time.UTC), nik.BirthDate)
+	assert.True(t, nik.Female)
+
+	// Two-digit years up to the current year are in this century
+	nik, err = validator.ParseNIK("3578011203050003")
+	assert.NoError(t, err)
+	assert.Equal(t, 2005, nik.BirthDate.Year())
+
+	invalid := map[string]string{
+		"TooShort":        "317101010190001",
+		"NotNumeric":      "31710101019A0001",
+		"UnknownProvince": "2071010101900001",
+		"ZeroRegency":     "3100010101900001",
+		"ZeroDistrict":    "3171000101900001",
+		"ZeroDay":         "3171010001900001",
+		"DayOver31":       "3171013201900001",
+		"FemaleDayOver31": "3171017201900001",
+		"Month13":         "3171010113900001",
+		"February30":      "3171013002900001",
+		"NotLeapYear":     "3171012902910001",
+		"ZeroSerial":      "3171010101900000",
+	}
+	for name, input := range invalid {
+		_, err := validator.ParseNIK(input)
+		assert.ErrorIs(t, err, validator.ErrInvalidNIK, name)
+	}
+
+	_, err = validator.ParseNIK("3171012902920001") // 1992 is a leap year
+	assert.NoError(t, err)
+}
+
+func TestNIK_MatchesBirthDate(t *testing.T) {
+	nik, err := validator.ParseNIK("3273015505920002")
+	assert.NoError(t, err)
+
+	assert.True(t, nik.MatchesBirthDate(time.Date(1992, 5, 15, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, nik.MatchesBirthDate(time.Date(1992, 5, 16, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, nik.MatchesBirthDate(time.Date(1992, 6, 15, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, nik.MatchesBirthDate(time.Date(1993, 5, 15, 0, 0, 0, 0, time.UTC)))
+}